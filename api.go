@@ -61,6 +61,7 @@ import (
 	"cmp"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"slices"
@@ -132,12 +133,41 @@ func ResolveContent(ctx context.Context, moduleContent string, opts ResolutionOp
 	return resolveInternal(ctx, moduleContent, opts)
 }
 
+// ResolveReader resolves dependencies from MODULE.bazel content read from r,
+// e.g. a file streamed from a VCS API. Errors reading r are wrapped as
+// "read module content" and errors parsing the content as "parse module
+// content", so the two can be told apart.
+//
+// Uses BCR by default if opts.Registries is empty.
+func ResolveReader(ctx context.Context, r io.Reader, opts ResolutionOptions) (*ResolutionList, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read module content: %w", err)
+	}
+	moduleInfo, err := parseModule("MODULE.bazel", content)
+	if err != nil {
+		return nil, fmt.Errorf("parse module content: %w", err)
+	}
+	return resolveParsed(ctx, moduleInfo, opts)
+}
+
 // resolveInternal is the internal implementation for content-based resolution.
 func resolveInternal(ctx context.Context, moduleContent string, opts ResolutionOptions) (*ResolutionList, error) {
 	moduleInfo, err := ParseModuleContent(moduleContent)
 	if err != nil {
 		return nil, fmt.Errorf("parse module content: %w", err)
 	}
+	return resolveParsed(ctx, moduleInfo, opts)
+}
+
+// resolveParsed resolves a root module parsed from content, which has no
+// directory of its own: includes are resolved against the working directory.
+func resolveParsed(ctx context.Context, moduleInfo *ModuleInfo, opts ResolutionOptions) (*ResolutionList, error) {
+	if opts.FollowIncludes {
+		if err := followIncludes(moduleInfo, "."); err != nil {
+			return nil, err
+		}
+	}
 
 	reg := registryFromOptions(opts)
 	resolver := newDependencyResolverWithOptions(reg, opts)
@@ -154,6 +184,11 @@ func ResolveFile(ctx context.Context, moduleFilePath string, opts ResolutionOpti
 	if err != nil {
 		return nil, fmt.Errorf("parse module file: %w", err)
 	}
+	if opts.FollowIncludes {
+		if err := followIncludes(moduleInfo, filepath.Dir(moduleFilePath)); err != nil {
+			return nil, err
+		}
+	}
 
 	reg := registryFromOptions(opts)
 	resolver := newDependencyResolverWithOptions(reg, opts)
@@ -232,7 +267,8 @@ func ResolveModule(ctx context.Context, name, version string, opts ResolutionOpt
 
 // resolveModuleInternal is the internal implementation for registry-based resolution.
 func resolveModuleInternal(ctx context.Context, name, version string, opts ResolutionOptions) (*ResolutionList, error) {
-	reg := registryFromOptions(opts)
+	resolver := newDependencyResolverWithOptions(registryFromOptions(opts), opts)
+	reg := resolver.registry
 
 	// Fetch the module's MODULE.bazel from registry
 	moduleInfo, err := reg.GetModuleFile(ctx, name, version)
@@ -250,7 +286,6 @@ func resolveModuleInternal(ctx context.Context, name, version string, opts Resol
 	}
 
 	// Resolve dependencies (treats moduleInfo as root)
-	resolver := newDependencyResolverWithOptions(reg, opts)
 	result, err := resolver.ResolveDependencies(ctx, moduleInfo)
 	if err != nil {
 		return nil, fmt.Errorf("resolve dependencies for %s@%s: %w", name, version, err)
@@ -258,6 +293,9 @@ func resolveModuleInternal(ctx context.Context, name, version string, opts Resol
 
 	// Determine the registry URL for the target module
 	registryURL := reg.BaseURL()
+	if vendored, ok := reg.(*vendorChain); ok {
+		reg = vendored.remote
+	}
 	if chain, ok := reg.(*registryChain); ok {
 		if moduleReg := chain.GetRegistryForModule(name); moduleReg != "" {
 			registryURL = moduleReg
@@ -361,10 +399,8 @@ func resolveModuleInternal(ctx context.Context, name, version string, opts Resol
 }
 
 // registryFromOptions creates a registry from ResolutionOptions.
-// Uses BCR if no registries are specified.
+// Uses BCR if no registries are specified. ModuleRegistries pins are applied
+// by newDependencyResolverWithOptions, not here.
 func registryFromOptions(opts ResolutionOptions) Registry {
-	if len(opts.Registries) == 0 {
-		return registryWithAllOptions(opts.HTTPClient, opts.Cache, opts.Timeout, opts.Logger)
-	}
 	return registryWithAllOptions(opts.HTTPClient, opts.Cache, opts.Timeout, opts.Logger, opts.Registries...)
 }
@@ -6,14 +6,18 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
+	"testing/iotest"
 	"time"
 )
 
@@ -405,6 +409,833 @@ func TestResolveFromContent_MVSSelection(t *testing.T) {
 	}
 }
 
+func TestResolutionList_RootRequestedVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/dep_a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "dep_a", version = "1.0.0")
+			bazel_dep(name = "shared_dep", version = "2.1.0")`)
+		case "/modules/shared_dep/2.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "shared_dep", version = "2.0.0")`)
+		case "/modules/shared_dep/2.1.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "shared_dep", version = "2.1.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "test_project", version = "1.0.0")
+	bazel_dep(name = "dep_a", version = "1.0.0")
+	bazel_dep(name = "shared_dep", version = "2.0.0")`
+
+	list, err := ResolveContent(context.Background(), content, ResolutionOptions{
+		Registries: []string{server.URL},
+	})
+	if err != nil {
+		t.Fatalf("ResolveContent() error = %v", err)
+	}
+
+	requested, ok := list.RootRequestedVersion("shared_dep")
+	if !ok {
+		t.Fatal("RootRequestedVersion(shared_dep) ok = false, want true")
+	}
+	if requested != "2.0.0" {
+		t.Errorf("RootRequestedVersion(shared_dep) = %q, want 2.0.0", requested)
+	}
+	if got := list.Module("shared_dep").Version; got != "2.1.0" {
+		t.Errorf("resolved shared_dep = %q, want 2.1.0", got)
+	}
+
+	if requested, ok := list.RootRequestedVersion("dep_a"); !ok || requested != "1.0.0" {
+		t.Errorf("RootRequestedVersion(dep_a) = (%q, %v), want (1.0.0, true)", requested, ok)
+	}
+
+	// Transitive-only modules are not requested by the root.
+	if requested, ok := list.RootRequestedVersion("unknown"); ok || requested != "" {
+		t.Errorf("RootRequestedVersion(unknown) = (%q, %v), want (\"\", false)", requested, ok)
+	}
+}
+
+func TestResolutionList_ValidateRootDeclarations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/dep_a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "dep_a", version = "1.0.0")`)
+		case "/modules/test_dep/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "test_dep", version = "1.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "test_project", version = "1.0.0")
+	bazel_dep(name = "dep_a", version = "1.0.0")
+	bazel_dep(name = "test_dep", version = "1.0.0", dev_dependency = True)`
+
+	list, err := ResolveContent(context.Background(), content, ResolutionOptions{
+		Registries:     []string{server.URL},
+		IncludeDevDeps: false,
+	})
+	if err != nil {
+		t.Fatalf("ResolveContent() error = %v", err)
+	}
+
+	missing, err := list.ValidateRootDeclarations(content)
+	if err != nil {
+		t.Fatalf("ValidateRootDeclarations() error = %v", err)
+	}
+	want := []string{"test_dep@1.0.0: not in resolution (dev_dependency)"}
+	if !slices.Equal(missing, want) {
+		t.Errorf("ValidateRootDeclarations() = %v, want %v", missing, want)
+	}
+
+	// A declaration that resolved lower than requested is also reported.
+	list.Modules = append(list.Modules, ModuleToResolve{Name: "test_dep", Version: "0.9.0"})
+	missing, err = list.ValidateRootDeclarations(content)
+	if err != nil {
+		t.Fatalf("ValidateRootDeclarations() error = %v", err)
+	}
+	want = []string{"test_dep@1.0.0: resolved to older version 0.9.0"}
+	if !slices.Equal(missing, want) {
+		t.Errorf("ValidateRootDeclarations() = %v, want %v", missing, want)
+	}
+
+	if _, err := list.ValidateRootDeclarations("bazel_dep("); err == nil {
+		t.Error("ValidateRootDeclarations() expected error for invalid content")
+	}
+}
+
+func TestResolve_ExtensionRepos(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/rules_go/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "rules_go", version = "1.0.0")
+go_sdk = use_extension("//go:extensions.bzl", "go_sdk")
+use_repo(go_sdk, "go_toolchains")
+dev = use_extension("//go:extensions.bzl", "dev", dev_dependency = True)
+use_repo(dev, "go_dev_only")`)
+		case "/modules/skylib/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "skylib", version = "1.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "rules_go", version = "1.0.0")
+bazel_dep(name = "skylib", version = "1.0.0")
+
+go_deps = use_extension("@gazelle//:extensions.bzl", "go_deps")
+go_deps.from_file(go_mod = "//:go.mod")
+use_repo(go_deps, "com_github_google_uuid", yaml = "in_gopkg_yaml_v3")
+
+tools = use_extension("//:tools.bzl", "tools", dev_dependency = True)
+use_repo(tools, "buildifier")`
+
+	tests := []struct {
+		name string
+		opts []Option
+		want map[string][]string
+	}{
+		{
+			name: "without dev deps",
+			opts: []Option{WithRegistries(server.URL)},
+			want: map[string][]string{
+				"root":     {"com_github_google_uuid", "yaml"},
+				"rules_go": {"go_toolchains"},
+			},
+		},
+		{
+			name: "with dev deps",
+			opts: []Option{WithRegistries(server.URL), WithDevDeps()},
+			want: map[string][]string{
+				"root":     {"com_github_google_uuid", "yaml", "buildifier"},
+				"rules_go": {"go_toolchains"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := Resolve(context.Background(), ContentSource(content), tt.opts...)
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if len(list.ExtensionRepos) != len(tt.want) {
+				t.Fatalf("ExtensionRepos = %v, want %v", list.ExtensionRepos, tt.want)
+			}
+			for module, want := range tt.want {
+				if got := list.ExtensionRepos[module]; !slices.Equal(got, want) {
+					t.Errorf("ExtensionRepos[%s] = %v, want %v", module, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestResolve_PreOneMinorBumpWarnings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/dep_a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "dep_a", version = "1.0.0")
+bazel_dep(name = "young_lib", version = "0.2.0")
+bazel_dep(name = "patch_lib", version = "0.3.1")`)
+		case "/modules/young_lib/0.1.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "young_lib", version = "0.1.0")`)
+		case "/modules/young_lib/0.2.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "young_lib", version = "0.2.0")`)
+		case "/modules/patch_lib/0.3.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "patch_lib", version = "0.3.0")`)
+		case "/modules/patch_lib/0.3.1/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "patch_lib", version = "0.3.1")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "dep_a", version = "1.0.0")
+bazel_dep(name = "young_lib", version = "0.1.0")
+bazel_dep(name = "patch_lib", version = "0.3.0")`
+
+	list, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithPreOneMinorBumpWarnings(true))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	want := []string{"module young_lib: selected 0.2.0 crosses a 0.x minor boundary from 0.1.0 requested by <root>"}
+	if !slices.Equal(list.Summary.PreOneMinorBumps, want) {
+		t.Errorf("Summary.PreOneMinorBumps = %v, want %v", list.Summary.PreOneMinorBumps, want)
+	}
+	if !slices.Contains(list.Warnings, want[0]) {
+		t.Errorf("Warnings = %v, want to contain %q", list.Warnings, want[0])
+	}
+
+	// The check is advisory and off by default.
+	list, err = Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if len(list.Summary.PreOneMinorBumps) != 0 || len(list.Warnings) != 0 {
+		t.Errorf("warnings without option: summary %v, warnings %v", list.Summary.PreOneMinorBumps, list.Warnings)
+	}
+}
+
+func TestResolve_PreOneMinorBumpWarningsMultipleVersionOverride(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/young_lib/0.1.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "young_lib", version = "0.1.0")`)
+		case "/modules/young_lib/0.2.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "young_lib", version = "0.2.0")`)
+		case "/modules/young_lib/0.3.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "young_lib", version = "0.3.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// 0.1.0 and 0.3.0 are only prefetched for the override; nobody requested
+	// them, so nothing was bumped across a minor.
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "young_lib", version = "0.2.0")
+multiple_version_override(module_name = "young_lib", versions = ["0.1.0", "0.2.0", "0.3.0"])`
+
+	list, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithPreOneMinorBumpWarnings(true))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if len(list.Summary.PreOneMinorBumps) != 0 {
+		t.Errorf("Summary.PreOneMinorBumps = %v, want none", list.Summary.PreOneMinorBumps)
+	}
+}
+
+func TestResolve_SoftTimeBudget(t *testing.T) {
+	const budget = 50 * time.Millisecond
+
+	// The budget runs out only when the slow fetch advances the clock.
+	start := time.Now()
+	var elapsed atomic.Int64
+	timeNow = func() time.Time { return start.Add(time.Duration(elapsed.Load())) }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	var unexploredFetched atomic.Bool
+	fastDone := make(chan struct{})
+	var fastOnce sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/fast/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "fast", version = "1.0.0")
+bazel_dep(name = "fast_child", version = "1.0.0")`)
+		case "/modules/fast_child/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "fast_child", version = "1.0.0")`)
+			fastOnce.Do(func() { close(fastDone) })
+		case "/modules/slow/1.0.0/MODULE.bazel":
+			// In flight when the budget runs out; must still complete.
+			select {
+			case <-fastDone:
+			case <-r.Context().Done():
+				return
+			}
+			elapsed.Store(int64(2 * budget))
+			fmt.Fprint(w, `module(name = "slow", version = "1.0.0")
+bazel_dep(name = "slow_child", version = "1.0.0")
+bazel_dep(name = "fast_child", version = "1.1.0")`)
+		case "/modules/slow_child/1.0.0/MODULE.bazel", "/modules/fast_child/1.1.0/MODULE.bazel":
+			unexploredFetched.Store(true)
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "fast", version = "1.0.0")
+bazel_dep(name = "slow", version = "1.0.0")`
+
+	list, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithSoftTimeBudget(budget))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	if !list.Summary.Partial {
+		t.Error("Summary.Partial = false, want true")
+	}
+	if unexploredFetched.Load() {
+		t.Error("module discovered after the budget ran out was fetched")
+	}
+
+	// The in-flight fetch finished, so slow's requests still take part in MVS.
+	if m := list.Module("slow"); m == nil || !slices.Contains(m.Dependencies, "slow_child") {
+		t.Errorf("slow = %+v, want its dependencies recorded", m)
+	}
+	if m := list.Module("fast_child"); m == nil || m.Version != "1.1.0" {
+		t.Errorf("fast_child = %+v, want 1.1.0 selected from slow's request", m)
+	}
+	wantUnexplored := []string{"fast_child@1.1.0", "slow_child@1.0.0"}
+	if !slices.Equal(list.Summary.UnexploredModules, wantUnexplored) {
+		t.Errorf("Summary.UnexploredModules = %v, want %v", list.Summary.UnexploredModules, wantUnexplored)
+	}
+	if len(list.Warnings) == 0 || !strings.Contains(list.Warnings[0], "soft time budget") {
+		t.Errorf("Warnings = %v, want soft time budget warning", list.Warnings)
+	}
+
+	// A generous budget behaves like a full resolution.
+	list, err = Resolve(context.Background(), ContentSource(`module(name = "root", version = "1.0.0")
+bazel_dep(name = "fast", version = "1.0.0")`), WithRegistries(server.URL), WithSoftTimeBudget(time.Minute))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if list.Summary.Partial || len(list.Summary.UnexploredModules) != 0 {
+		t.Errorf("Summary = %+v, want complete resolution", list.Summary)
+	}
+}
+
+func TestResolve_MaxConcurrentFetches(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := strings.CutPrefix(r.URL.Path, "/modules/")
+		if !ok || !strings.HasSuffix(name, "/1.0.0/MODULE.bazel") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprintf(w, `module(name = %q, version = "1.0.0")`, strings.TrimSuffix(name, "/1.0.0/MODULE.bazel"))
+	}))
+	defer server.Close()
+
+	var content strings.Builder
+	content.WriteString(`module(name = "root", version = "1.0.0")` + "\n")
+	for i := range 8 {
+		fmt.Fprintf(&content, "bazel_dep(name = \"dep%d\", version = \"1.0.0\")\n", i)
+	}
+
+	var mu sync.Mutex
+	events := make(map[ProgressEventType]int)
+	list, err := Resolve(context.Background(), ContentSource(content.String()),
+		WithRegistries(server.URL),
+		WithMaxConcurrentFetches(2),
+		WithProgress(func(e ProgressEvent) {
+			mu.Lock()
+			events[e.Type]++
+			mu.Unlock()
+		}))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	if len(list.Modules) != 8 {
+		t.Errorf("resolved %d modules, want 8", len(list.Modules))
+	}
+	if got := peak.Load(); got > 2 {
+		t.Errorf("peak concurrent fetches = %d, want <= 2", got)
+	}
+	if events[ProgressModuleFetchStart] != 8 || events[ProgressModuleFetchEnd] != 8 {
+		t.Errorf("fetch events = %d start, %d end; want 8 each",
+			events[ProgressModuleFetchStart], events[ProgressModuleFetchEnd])
+	}
+}
+
+func TestResolve_TotalTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/fast/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "fast", version = "1.0.0")
+bazel_dep(name = "slow", version = "1.0.0")`)
+		case "/modules/slow/1.0.0/MODULE.bazel":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			fmt.Fprint(w, `module(name = "slow", version = "1.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "fast", version = "1.0.0")`
+
+	_, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithTotalTimeout(100*time.Millisecond))
+	var timeoutErr *ResolutionTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("Resolve() error = %v, want ResolutionTimeoutError", err)
+	}
+	if timeoutErr.Timeout != 100*time.Millisecond || timeoutErr.ModulesResolved != 1 {
+		t.Errorf("ResolutionTimeoutError = %+v, want 100ms timeout with 1 module resolved", timeoutErr)
+	}
+	if !errors.Is(err, ErrResolutionTimeout) {
+		t.Error("errors.Is(err, ErrResolutionTimeout) = false, want true")
+	}
+
+	// A deadline of the caller's own is not reported as a TotalTimeout.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = Resolve(ctx, ContentSource(content),
+		WithRegistries(server.URL), WithTotalTimeout(time.Minute))
+	if err == nil || errors.Is(err, ErrResolutionTimeout) {
+		t.Errorf("Resolve() with expired caller context error = %v, want a non-timeout error", err)
+	}
+}
+
+func TestResolve_TotalTimeoutDuringMetadataChecks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/dep/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "dep", version = "1.0.0")`)
+		case "/modules/dep/metadata.json":
+			// The yanked version is never reported: the deadline hits first.
+			<-r.Context().Done()
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "dep", version = "1.0.0")`
+
+	list, err := Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL),
+		WithYankedCheck(true), WithYankedBehavior(YankedVersionError), WithTotalTimeout(100*time.Millisecond))
+	var timeoutErr *ResolutionTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("Resolve() = %v, %v, want ResolutionTimeoutError", list, err)
+	}
+	if list != nil {
+		t.Errorf("Resolve() returned a result with the timeout error: %+v", list)
+	}
+	if timeoutErr.ModulesResolved != 1 {
+		t.Errorf("ModulesResolved = %d, want 1", timeoutErr.ModulesResolved)
+	}
+}
+
+func TestResolve_ExcludeModules(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/app/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "app", version = "1.0.0")
+bazel_dep(name = "legacy", version = "1.0.0")
+bazel_dep(name = "shared", version = "1.0.0")`)
+		case "/modules/legacy/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "legacy", version = "1.0.0")
+bazel_dep(name = "legacy_only", version = "1.0.0")
+bazel_dep(name = "shared", version = "2.0.0")`)
+		case "/modules/legacy_only/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "legacy_only", version = "1.0.0")`)
+		case "/modules/shared/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "shared", version = "1.0.0")`)
+		case "/modules/shared/2.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "shared", version = "2.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "app", version = "1.0.0")`
+
+	list, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithExcludeModules("legacy", "unrelated"))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if !slices.Equal(list.Summary.ExcludedModules, []string{"legacy"}) ||
+		!slices.Equal(list.Summary.UnusedExclusions, []string{"unrelated"}) {
+		t.Errorf("Summary exclusions = %v applied, %v unused; want [legacy], [unrelated]",
+			list.Summary.ExcludedModules, list.Summary.UnusedExclusions)
+	}
+
+	var got []string
+	for _, m := range list.Modules {
+		got = append(got, m.Key())
+	}
+	// legacy and its exclusive subtree are gone, and so is its request for
+	// shared 2.0.0.
+	want := []string{"app@1.0.0", "shared@1.0.0"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Modules = %v, want %v", got, want)
+	}
+	if m := list.Module("app"); m == nil || !slices.Equal(m.Dependencies, []string{"shared"}) {
+		t.Errorf("app = %+v, want only shared as a dependency", m)
+	}
+
+	// A direct dependency of the root cannot be excluded.
+	_, err = Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithExcludeModules("app"))
+	if err == nil || !strings.Contains(err.Error(), "cannot exclude module app") {
+		t.Errorf("Resolve() excluding a root dependency: error = %v, want cannot exclude", err)
+	}
+}
+
+func TestResolve_VersionPins(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/app/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "app", version = "1.0.0")
+bazel_dep(name = "shared", version = "1.0.0")`)
+		case "/modules/app/2.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "app", version = "2.0.0")
+bazel_dep(name = "shared", version = "2.0.0")`)
+		case "/modules/extra/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "extra", version = "1.0.0")`)
+		case "/modules/shared/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "shared", version = "1.0.0")`)
+		case "/modules/shared/2.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "shared", version = "2.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "app", version = "1.0.0")`
+
+	before, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithDeterministic())
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	after, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithDeterministic(),
+		WithVersionPins(map[string]string{"app": "2.0.0", "extra": "1.0.0"}))
+	if err != nil {
+		t.Fatalf("Resolve() with pins error = %v", err)
+	}
+
+	var got []string
+	for _, m := range after.Modules {
+		got = append(got, m.Key())
+	}
+	want := []string{"app@2.0.0", "extra@1.0.0", "shared@2.0.0"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Modules = %v, want %v", got, want)
+	}
+	if m := after.Module("extra"); m == nil || m.DevDependency {
+		t.Errorf("extra = %+v, want a non-dev dependency", m)
+	}
+
+	diff := DiffResolutions(before, after)
+	if len(diff.Upgraded) != 2 || len(diff.Added) != 1 {
+		t.Errorf("diff = %d upgraded, %d added; want 2 and 1", len(diff.Upgraded), len(diff.Added))
+	}
+
+	if _, err := Resolve(context.Background(), ContentSource(content),
+		WithVersionPins(map[string]string{"app": ""})); err == nil {
+		t.Error("Resolve() with an empty pinned version succeeded, want error")
+	}
+}
+
+func TestResolve_DependencyApproval(t *testing.T) {
+	var fetchedRejected atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/app/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "app", version = "1.0.0")
+bazel_dep(name = "shared", version = "1.0.0")
+bazel_dep(name = "untrusted", version = "0.1.0")`)
+		case "/modules/lib/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "lib", version = "1.0.0")
+bazel_dep(name = "shared", version = "1.0.0")`)
+		case "/modules/shared/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "shared", version = "1.0.0")`)
+		case "/modules/untrusted/0.1.0/MODULE.bazel":
+			fetchedRejected.Store(true)
+			fmt.Fprint(w, `module(name = "untrusted", version = "0.1.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "app", version = "1.0.0")
+bazel_dep(name = "lib", version = "1.0.0")`
+
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	approveAll := func(name, version, requester string) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[name+"@"+version]++
+		return true, nil
+	}
+
+	if _, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithDependencyApproval(approveAll)); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	// shared is requested by both app and lib but approved only once.
+	want := map[string]int{"app@1.0.0": 1, "lib@1.0.0": 1, "shared@1.0.0": 1, "untrusted@0.1.0": 1}
+	if !maps.Equal(calls, want) {
+		t.Errorf("approval calls = %v, want %v", calls, want)
+	}
+
+	fetchedRejected.Store(false)
+	rejectUntrusted := func(name, version, requester string) (bool, error) {
+		return name != "untrusted", nil
+	}
+	_, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithDependencyApproval(rejectUntrusted))
+	var rejected *DependencyRejectedError
+	if !errors.As(err, &rejected) {
+		t.Fatalf("Resolve() error = %v, want DependencyRejectedError", err)
+	}
+	if rejected.Module != "untrusted" || rejected.Version != "0.1.0" || rejected.Requester != "app@1.0.0" {
+		t.Errorf("rejection = %+v, want untrusted@0.1.0 requested by app@1.0.0", rejected)
+	}
+	if fetchedRejected.Load() {
+		t.Error("rejected dependency was fetched")
+	}
+
+	// An error from the callback aborts resolution.
+	boom := errors.New("policy service unavailable")
+	_, err = Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithDependencyApproval(func(string, string, string) (bool, error) {
+			return false, boom
+		}))
+	if !errors.Is(err, boom) {
+		t.Errorf("Resolve() error = %v, want %v", err, boom)
+	}
+}
+
+func TestResolutionList_DevOnlyModules(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/app_lib/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "app_lib", version = "1.0.0")
+bazel_dep(name = "shared", version = "1.0.0")`)
+		case "/modules/test_framework/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "test_framework", version = "1.0.0")
+bazel_dep(name = "mock_lib", version = "1.0.0")
+bazel_dep(name = "shared", version = "1.0.0")`)
+		case "/modules/mock_lib/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "mock_lib", version = "1.0.0")`)
+		case "/modules/shared/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "shared", version = "1.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "app_lib", version = "1.0.0")
+bazel_dep(name = "test_framework", version = "1.0.0", dev_dependency = True)`
+
+	list, err := Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL), WithDevDeps())
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	// mock_lib is only reachable through the dev dependency, although its own
+	// DevDependency flag is false; shared is also reachable via app_lib.
+	want := []string{"mock_lib", "test_framework"}
+	if got := list.DevOnlyModules(); !slices.Equal(got, want) {
+		t.Errorf("DevOnlyModules() = %v, want %v", got, want)
+	}
+	if list.Module("mock_lib").DevDependency {
+		t.Error("mock_lib DevDependency = true; test no longer covers transitive reachability")
+	}
+
+	// Without root information, the direct dependency flags are used.
+	decoded := &ResolutionList{Modules: list.Modules}
+	if got := decoded.DevOnlyModules(); !slices.Equal(got, want) {
+		t.Errorf("DevOnlyModules() without root = %v, want %v", got, want)
+	}
+
+	list, err = Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if got := list.DevOnlyModules(); len(got) != 0 {
+		t.Errorf("DevOnlyModules() without dev deps = %v, want none", got)
+	}
+}
+
+func TestResolve_MultipleVersionOverridePrefetchesAllowedVersions(t *testing.T) {
+	var fetchedUnreachable, fetchedExtra atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/dep_a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "dep_a", version = "1.0.0")
+bazel_dep(name = "lib", version = "1.0.0")`)
+		case "/modules/dep_b/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "dep_b", version = "1.0.0")
+bazel_dep(name = "lib", version = "2.0.0")`)
+		case "/modules/lib/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "lib", version = "1.0.0")`)
+		case "/modules/lib/2.0.0/MODULE.bazel":
+			fetchedUnreachable.Add(1)
+			fmt.Fprint(w, `module(name = "lib", version = "2.0.0")
+bazel_dep(name = "extra", version = "1.0.0")`)
+		case "/modules/extra/1.0.0/MODULE.bazel":
+			fetchedExtra.Add(1)
+			fmt.Fprint(w, `module(name = "extra", version = "1.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	versionOf := func(modules []ModuleToResolve, name string) []string {
+		var versions []string
+		for _, m := range modules {
+			if m.Name == name {
+				versions = append(versions, m.Version)
+			}
+		}
+		return versions
+	}
+
+	t.Run("unreachable version is fetched but not selected", func(t *testing.T) {
+		fetchedUnreachable.Store(0)
+		fetchedExtra.Store(0)
+		// The override omits registry, so allowed versions come from the default chain.
+		content := `module(name = "test", version = "1.0.0")
+bazel_dep(name = "dep_a", version = "1.0.0")
+multiple_version_override(module_name = "lib", versions = ["1.0.0", "2.0.0"])`
+
+		result, err := Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL))
+		if err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		if got := fetchedUnreachable.Load(); got != 1 {
+			t.Errorf("lib@2.0.0 fetched %d times, want 1", got)
+		}
+		if got := fetchedExtra.Load(); got != 0 {
+			t.Errorf("extra@1.0.0 fetched %d times, want 0: deps of an unrequested prefetch must not be followed", got)
+		}
+		if got := versionOf(result.Modules, "lib"); !slices.Equal(got, []string{"1.0.0"}) {
+			t.Errorf("resolved lib versions = %v, want [1.0.0]", got)
+		}
+		if got := versionOf(result.UnprunedModules, "lib"); !slices.Equal(got, []string{"1.0.0", "2.0.0"}) {
+			t.Errorf("unpruned lib versions = %v, want [1.0.0 2.0.0]", got)
+		}
+	})
+
+	t.Run("requested version is selected with its deps", func(t *testing.T) {
+		fetchedUnreachable.Store(0)
+		fetchedExtra.Store(0)
+		content := `module(name = "test", version = "1.0.0")
+bazel_dep(name = "dep_a", version = "1.0.0")
+bazel_dep(name = "dep_b", version = "1.0.0")
+multiple_version_override(module_name = "lib", versions = ["1.0.0", "2.0.0"])`
+
+		result, err := Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL))
+		if err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		if got := fetchedUnreachable.Load(); got != 1 {
+			t.Errorf("lib@2.0.0 fetched %d times, want 1", got)
+		}
+		if got := versionOf(result.Modules, "lib"); !slices.Contains(got, "2.0.0") {
+			t.Errorf("resolved lib versions = %v, want lib@2.0.0 among them", got)
+		}
+		if !result.HasModule("extra") {
+			t.Error("extra should be resolved through the requested lib@2.0.0")
+		}
+	})
+}
+
+func TestResolve_IgnoredNonRootOverrides(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/mid/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "mid", version = "1.0.0")
+bazel_dep(name = "shared", version = "1.0.0")
+single_version_override(module_name = "shared", version = "2.0.0")
+git_override(module_name = "other", remote = "https://example.com/other.git", commit = "abc")`)
+		case "/modules/shared/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "shared", version = "1.0.0")`)
+		case "/modules/shared/2.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "shared", version = "2.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "mid", version = "1.0.0")`
+
+	list, err := Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	if got := list.Module("shared").Version; got != "1.0.0" {
+		t.Errorf("shared = %s, want 1.0.0 (non-root override must be ignored)", got)
+	}
+	want := []string{
+		"mid@1.0.0: single_version_override(shared)",
+		"mid@1.0.0: git_override(other)",
+	}
+	if !slices.Equal(list.Summary.IgnoredNonRootOverrides, want) {
+		t.Errorf("Summary.IgnoredNonRootOverrides = %v, want %v", list.Summary.IgnoredNonRootOverrides, want)
+	}
+}
+
 func TestResolveFromContent_EmptyModule(t *testing.T) {
 	content := `module(name = "empty_project", version = "1.0.0")`
 
@@ -809,6 +1640,22 @@ bazel_dep(name = "module_a", version = "1.0.0")`),
 			t.Fatalf("RegistryFileHashes[%s] = %q, want %q", url, *got, *want)
 		}
 	}
+
+	// Each module carries the entries for its own registry files.
+	wantModuleA := map[string]*string{
+		server1.URL + "/modules/module_a/1.0.0/MODULE.bazel": nil,
+		server2.URL + "/modules/module_a/1.0.0/MODULE.bazel": testSHA256Hex(moduleA),
+		server2.URL + "/modules/module_a/1.0.0/source.json":  testSHA256Hex(sourceA),
+	}
+	if len(moduleANode.RegistryFileHashes) != len(wantModuleA) {
+		t.Fatalf("module_a RegistryFileHashes = %v, want %d entries", moduleANode.RegistryFileHashes, len(wantModuleA))
+	}
+	for url, want := range wantModuleA {
+		got, ok := moduleANode.RegistryFileHashes[url]
+		if !ok || !sameHash(got, want) {
+			t.Errorf("module_a RegistryFileHashes[%s] = %v, %v; want %v", url, got, ok, want)
+		}
+	}
 }
 
 func TestResolve_RegistryTrace_RegistryOverrideUsesOverrideRegistry(t *testing.T) {
@@ -1163,6 +2010,41 @@ bazel_dep(name = "file_dep", version = "1.0.0")`
 	}
 }
 
+// TestResolveReader tests resolving content streamed from an io.Reader
+func TestResolveReader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `module(name = "reader_dep", version = "1.0.0")`)
+	}))
+	defer server.Close()
+
+	content := `module(name = "reader_test", version = "1.0.0")
+bazel_dep(name = "reader_dep", version = "1.0.0")`
+
+	ctx := context.Background()
+	result, err := ResolveReader(ctx, strings.NewReader(content), ResolutionOptions{
+		Registries: []string{server.URL},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Modules) != 1 || result.Modules[0].Name != "reader_dep" {
+		t.Errorf("Modules = %+v, want reader_dep only", result.Modules)
+	}
+
+	// Read errors are reported as such, wrapping the reader's error.
+	readErr := errors.New("connection reset")
+	_, err = ResolveReader(ctx, io.MultiReader(strings.NewReader(content), iotest.ErrReader(readErr)), ResolutionOptions{})
+	if !errors.Is(err, readErr) || !strings.Contains(err.Error(), "read module content") {
+		t.Errorf("ResolveReader() with failing reader error = %v, want read error", err)
+	}
+
+	// Parse errors are distinct from read errors.
+	_, err = ResolveReader(ctx, strings.NewReader("bazel_dep(name = "), ResolutionOptions{})
+	if err == nil || !strings.Contains(err.Error(), "parse module content") {
+		t.Errorf("ResolveReader() with bad content error = %v, want parse error", err)
+	}
+}
+
 func TestResolveFile_LocalPathOverrideHydratesModuleFromDisk(t *testing.T) {
 	var localFetchCount atomic.Int32
 
@@ -2313,3 +3195,96 @@ bazel_dep(name = "mmm_dep", version = "1.0.0")`)
 		t.Errorf("Modules list is not sorted by name: %v", names)
 	}
 }
+
+func TestResolve_RegistryInconsistency(t *testing.T) {
+	var metadataFetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/app/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "app", version = "1.0.0")
+bazel_dep(name = "foo", version = "2.0.0")
+bazel_dep(name = "bar", version = "1.0.0")`)
+		case "/modules/lib/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "lib", version = "1.0.0")
+bazel_dep(name = "bar", version = "2.0.0")`)
+		case "/modules/bar/2.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "bar", version = "2.0.0")`)
+		case "/modules/foo/metadata.json":
+			// metadata.json lists 2.0.0, but its MODULE.bazel is missing.
+			metadataFetches.Add(1)
+			fmt.Fprint(w, `{"versions": ["1.0.0", "2.0.0"]}`)
+		case "/modules/bar/metadata.json":
+			metadataFetches.Add(1)
+			fmt.Fprint(w, `{"versions": ["1.0.0", "2.0.0"]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// A transitive inconsistency drops the version with a warning. bar@1.0.0
+	// is missing too, but MVS selects bar@2.0.0 anyway, so its metadata is
+	// never consulted.
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "app", version = "1.0.0")
+bazel_dep(name = "lib", version = "1.0.0")`
+
+	list, err := Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if want := []string{"foo@2.0.0"}; !slices.Equal(list.Summary.RegistryInconsistencies, want) {
+		t.Errorf("Summary.RegistryInconsistencies = %v, want %v", list.Summary.RegistryInconsistencies, want)
+	}
+	want := "metadata lists foo@2.0.0 but its MODULE.bazel is missing"
+	if !slices.ContainsFunc(list.Warnings, func(w string) bool { return strings.Contains(w, want) }) {
+		t.Errorf("Warnings = %v, want one containing %q", list.Warnings, want)
+	}
+	if got := metadataFetches.Load(); got != 1 {
+		t.Errorf("metadata.json fetched %d times, want 1 (foo only)", got)
+	}
+
+	// A direct dependency of the root fails resolution.
+	content = `module(name = "root", version = "1.0.0")
+bazel_dep(name = "foo", version = "2.0.0")`
+
+	_, err = Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL))
+	var inconsistentErr *RegistryInconsistencyError
+	if !errors.As(err, &inconsistentErr) {
+		t.Fatalf("Resolve() error = %v, want *RegistryInconsistencyError", err)
+	}
+	if inconsistentErr.Module != "foo" || inconsistentErr.Version != "2.0.0" {
+		t.Errorf("error names %s@%s, want foo@2.0.0", inconsistentErr.Module, inconsistentErr.Version)
+	}
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want it to contain %q", err, want)
+	}
+}
+
+func TestResolve_MissingVersionNotInMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/app/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "app", version = "1.0.0")
+bazel_dep(name = "foo", version = "2.0.0")`)
+		case "/modules/foo/metadata.json":
+			fmt.Fprint(w, `{"versions": ["1.0.0"]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "app", version = "1.0.0")`
+
+	// A transitive version the registry genuinely does not have is dropped,
+	// as before.
+	list, err := Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if list.HasModule("foo") {
+		t.Error("foo should not be resolved")
+	}
+}
@@ -0,0 +1,113 @@
+package gobzlmod
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ArchiveOverrideCheck reports whether an archive_override can be downloaded.
+//
+// Reference: archive_override URLs are passed to http_archive, which treats
+// them as mirrors and tries each in order until one succeeds. An override is
+// therefore only broken when every URL fails.
+// See: https://bazel.build/rules/lib/repo/http#http_archive-urls
+type ArchiveOverrideCheck struct {
+	// ModuleName is the name of the overridden module.
+	ModuleName string `json:"module_name"`
+
+	// URLs are the override's mirrors in declaration order.
+	URLs []string `json:"urls"`
+
+	// SelectedURL is the first reachable URL, i.e. the one Bazel would
+	// download from. Empty if no URL is reachable.
+	SelectedURL string `json:"selected_url,omitempty"`
+
+	// Failures maps each URL that was tried and failed to the reason.
+	// URLs after SelectedURL are not tried.
+	Failures map[string]string `json:"failures,omitempty"`
+}
+
+// Broken returns true if none of the override's URLs are reachable.
+func (c ArchiveOverrideCheck) Broken() bool {
+	return c.SelectedURL == ""
+}
+
+// CheckArchiveOverrides probes the URLs of every archive_override declared by
+// module and reports which URL would be used for each.
+//
+// URLs are probed in order with a HEAD request (falling back to GET for servers
+// that reject HEAD), stopping at the first one that answers with a 2xx status.
+// The HTTP client and timeout are taken from WithHTTPClient and WithTimeout.
+func CheckArchiveOverrides(ctx context.Context, module *ModuleInfo, opts ...Option) ([]ArchiveOverrideCheck, error) {
+	if module == nil {
+		return nil, fmt.Errorf("module is nil")
+	}
+	cfg, err := newResolverConfig(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	client := cfg.httpClient
+	if client == nil {
+		client = &http.Client{Timeout: cfg.timeout}
+	}
+
+	var checks []ArchiveOverrideCheck
+	for _, override := range module.Overrides {
+		if override.Type != overrideTypeArchive {
+			continue
+		}
+		check := ArchiveOverrideCheck{
+			ModuleName: override.ModuleName,
+			URLs:       override.URLs,
+		}
+		for _, url := range override.URLs {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if err := probeURL(ctx, client, url); err != nil {
+				if check.Failures == nil {
+					check.Failures = make(map[string]string)
+				}
+				check.Failures[url] = err.Error()
+				continue
+			}
+			check.SelectedURL = url
+			break
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// probeURL checks that url answers with a 2xx status without downloading it.
+func probeURL(ctx context.Context, client *http.Client, url string) error {
+	status, err := doProbe(ctx, client, http.MethodHead, url)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented {
+		status, err = doProbe(ctx, client, http.MethodGet, url)
+		if err != nil {
+			return err
+		}
+	}
+	if status < 200 || status > 299 {
+		return fmt.Errorf("HTTP %d", status)
+	}
+	return nil
+}
+
+func doProbe(ctx context.Context, client *http.Client, method, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,80 @@
+package gobzlmod
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckArchiveOverrides(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok.zip":
+			w.WriteHeader(http.StatusOK)
+		case "/head-not-allowed.zip":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	module := &ModuleInfo{
+		Name: "root",
+		Overrides: []Override{
+			{Type: overrideTypeArchive, ModuleName: "mirrored", URLs: []string{
+				server.URL + "/dead.zip",
+				server.URL + "/ok.zip",
+				server.URL + "/never-tried.zip",
+			}},
+			{Type: overrideTypeArchive, ModuleName: "broken", URLs: []string{
+				server.URL + "/dead.zip",
+				"http://127.0.0.1:0/unreachable.zip",
+			}},
+			{Type: overrideTypeArchive, ModuleName: "get_only", URLs: []string{server.URL + "/head-not-allowed.zip"}},
+			{Type: overrideTypeGit, ModuleName: "ignored"},
+		},
+	}
+
+	checks, err := CheckArchiveOverrides(context.Background(), module)
+	if err != nil {
+		t.Fatalf("CheckArchiveOverrides() error = %v", err)
+	}
+	if len(checks) != 3 {
+		t.Fatalf("len(checks) = %d, want 3", len(checks))
+	}
+
+	mirrored := checks[0]
+	if mirrored.Broken() {
+		t.Error("mirrored override flagged as broken although its second URL works")
+	}
+	if mirrored.SelectedURL != server.URL+"/ok.zip" {
+		t.Errorf("SelectedURL = %q, want %q", mirrored.SelectedURL, server.URL+"/ok.zip")
+	}
+	if _, ok := mirrored.Failures[server.URL+"/dead.zip"]; !ok || len(mirrored.Failures) != 1 {
+		t.Errorf("Failures = %v, want only the dead URL", mirrored.Failures)
+	}
+
+	broken := checks[1]
+	if !broken.Broken() {
+		t.Error("override with only dead URLs not flagged as broken")
+	}
+	if len(broken.Failures) != 2 {
+		t.Errorf("Failures = %v, want both URLs", broken.Failures)
+	}
+
+	if checks[2].Broken() {
+		t.Errorf("override served only via GET flagged as broken: %v", checks[2].Failures)
+	}
+}
+
+func TestCheckArchiveOverrides_NilModule(t *testing.T) {
+	if _, err := CheckArchiveOverrides(context.Background(), nil); err == nil {
+		t.Error("expected error for nil module")
+	}
+}
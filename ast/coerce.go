@@ -0,0 +1,151 @@
+package ast
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/albertocavalcante/go-bzlmod/label"
+	"github.com/albertocavalcante/go-bzlmod/third_party/buildtools/build"
+)
+
+// AttrType is the expected Starlark type of an extension tag attribute.
+type AttrType int
+
+const (
+	// AttrAny leaves the attribute untyped (the default behavior).
+	AttrAny AttrType = iota
+	// AttrString requires a string literal. Coerces to string.
+	AttrString
+	// AttrInt requires an integer literal. Coerces to int.
+	AttrInt
+	// AttrBool requires True or False. Coerces to bool.
+	AttrBool
+	// AttrLabel requires a string literal holding a valid label.
+	// Coerces to label.ApparentLabel.
+	AttrLabel
+	// AttrStringList requires a list of string literals. Coerces to []string.
+	AttrStringList
+)
+
+// String returns the Starlark-style name of the type.
+func (t AttrType) String() string {
+	switch t {
+	case AttrString:
+		return "string"
+	case AttrInt:
+		return "int"
+	case AttrBool:
+		return "bool"
+	case AttrLabel:
+		return "label"
+	case AttrStringList:
+		return "list of strings"
+	default:
+		return "any"
+	}
+}
+
+// AttrSchema maps extension tag attribute names to their expected types.
+// Attributes not in the schema keep their untyped value.
+type AttrSchema map[string]AttrType
+
+// ParseOption configures ParseFile and ParseContent.
+type ParseOption func(*Parser)
+
+// WithAttrSchema coerces extension tag attributes named in schema to their
+// declared types. Mismatches are reported as errors in ParseResult.Errors
+// with the position of the offending value, and the attribute keeps its
+// untyped value.
+//
+// Coercion is strict, matching Bazel's attribute checking: version = 1.21
+// does not satisfy AttrString, and count = "3" does not satisfy AttrInt.
+func WithAttrSchema(schema AttrSchema) ParseOption {
+	return func(p *Parser) {
+		p.schema = schema
+	}
+}
+
+// coerceAttr converts expr to the Go representation of want.
+// Returns an error describing the mismatch if expr has the wrong type.
+func coerceAttr(expr build.Expr, want AttrType) (any, error) {
+	switch want {
+	case AttrString:
+		if str, ok := expr.(*build.StringExpr); ok {
+			return str.Value, nil
+		}
+	case AttrInt:
+		if n, ok := intLiteral(expr); ok {
+			return n, nil
+		}
+	case AttrBool:
+		if ident, ok := expr.(*build.Ident); ok && (ident.Name == "True" || ident.Name == "False") {
+			return ident.Name == "True", nil
+		}
+	case AttrLabel:
+		if str, ok := expr.(*build.StringExpr); ok {
+			lbl, err := label.ParseApparentLabel(str.Value)
+			if err != nil {
+				return nil, err
+			}
+			return lbl, nil
+		}
+	case AttrStringList:
+		if list, ok := expr.(*build.ListExpr); ok {
+			result := make([]string, 0, len(list.List))
+			for i, elem := range list.List {
+				str, ok := elem.(*build.StringExpr)
+				if !ok {
+					return nil, fmt.Errorf("element %d: expected string, got %s", i, describeExpr(elem))
+				}
+				result = append(result, str.Value)
+			}
+			return result, nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown attribute type %d", want)
+	}
+	return nil, fmt.Errorf("expected %s, got %s", want, describeExpr(expr))
+}
+
+// intLiteral returns the value of an integer literal, including negated ones.
+func intLiteral(expr build.Expr) (int, bool) {
+	switch e := expr.(type) {
+	case *build.LiteralExpr:
+		n, err := strconv.Atoi(e.Token)
+		return n, err == nil
+	case *build.UnaryExpr:
+		if e.Op != "-" {
+			return 0, false
+		}
+		n, ok := intLiteral(e.X)
+		return -n, ok
+	}
+	return 0, false
+}
+
+// describeExpr returns a short type description of expr for error messages.
+func describeExpr(expr build.Expr) string {
+	switch e := expr.(type) {
+	case *build.StringExpr:
+		return fmt.Sprintf("string %q", e.Value)
+	case *build.LiteralExpr:
+		return "number " + e.Token
+	case *build.Ident:
+		switch e.Name {
+		case "True", "False":
+			return "bool " + e.Name
+		case "None":
+			return "None"
+		}
+		return "identifier " + e.Name
+	case *build.ListExpr:
+		return "list"
+	case *build.DictExpr:
+		return "dict"
+	case *build.UnaryExpr:
+		if n, ok := intLiteral(e); ok {
+			return "number " + strconv.Itoa(n)
+		}
+	}
+	return "expression"
+}
@@ -0,0 +1,67 @@
+package ast
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// FuzzParseContent checks the invariant that ParseContent never panics and
+// always returns either a result or a *ParseError.
+func FuzzParseContent(f *testing.F) {
+	seeds := []string{
+		``,
+		`module(name = "m", version = "1.0.0")`,
+		`bazel_dep(name = "dep", version = "1.0.0", dev_dependency = True)`,
+		`bazel_dep(name = "unclosed"`,
+		`bazel_dep(`,
+		`x = use_extension("//:ext.bzl", "ext")` + "\n" + `use_repo(x, "a", b = "c")`,
+		`ext.tag(attrs = {"k": [1, 2, {"n": None}]})`,
+		`a.b.c(d = e)`,
+		`use_extension()` + "\n" + `use_repo()` + "\n" + `include()`,
+		`use_repo_rule()` + "\n" + `inject_repo()` + "\n" + `override_repo()`,
+		`single_version_override()` + "\n" + `multiple_version_override(module_name = "m", versions = [1, None])`,
+		`archive_override(module_name = 1, urls = "x")`,
+		`git_override(module_name = "m", patch_strip = "x")`,
+		`f()()` + "\n" + `(lambda: 1)()` + "\n" + `x[0](y)`,
+		`module(name = "` + strings.Repeat("a", 4096) + `")`,
+		strings.Repeat("f(", 64) + strings.Repeat(")", 64),
+		"bazel_dep(name = \"\xff\xfe\", version = \"\x80\")",
+	}
+	for _, s := range seeds {
+		f.Add([]byte(s))
+	}
+
+	// Real-world MODULE.bazel files make good starting points for mutation.
+	files, _ := filepath.Glob(filepath.Join("testdata", "*.MODULE.bazel"))
+	for _, path := range files {
+		if data, err := os.ReadFile(path); err == nil {
+			f.Add(data)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, content []byte) {
+		result, err := ParseContent("MODULE.bazel", content)
+		if err != nil {
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("ParseContent returned %T, want *ParseError", err)
+			}
+			if result != nil {
+				t.Fatalf("ParseContent returned both a result and an error")
+			}
+			return
+		}
+		if result == nil || result.File == nil {
+			t.Fatalf("ParseContent returned nil result without error")
+		}
+		for _, stmt := range result.File.Statements {
+			if stmt == nil {
+				t.Fatalf("ParseContent returned a nil statement")
+			}
+			_ = stmt.Position()
+		}
+	})
+}
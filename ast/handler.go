@@ -1,6 +1,8 @@
 package ast
 
 import (
+	"fmt"
+
 	"github.com/albertocavalcante/go-bzlmod/label"
 )
 
@@ -41,8 +43,24 @@ type Handler interface {
 	// RegisterExecutionPlatforms is called for register_execution_platforms().
 	RegisterExecutionPlatforms(patterns []string, devDependency bool) error
 
-	// UnknownStatement is called for unrecognized function calls.
-	UnknownStatement(name string, pos Position) error
+	// Include is called for include() declarations.
+	Include(label label.ApparentLabel) error
+
+	// UnknownStatement is called for unrecognized function calls, with
+	// their keyword arguments.
+	UnknownStatement(name string, attrs map[string]any, pos Position) error
+}
+
+// ExtensionRepoHandler is an optional interface for a Handler. When the
+// handler implements it, Walk calls UseExtensionRepo instead of
+// Handler.UseRepo, passing the extension each use_repo() imports from.
+type ExtensionRepoHandler interface {
+	// UseExtensionRepo is called for use_repo() declarations. proxy is the
+	// extension variable passed to use_repo(); extensionFile and
+	// extensionName identify the use_extension() it was assigned from, and
+	// are zero if use_repo() names no known proxy. devDependency is the
+	// dev_dependency of that use_extension().
+	UseExtensionRepo(proxy string, extensionFile label.ApparentLabel, extensionName label.StarlarkIdentifier, repos []string, devDependency bool) error
 }
 
 // ExtensionProxy represents the return value of use_extension().
@@ -85,6 +103,14 @@ func walkStatement(stmt Statement, handler Handler) error {
 		return nil
 
 	case *UseRepo:
+		if h, ok := handler.(ExtensionRepoHandler); ok {
+			var file label.ApparentLabel
+			var name label.StarlarkIdentifier
+			if s.Extension != nil {
+				file, name = s.Extension.ExtensionFile, s.Extension.ExtensionName
+			}
+			return h.UseExtensionRepo(s.ExtensionProxy, file, name, s.Repos, s.DevDependency)
+		}
 		return handler.UseRepo(s.Repos, s.DevDependency)
 
 	case *SingleVersionOverride:
@@ -108,8 +134,15 @@ func walkStatement(stmt Statement, handler Handler) error {
 	case *RegisterExecutionPlatforms:
 		return handler.RegisterExecutionPlatforms(s.Patterns, s.DevDependency)
 
+	case *Include:
+		lbl, err := label.ParseApparentLabel(s.Label)
+		if err != nil {
+			return fmt.Errorf("include: %w", err)
+		}
+		return handler.Include(lbl)
+
 	case *UnknownStatement:
-		return handler.UnknownStatement(s.FuncName, s.Pos)
+		return handler.UnknownStatement(s.FuncName, s.Attributes, s.Pos)
 	}
 
 	return nil
@@ -151,10 +184,11 @@ func (h *BaseHandler) GitOverride(label.Module, string, string, string, string,
 func (h *BaseHandler) ArchiveOverride(label.Module, []string, string, string, []string, []string, int) error {
 	return nil
 }
-func (h *BaseHandler) LocalPathOverride(label.Module, string) error    { return nil }
-func (h *BaseHandler) RegisterToolchains([]string, bool) error         { return nil }
-func (h *BaseHandler) RegisterExecutionPlatforms([]string, bool) error { return nil }
-func (h *BaseHandler) UnknownStatement(string, Position) error         { return nil }
+func (h *BaseHandler) LocalPathOverride(label.Module, string) error            { return nil }
+func (h *BaseHandler) RegisterToolchains([]string, bool) error                 { return nil }
+func (h *BaseHandler) RegisterExecutionPlatforms([]string, bool) error         { return nil }
+func (h *BaseHandler) Include(label.ApparentLabel) error                       { return nil }
+func (h *BaseHandler) UnknownStatement(string, map[string]any, Position) error { return nil }
 
 // DependencyCollector is a handler that collects all bazel_dep declarations.
 type DependencyCollector struct {
@@ -52,8 +52,9 @@ func (m *mockExtensionProxy) Tag(name string, attrs map[string]any) error {
 // recordingHandler records all handler calls for testing
 type recordingHandler struct {
 	BaseHandler
-	calls []string
-	err   error // error to return from all methods
+	calls        []string
+	unknownAttrs map[string]any
+	err          error // error to return from all methods
 }
 
 func (h *recordingHandler) Module(name label.Module, version label.Version, compatLevel int, repoName label.ApparentRepo) error {
@@ -101,8 +102,9 @@ func (h *recordingHandler) RegisterExecutionPlatforms(patterns []string, devDep
 	return h.err
 }
 
-func (h *recordingHandler) UnknownStatement(name string, pos Position) error {
+func (h *recordingHandler) UnknownStatement(name string, attrs map[string]any, pos Position) error {
 	h.calls = append(h.calls, "UnknownStatement:"+name)
+	h.unknownAttrs = attrs
 	return h.err
 }
 
@@ -277,7 +279,11 @@ func TestWalk_RegisterStatements(t *testing.T) {
 func TestWalk_UnknownStatement(t *testing.T) {
 	file := &ModuleFile{
 		Statements: []Statement{
-			&UnknownStatement{FuncName: "custom_func", Pos: Position{Line: 10}},
+			&UnknownStatement{
+				FuncName:   "custom_func",
+				Attributes: map[string]any{"mode": "fast"},
+				Pos:        Position{Line: 10},
+			},
 		},
 	}
 	handler := &recordingHandler{}
@@ -294,6 +300,9 @@ func TestWalk_UnknownStatement(t *testing.T) {
 	if handler.calls[0] != "UnknownStatement:custom_func" {
 		t.Errorf("Expected 'UnknownStatement:custom_func', got %q", handler.calls[0])
 	}
+	if handler.unknownAttrs["mode"] != "fast" {
+		t.Errorf("Expected attributes {mode: fast}, got %v", handler.unknownAttrs)
+	}
 }
 
 // TestWalk_UseExtensionWithTags tests use_extension with tag calls
@@ -442,7 +451,7 @@ func TestBaseHandler_AllMethodsReturnNil(t *testing.T) {
 		t.Errorf("RegisterExecutionPlatforms returned error: %v", err)
 	}
 
-	if err := h.UnknownStatement("func", Position{}); err != nil {
+	if err := h.UnknownStatement("func", nil, Position{}); err != nil {
 		t.Errorf("UnknownStatement returned error: %v", err)
 	}
 }
@@ -683,6 +692,77 @@ func (h *useRepoHandler) UseRepo(repos []string, devDep bool) error {
 	return nil
 }
 
+// TestWalk_ExtensionRepoHandler tests that handlers implementing
+// ExtensionRepoHandler receive the extension each use_repo imports from.
+func TestWalk_ExtensionRepoHandler(t *testing.T) {
+	result, err := ParseContent("MODULE.bazel", []byte(`go_sdk = use_extension("@rules_go//go:extensions.bzl", "go_sdk", dev_dependency = True)
+use_repo(go_sdk, "go_toolchains")
+`))
+	if err != nil {
+		t.Fatalf("ParseContent error: %v", err)
+	}
+
+	h := &extensionRepoHandler{}
+	if err := Walk(result.File, h); err != nil {
+		t.Fatalf("Walk returned error: %v", err)
+	}
+
+	want := `go_sdk @rules_go//go:extensions.bzl%go_sdk [go_toolchains] dev=true`
+	if len(h.calls) != 1 || h.calls[0] != want {
+		t.Errorf("UseExtensionRepo calls = %q, want [%q]", h.calls, want)
+	}
+	if h.plainCalls != 0 {
+		t.Errorf("UseRepo called %d times, want 0", h.plainCalls)
+	}
+}
+
+type extensionRepoHandler struct {
+	BaseHandler
+	calls      []string
+	plainCalls int
+}
+
+func (h *extensionRepoHandler) UseRepo([]string, bool) error {
+	h.plainCalls++
+	return nil
+}
+
+func (h *extensionRepoHandler) UseExtensionRepo(proxy string, file label.ApparentLabel, name label.StarlarkIdentifier, repos []string, devDep bool) error {
+	h.calls = append(h.calls, fmt.Sprintf("%s %s%%%s %v dev=%v", proxy, file, name, repos, devDep))
+	return nil
+}
+
+// TestWalk_Include tests include handling
+func TestWalk_Include(t *testing.T) {
+	h := &includeHandler{}
+	file := &ModuleFile{
+		Statements: []Statement{
+			&Include{Label: "//bazel:deps.MODULE.bazel"},
+		},
+	}
+	if err := Walk(file, h); err != nil {
+		t.Fatalf("Walk returned error: %v", err)
+	}
+	if len(h.labels) != 1 || h.labels[0].Package() != "bazel" || h.labels[0].Target() != "deps.MODULE.bazel" {
+		t.Errorf("Include labels = %v, want [//bazel:deps.MODULE.bazel]", h.labels)
+	}
+
+	file.Statements = []Statement{&Include{Label: "not a label"}}
+	if err := Walk(file, h); err == nil {
+		t.Error("Walk with an invalid include label succeeded, want error")
+	}
+}
+
+type includeHandler struct {
+	BaseHandler
+	labels []label.ApparentLabel
+}
+
+func (h *includeHandler) Include(lbl label.ApparentLabel) error {
+	h.labels = append(h.labels, lbl)
+	return nil
+}
+
 // TestDependencyCollector_WithRepoName tests collecting deps with repo_name
 func TestDependencyCollector_WithRepoName(t *testing.T) {
 	file := &ModuleFile{
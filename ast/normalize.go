@@ -0,0 +1,75 @@
+package ast
+
+import (
+	"slices"
+
+	"github.com/albertocavalcante/go-bzlmod/internal/buildutil"
+	"github.com/albertocavalcante/go-bzlmod/third_party/buildtools/build"
+)
+
+// canonicalAttrOrder lists, per MODULE.bazel function, its keyword arguments
+// in the order the Bazel documentation declares them.
+var canonicalAttrOrder = map[string][]string{
+	"module":    {"name", "version", "compatibility_level", "repo_name", "bazel_compatibility"},
+	"bazel_dep": {"name", "version", "max_compatibility_level", "repo_name", "dev_dependency"},
+	"single_version_override": {
+		"module_name", "version", "registry", "patches", "patch_cmds", "patch_strip",
+	},
+	"multiple_version_override": {"module_name", "versions", "registry"},
+	"git_override": {
+		"module_name", "remote", "commit", "tag", "branch",
+		"patches", "patch_cmds", "patch_strip", "init_submodules", "strip_prefix",
+	},
+	"archive_override": {
+		"module_name", "urls", "integrity", "strip_prefix", "patches", "patch_cmds", "patch_strip",
+	},
+	"local_path_override": {"module_name", "path"},
+}
+
+// NormalizeAttributeOrder reorders the keyword arguments of module(),
+// bazel_dep() and the *_override() calls in f into Bazel's documented order,
+// so name (or module_name) always comes first.
+//
+// Bazel accepts attributes in any order and the parser does too, so this only
+// changes how the file is written, never what it means. Positional arguments
+// stay in front, and attributes the table does not know follow the known ones
+// in their original order. Comments move with the argument they are attached
+// to. Statements parsed from f are unaffected.
+//
+// Print the result with build.FormatWithoutRewriting: build.Format applies
+// buildifier's own argument sorting, which differs from Bazel's order.
+func NormalizeAttributeOrder(f *ModuleFile) {
+	if f == nil || f.raw == nil {
+		return
+	}
+	for _, stmt := range f.raw.Stmt {
+		call, ok := stmt.(*build.CallExpr)
+		if !ok {
+			continue
+		}
+		order, ok := canonicalAttrOrder[buildutil.FuncName(call)]
+		if !ok {
+			continue
+		}
+		slices.SortStableFunc(call.List, func(a, b build.Expr) int {
+			return attrRank(a, order) - attrRank(b, order)
+		})
+	}
+}
+
+// attrRank returns the sort key of a call argument: positional arguments
+// first, then known keyword arguments by their index in order, then the rest.
+func attrRank(arg build.Expr, order []string) int {
+	assign, ok := arg.(*build.AssignExpr)
+	if !ok {
+		return 0
+	}
+	key, ok := assign.LHS.(*build.Ident)
+	if !ok {
+		return len(order) + 1
+	}
+	if i := slices.Index(order, key.Name); i >= 0 {
+		return i + 1
+	}
+	return len(order) + 1
+}
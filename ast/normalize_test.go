@@ -0,0 +1,73 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/albertocavalcante/go-bzlmod/third_party/buildtools/build"
+)
+
+func TestNormalizeAttributeOrder(t *testing.T) {
+	moduleAttrs := []string{
+		`name = "my_module"`,
+		`version = "1.2.3"`,
+		`compatibility_level = 2`,
+		`repo_name = "custom_repo"`,
+		`bazel_compatibility = [">=7.0.0"]`,
+	}
+	depAttrs := []string{
+		`name = "rules_go"`,
+		`version = "0.50.1"`,
+		`max_compatibility_level = 1`,
+		`repo_name = "io_bazel_rules_go"`,
+		`dev_dependency = True`,
+	}
+	// The printer breaks module() over lines because of its list argument.
+	want := "module(\n    " + strings.Join(moduleAttrs, ",\n    ") + ",\n)\n\n" +
+		"bazel_dep(" + strings.Join(depAttrs, ", ") + ")\n"
+
+	moduleOrders := permutations(moduleAttrs)
+	for i, depOrder := range permutations(depAttrs) {
+		content := "module(" + strings.Join(moduleOrders[i], ", ") + ")\n\n" +
+			"bazel_dep(" + strings.Join(depOrder, ", ") + ")\n"
+		result, err := ParseContent("MODULE.bazel", []byte(content))
+		if err != nil {
+			t.Fatalf("ParseContent error: %v", err)
+		}
+		NormalizeAttributeOrder(result.File)
+		if got := string(build.FormatWithoutRewriting(result.File.Raw())); got != want {
+			t.Fatalf("normalized\n%s=\n%s\nwant\n%s", content, got, want)
+		}
+	}
+}
+
+func TestNormalizeAttributeOrder_UnknownAndOverrides(t *testing.T) {
+	content := `git_override(
+    # pinned for reproducibility
+    commit = "abc123",
+    custom = 1,
+    remote = "https://github.com/example/foo.git",
+    module_name = "foo",
+)
+
+use_repo(ext, "b", "a")
+`
+	want := `git_override(
+    module_name = "foo",
+    remote = "https://github.com/example/foo.git",
+    # pinned for reproducibility
+    commit = "abc123",
+    custom = 1,
+)
+
+use_repo(ext, "b", "a")
+`
+	result, err := ParseContent("MODULE.bazel", []byte(content))
+	if err != nil {
+		t.Fatalf("ParseContent error: %v", err)
+	}
+	NormalizeAttributeOrder(result.File)
+	if got := string(build.FormatWithoutRewriting(result.File.Raw())); got != want {
+		t.Errorf("normalized =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -42,25 +42,66 @@ func (r *ParseResult) HasErrors() bool {
 // Parser parses MODULE.bazel files into AST.
 type Parser struct {
 	filename string
+	schema   AttrSchema
+	strict   bool
+	// assigned holds the variables assigned so far, such as repo rule
+	// proxies, which strict mode accepts as functions.
+	assigned map[string]bool
 	errors   []*ParseError
 	warnings []*ParseError
 }
 
 // ParseFile reads and parses a MODULE.bazel file from disk.
-func ParseFile(filename string) (*ParseResult, error) {
+func ParseFile(filename string, opts ...ParseOption) (*ParseResult, error) {
 	data, err := os.ReadFile(filename) // #nosec G304 -- intentional file read by caller-provided path
 	if err != nil {
 		return nil, fmt.Errorf("failed to read %s: %w", filename, err)
 	}
-	return ParseContent(filename, data)
+	return ParseContent(filename, data, opts...)
 }
 
 // ParseContent parses MODULE.bazel content from bytes.
-func ParseContent(filename string, content []byte) (*ParseResult, error) {
+//
+// ParseContent never panics, regardless of input. It returns either a
+// non-nil *ParseResult (possibly carrying semantic errors and warnings) or
+// a *ParseError describing why the content could not be parsed at all. A
+// panic anywhere in parsing, in buildtools or in building the AST, is
+// returned as a *ParseError carrying the panic value.
+func ParseContent(filename string, content []byte, opts ...ParseOption) (result *ParseResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = &ParseError{
+				Pos:     Position{Filename: filename},
+				Message: fmt.Sprintf("internal parser error: %v", r),
+			}
+		}
+	}()
+
 	p := &Parser{filename: filename}
+	for _, opt := range opts {
+		opt(p)
+	}
 	return p.parse(content)
 }
 
+// ParseContentStrict parses MODULE.bazel content like ParseContent with
+// WithStrict, so misspelled directives such as bazel_deps(...) are errors.
+func ParseContentStrict(filename string, content []byte, opts ...ParseOption) (*ParseResult, error) {
+	return ParseContent(filename, content, append(opts, WithStrict())...)
+}
+
+// WithStrict reports a call to a function MODULE.bazel does not define as
+// an error in ParseResult.Errors, at the position of the call. Calls to a
+// variable assigned earlier in the file, such as a use_repo_rule() proxy,
+// are allowed. The call is still returned as an UnknownStatement. Without
+// WithStrict such calls are accepted silently.
+func WithStrict() ParseOption {
+	return func(p *Parser) {
+		p.strict = true
+	}
+}
+
 func (p *Parser) parse(content []byte) (*ParseResult, error) {
 	raw, err := build.ParseModule(p.filename, content)
 	if err != nil {
@@ -75,12 +116,29 @@ func (p *Parser) parse(content []byte) (*ParseResult, error) {
 		Path:       p.filename,
 		Statements: make([]Statement, 0, len(raw.Stmt)),
 		raw:        raw,
+		exprs:      make(map[Statement]build.Expr, len(raw.Stmt)),
 	}
 
+	// Extension proxies by variable name, for use_repo() calls.
+	proxies := make(map[string]*UseExtension)
 	for _, stmt := range raw.Stmt {
-		if s := p.parseStatement(stmt); s != nil {
-			file.Statements = append(file.Statements, s)
+		s := p.parseStatement(stmt)
+		if s == nil {
+			continue
+		}
+		switch s := s.(type) {
+		case *UseExtension:
+			if s.Proxy != "" {
+				proxies[s.Proxy] = s
+			}
+		case *UseRepo:
+			if ext, ok := proxies[s.ExtensionProxy]; ok {
+				s.Extension = ext
+				s.DevDependency = ext.DevDependency
+			}
 		}
+		file.Statements = append(file.Statements, s)
+		file.exprs[s] = stmt
 	}
 
 	return &ParseResult{
@@ -93,12 +151,22 @@ func (p *Parser) parse(content []byte) (*ParseResult, error) {
 func (p *Parser) parseStatement(expr build.Expr) Statement {
 	// Handle assignment expressions like: go = use_extension(...) or http_archive = use_repo_rule(...)
 	if assign, ok := expr.(*build.AssignExpr); ok {
+		if lhs, ok := assign.LHS.(*build.Ident); ok {
+			if p.assigned == nil {
+				p.assigned = make(map[string]bool)
+			}
+			p.assigned[lhs.Name] = true
+		}
 		if call, ok := assign.RHS.(*build.CallExpr); ok {
 			if ident, ok := call.X.(*build.Ident); ok {
 				pos := p.position(call)
 				switch ident.Name {
 				case "use_extension":
-					return p.parseUseExtension(call, pos)
+					ext := p.parseUseExtension(call, pos)
+					if lhs, ok := assign.LHS.(*build.Ident); ok {
+						ext.Proxy = lhs.Name
+					}
+					return ext
 				case "use_repo_rule":
 					return p.parseUseRepoRule(call, pos)
 				}
@@ -128,21 +196,21 @@ func (p *Parser) parseStatement(expr build.Expr) Statement {
 	case "module":
 		return p.parseModule(call, pos)
 	case "bazel_dep":
-		return p.parseBazelDep(call, pos)
+		return statement(p.parseBazelDep(call, pos))
 	case "use_extension":
 		return p.parseUseExtension(call, pos)
 	case "use_repo":
 		return p.parseUseRepo(call, pos)
 	case "single_version_override":
-		return p.parseSingleVersionOverride(call, pos)
+		return statement(p.parseSingleVersionOverride(call, pos))
 	case "multiple_version_override":
-		return p.parseMultipleVersionOverride(call, pos)
+		return statement(p.parseMultipleVersionOverride(call, pos))
 	case "git_override":
-		return p.parseGitOverride(call, pos)
+		return statement(p.parseGitOverride(call, pos))
 	case "archive_override":
-		return p.parseArchiveOverride(call, pos)
+		return statement(p.parseArchiveOverride(call, pos))
 	case "local_path_override":
-		return p.parseLocalPathOverride(call, pos)
+		return statement(p.parseLocalPathOverride(call, pos))
 	case "register_toolchains":
 		return p.parseRegisterToolchains(call, pos)
 	case "register_execution_platforms":
@@ -158,12 +226,30 @@ func (p *Parser) parseStatement(expr build.Expr) Statement {
 	case "flag_alias":
 		return p.parseFlagAlias(call, pos)
 	default:
-		return &UnknownStatement{
-			Pos:      pos,
-			FuncName: ident.Name,
-			Raw:      expr,
+		return p.parseUnknownStatement(call, ident.Name, pos)
+	}
+}
+
+func (p *Parser) parseUnknownStatement(call *build.CallExpr, funcName string, pos Position) *UnknownStatement {
+	stmt := &UnknownStatement{
+		Pos:        pos,
+		FuncName:   funcName,
+		Attributes: make(map[string]any),
+		Raw:        call,
+	}
+	if p.strict && !p.assigned[funcName] {
+		p.addErrorf(pos, "unknown function %s()", funcName)
+	}
+
+	for _, arg := range call.List {
+		if assign, ok := arg.(*build.AssignExpr); ok {
+			if lhs, ok := assign.LHS.(*build.Ident); ok {
+				stmt.Attributes[lhs.Name] = buildutil.ExtractValue(assign.RHS)
+			}
 		}
 	}
+
+	return stmt
 }
 
 func (p *Parser) parseInclude(call *build.CallExpr, pos Position) *Include {
@@ -183,6 +269,8 @@ func (p *Parser) parseInclude(call *build.CallExpr, pos Position) *Include {
 
 	if inc.Label == "" {
 		p.addErrorf(pos, "include: missing required label argument")
+	} else if _, err := label.ParseApparentLabel(inc.Label); err != nil {
+		p.addErrorf(pos, "include: %v", err)
 	}
 
 	return inc
@@ -207,7 +295,7 @@ func (p *Parser) parseExtensionTagCall(call *build.CallExpr, dotExpr *build.DotE
 	for _, arg := range call.List {
 		if assign, ok := arg.(*build.AssignExpr); ok {
 			if lhs, ok := assign.LHS.(*build.Ident); ok {
-				tag.Attributes[lhs.Name] = buildutil.ExtractValue(assign.RHS)
+				tag.Attributes[lhs.Name] = p.tagAttrValue(tag, lhs.Name, assign.RHS)
 			}
 		}
 	}
@@ -215,6 +303,22 @@ func (p *Parser) parseExtensionTagCall(call *build.CallExpr, dotExpr *build.DotE
 	return tag
 }
 
+// tagAttrValue returns the value of a tag attribute, coerced according to the
+// parser's schema when the attribute is listed there.
+func (p *Parser) tagAttrValue(tag *ExtensionTagCall, name string, expr build.Expr) any {
+	want, ok := p.schema[name]
+	if !ok || want == AttrAny {
+		return buildutil.ExtractValue(expr)
+	}
+
+	value, err := coerceAttr(expr, want)
+	if err != nil {
+		p.addErrorf(p.position(expr), "%s.%s: attribute %q: %v", tag.Extension, tag.TagName, name, err)
+		return buildutil.ExtractValue(expr)
+	}
+	return value
+}
+
 func (p *Parser) parseModule(call *build.CallExpr, pos Position) *ModuleDecl {
 	decl := &ModuleDecl{Pos: pos}
 
@@ -332,7 +436,13 @@ func (p *Parser) parseUseExtension(call *build.CallExpr, pos Position) *UseExten
 func (p *Parser) parseUseRepo(call *build.CallExpr, pos Position) *UseRepo {
 	repo := &UseRepo{Pos: pos}
 
-	// First positional arg is the extension proxy (we just capture repos for now)
+	// First positional arg is the extension proxy
+	if len(call.List) > 0 {
+		if ident, ok := call.List[0].(*build.Ident); ok {
+			repo.ExtensionProxy = ident.Name
+		}
+	}
+
 	repo.Repos = make([]string, 0)
 
 	// Collect all string positional args after the first
@@ -419,10 +529,18 @@ func (p *Parser) parseArchiveOverride(call *build.CallExpr, pos Position) *Archi
 		return nil
 	}
 
+	// urls accepts either a list of mirrors or a single string.
+	urls := buildutil.StringList(call, "urls")
+	if urls == nil {
+		if url := buildutil.String(call, "urls"); url != "" {
+			urls = []string{url}
+		}
+	}
+
 	return &ArchiveOverride{
 		Pos:         pos,
 		Module:      m,
-		URLs:        buildutil.StringList(call, "urls"),
+		URLs:        urls,
 		Integrity:   buildutil.String(call, "integrity"),
 		StripPrefix: buildutil.String(call, "strip_prefix"),
 		Patches:     buildutil.StringList(call, "patches"),
@@ -573,6 +691,19 @@ func (p *Parser) parseFlagAlias(call *build.CallExpr, pos Position) *FlagAlias {
 	return alias
 }
 
+// statement converts a concrete statement pointer to a Statement, mapping a
+// nil pointer to a nil interface. Without this, parsers that return a typed
+// nil on error would produce a non-nil Statement that panics when used.
+func statement[T any, P interface {
+	*T
+	Statement
+}](s P) Statement {
+	if s == nil {
+		return nil
+	}
+	return s
+}
+
 // Helper methods for extracting attributes
 
 func (p *Parser) position(expr build.Expr) Position {
@@ -1,7 +1,12 @@
 package ast
 
 import (
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+
+	"github.com/albertocavalcante/go-bzlmod/label"
 )
 
 func TestParseContent_Module(t *testing.T) {
@@ -311,6 +316,28 @@ func TestParseContent_Error_MissingName(t *testing.T) {
 	}
 }
 
+func TestParseContent_InvalidStatementsOmitted(t *testing.T) {
+	content := `bazel_dep(version = "1.0.0")
+single_version_override()
+multiple_version_override()
+git_override()
+archive_override()
+local_path_override()
+`
+	result, err := ParseContent("MODULE.bazel", []byte(content))
+	if err != nil {
+		t.Fatalf("ParseContent error: %v", err)
+	}
+
+	if len(result.Errors) != 6 {
+		t.Errorf("len(Errors) = %d, want 6", len(result.Errors))
+	}
+	// Invalid statements must be dropped, not kept as typed nil pointers.
+	if len(result.File.Statements) != 0 {
+		t.Fatalf("len(Statements) = %d, want 0", len(result.File.Statements))
+	}
+}
+
 func TestParseContent_SyntaxError(t *testing.T) {
 	content := `module(name = "test"
 ` // Missing closing paren
@@ -329,6 +356,24 @@ func TestParseContent_SyntaxError(t *testing.T) {
 	}
 }
 
+func TestParseContent_PanicReturnsParseError(t *testing.T) {
+	// Any panic under ParseContent, not only one in buildtools, is returned
+	// as a *ParseError instead of reaching the caller.
+	panicking := func(*Parser) { panic("boom") }
+
+	result, err := ParseContent("MODULE.bazel", []byte(`module(name = "test")`), panicking)
+	if result != nil {
+		t.Errorf("ParseContent() result = %+v, want nil", result)
+	}
+	parseErr, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("ParseContent() error = %T %v, want *ParseError", err, err)
+	}
+	if parseErr.Pos.Filename != "MODULE.bazel" || !strings.Contains(parseErr.Message, "boom") {
+		t.Errorf("ParseError = %+v, want filename MODULE.bazel and the panic value", parseErr)
+	}
+}
+
 func TestParseContent_MultipleVersionOverride(t *testing.T) {
 	content := `multiple_version_override(
     module_name = "protobuf",
@@ -449,6 +494,56 @@ use_repo(go, "go_sdk", "go_toolchains")
 	if useRepo.Repos[1] != "go_toolchains" {
 		t.Errorf("useRepo.Repos[1] = %q, want 'go_toolchains'", useRepo.Repos[1])
 	}
+	if useRepo.ExtensionProxy != "go" {
+		t.Errorf("useRepo.ExtensionProxy = %q, want 'go'", useRepo.ExtensionProxy)
+	}
+	if useRepo.Extension == nil || useRepo.Extension.ExtensionName.String() != "go" {
+		t.Errorf("useRepo.Extension = %+v, want the go extension", useRepo.Extension)
+	}
+}
+
+func TestParseContent_UseRepoExtension(t *testing.T) {
+	content := `tools = use_extension("//:tools.bzl", "tools", dev_dependency = True)
+use_repo(tools, "lint")
+
+tools = use_extension("//:tools.bzl", "tools")
+use_repo(tools, "compiler")
+
+use_repo(undefined, "orphan")
+`
+	result, err := ParseContent("MODULE.bazel", []byte(content))
+	if err != nil {
+		t.Fatalf("ParseContent error: %v", err)
+	}
+
+	var exts []*UseExtension
+	var repos []*UseRepo
+	for _, stmt := range result.File.Statements {
+		switch s := stmt.(type) {
+		case *UseExtension:
+			exts = append(exts, s)
+		case *UseRepo:
+			repos = append(repos, s)
+		}
+	}
+	if len(exts) != 2 || len(repos) != 3 {
+		t.Fatalf("got %d use_extension and %d use_repo, want 2 and 3", len(exts), len(repos))
+	}
+	if exts[0].Proxy != "tools" {
+		t.Errorf("exts[0].Proxy = %q, want 'tools'", exts[0].Proxy)
+	}
+
+	// Each use_repo binds to the latest assignment of its proxy and takes
+	// its dev_dependency.
+	if repos[0].Extension != exts[0] || !repos[0].DevDependency {
+		t.Errorf("use_repo(tools, \"lint\"): Extension = %p, DevDependency = %v; want first extension, true", repos[0].Extension, repos[0].DevDependency)
+	}
+	if repos[1].Extension != exts[1] || repos[1].DevDependency {
+		t.Errorf("use_repo(tools, \"compiler\"): Extension = %p, DevDependency = %v; want second extension, false", repos[1].Extension, repos[1].DevDependency)
+	}
+	if repos[2].Extension != nil || repos[2].ExtensionProxy != "undefined" {
+		t.Errorf("use_repo(undefined, ...): Extension = %p, ExtensionProxy = %q; want nil, 'undefined'", repos[2].Extension, repos[2].ExtensionProxy)
+	}
 }
 
 func TestParseContent_UseRepoRule(t *testing.T) {
@@ -640,6 +735,91 @@ go.download(name = "another_sdk", version = "1.22.0")
 	}
 }
 
+func TestParseContent_ExtensionTagCall_AttrSchema(t *testing.T) {
+	content := `ext = use_extension("//:ext.bzl", "ext")
+ext.tag(
+    count = 3,
+    offset = -1,
+    version = 1.21,
+    enabled = True,
+    target = "@repo//pkg:target",
+    files = ["a", "b"],
+)
+`
+	schema := AttrSchema{
+		"count":   AttrInt,
+		"offset":  AttrInt,
+		"version": AttrString,
+		"enabled": AttrBool,
+		"target":  AttrLabel,
+		"files":   AttrStringList,
+	}
+
+	result, err := ParseContent("MODULE.bazel", []byte(content), WithAttrSchema(schema))
+	if err != nil {
+		t.Fatalf("ParseContent error: %v", err)
+	}
+
+	var tag *ExtensionTagCall
+	for _, stmt := range result.File.Statements {
+		if tc, ok := stmt.(*ExtensionTagCall); ok {
+			tag = tc
+		}
+	}
+	if tag == nil {
+		t.Fatal("No extension tag call found")
+	}
+
+	if got, ok := tag.Attributes["count"].(int); !ok || got != 3 {
+		t.Errorf("count = %#v, want int 3", tag.Attributes["count"])
+	}
+	if got, ok := tag.Attributes["offset"].(int); !ok || got != -1 {
+		t.Errorf("offset = %#v, want int -1", tag.Attributes["offset"])
+	}
+	if got, ok := tag.Attributes["enabled"].(bool); !ok || !got {
+		t.Errorf("enabled = %#v, want true", tag.Attributes["enabled"])
+	}
+	if got, ok := tag.Attributes["target"].(label.ApparentLabel); !ok || got.Target() != "target" {
+		t.Errorf("target = %#v, want label with target 'target'", tag.Attributes["target"])
+	}
+	if got, ok := tag.Attributes["files"].([]string); !ok || len(got) != 2 {
+		t.Errorf("files = %#v, want [a b]", tag.Attributes["files"])
+	}
+
+	// version = 1.21 is a number, not a string.
+	if len(result.Errors) != 1 {
+		t.Fatalf("len(Errors) = %d, want 1: %v", len(result.Errors), result.Errors)
+	}
+	parseErr := result.Errors[0]
+	if !strings.Contains(parseErr.Message, `"version"`) || !strings.Contains(parseErr.Message, "expected string, got number 1.21") {
+		t.Errorf("error message = %q", parseErr.Message)
+	}
+	if parseErr.Pos.Line != 5 {
+		t.Errorf("error line = %d, want 5", parseErr.Pos.Line)
+	}
+	// The untyped value is kept on mismatch.
+	if got, ok := tag.Attributes["version"].(string); !ok || got != "1.21" {
+		t.Errorf("version = %#v, want untyped \"1.21\"", tag.Attributes["version"])
+	}
+}
+
+func TestParseContent_ExtensionTagCall_NoSchemaIsUntyped(t *testing.T) {
+	content := `ext = use_extension("//:ext.bzl", "ext")
+ext.tag(version = 1.21, count = "3")
+`
+	result, err := ParseContent("MODULE.bazel", []byte(content))
+	if err != nil {
+		t.Fatalf("ParseContent error: %v", err)
+	}
+	if result.HasErrors() {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+	tag := result.File.Statements[1].(*ExtensionTagCall)
+	if got, ok := tag.Attributes["count"].(string); !ok || got != "3" {
+		t.Errorf("count = %#v, want \"3\"", tag.Attributes["count"])
+	}
+}
+
 func TestParseContent_RegisterExecutionPlatforms(t *testing.T) {
 	content := `register_execution_platforms(
     "//platforms:linux_x86_64",
@@ -678,7 +858,7 @@ func TestParseContent_RegisterExecutionPlatforms(t *testing.T) {
 }
 
 func TestParseContent_UnknownStatement(t *testing.T) {
-	content := `unknown_func(some_arg = "value")
+	content := `unknown_func("positional", some_arg = "value", count = 3, items = ["a", "b"])
 `
 	result, err := ParseContent("MODULE.bazel", []byte(content))
 	if err != nil {
@@ -699,6 +879,46 @@ func TestParseContent_UnknownStatement(t *testing.T) {
 	if unknown.FuncName != "unknown_func" {
 		t.Errorf("unknown.FuncName = %q, want 'unknown_func'", unknown.FuncName)
 	}
+
+	want := map[string]any{"some_arg": "value", "count": 3, "items": []any{"a", "b"}}
+	if !reflect.DeepEqual(unknown.Attributes, want) {
+		t.Errorf("unknown.Attributes = %#v, want %#v", unknown.Attributes, want)
+	}
+}
+
+func TestParseContentStrict(t *testing.T) {
+	content := `module(name = "my_module")
+
+bazel_deps(name = "rules_go", version = "0.50.1")
+bazel_dep(version = "1.0")
+
+http_archive = use_repo_rule("@bazel_tools//tools/build_defs/repo:http.bzl", "http_archive")
+http_archive(name = "foo", urls = ["https://example.com/foo.tar.gz"])
+`
+	lenient, err := ParseContent("MODULE.bazel", []byte(content))
+	if err != nil {
+		t.Fatalf("ParseContent error: %v", err)
+	}
+	strict, err := ParseContentStrict("MODULE.bazel", []byte(content))
+	if err != nil {
+		t.Fatalf("ParseContentStrict error: %v", err)
+	}
+
+	// The malformed bazel_dep is an error in both modes; the typo only in
+	// strict mode.
+	if len(lenient.Errors) != 1 {
+		t.Fatalf("ParseContent errors = %v, want 1", lenient.Errors)
+	}
+	if len(strict.Errors) != 2 {
+		t.Fatalf("ParseContentStrict errors = %v, want 2", strict.Errors)
+	}
+	unknown := strict.Errors[0]
+	if unknown.Pos.Line != 3 || !strings.Contains(unknown.Message, "bazel_deps") {
+		t.Errorf("unknown function error = %v, want bazel_deps at line 3", unknown)
+	}
+	if len(strict.File.Statements) != len(lenient.File.Statements) {
+		t.Errorf("strict mode parsed %d statements, want %d", len(strict.File.Statements), len(lenient.File.Statements))
+	}
 }
 
 func TestParseContent_GitOverride_AllFields(t *testing.T) {
@@ -858,3 +1078,73 @@ register_execution_platforms("//platforms:linux_x86_64")
 		}
 	}
 }
+
+// permutations returns every ordering of items.
+func permutations(items []string) [][]string {
+	if len(items) <= 1 {
+		return [][]string{slices.Clone(items)}
+	}
+	var out [][]string
+	for i := range items {
+		rest := slices.Concat(items[:i:i], items[i+1:])
+		for _, p := range permutations(rest) {
+			out = append(out, append([]string{items[i]}, p...))
+		}
+	}
+	return out
+}
+
+func TestParseContent_AttributeOrderIndependent(t *testing.T) {
+	tests := []struct {
+		name  string
+		fn    string
+		attrs []string
+	}{
+		{
+			name: "module",
+			fn:   "module",
+			attrs: []string{
+				`name = "my_module"`,
+				`version = "1.2.3"`,
+				`compatibility_level = 2`,
+				`repo_name = "custom_repo"`,
+				`bazel_compatibility = [">=7.0.0"]`,
+			},
+		},
+		{
+			name: "bazel_dep",
+			fn:   "bazel_dep",
+			attrs: []string{
+				`name = "rules_go"`,
+				`version = "0.50.1"`,
+				`max_compatibility_level = 1`,
+				`repo_name = "io_bazel_rules_go"`,
+				`dev_dependency = True`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want Statement
+			for _, order := range permutations(tt.attrs) {
+				content := tt.fn + "(" + strings.Join(order, ", ") + ")\n"
+				result, err := ParseContent("MODULE.bazel", []byte(content))
+				if err != nil {
+					t.Fatalf("ParseContent(%s) error: %v", content, err)
+				}
+				if result.HasErrors() || len(result.File.Statements) != 1 {
+					t.Fatalf("ParseContent(%s) = %d statements, errors %v", content, len(result.File.Statements), result.Errors)
+				}
+				got := result.File.Statements[0]
+				if want == nil {
+					want = got
+					continue
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("ParseContent(%s) = %+v, want %+v", content, got, want)
+				}
+			}
+		})
+	}
+}
@@ -20,6 +20,10 @@ type ModuleFile struct {
 	Statements []Statement
 	Comments   []*Comment
 	raw        *build.File
+
+	// exprs maps each parsed statement to the raw statement it came from, so
+	// Write can update it in place.
+	exprs map[Statement]build.Expr
 }
 
 // Raw returns the underlying buildtools File for advanced use cases.
@@ -68,6 +72,7 @@ func (b *BazelDep) isStatement()       {}
 // UseExtension represents a use_extension() call.
 type UseExtension struct {
 	Pos           Position
+	Proxy         string // The variable the proxy is assigned to (e.g., "go_sdk"), if any
 	ExtensionFile label.ApparentLabel
 	ExtensionName label.StarlarkIdentifier
 	DevDependency bool
@@ -88,9 +93,14 @@ type ExtensionTag struct {
 
 // UseRepo represents a use_repo() call.
 type UseRepo struct {
-	Pos           Position
-	Extension     *UseExtension
-	Repos         []string
+	Pos            Position
+	ExtensionProxy string // The extension proxy variable passed first (e.g., "go_sdk")
+	// Extension is the most recent use_extension() assigned to
+	// ExtensionProxy before this call, or nil if there is none.
+	Extension *UseExtension
+	Repos     []string
+	// DevDependency is inherited from Extension: repos imported from a
+	// dev_dependency extension are dev dependencies.
 	DevDependency bool
 }
 
@@ -286,9 +296,10 @@ func (f *FlagAlias) isStatement()       {}
 
 // UnknownStatement represents an unrecognized statement for forward compatibility.
 type UnknownStatement struct {
-	Pos      Position
-	FuncName string
-	Raw      build.Expr
+	Pos        Position
+	FuncName   string
+	Attributes map[string]any // Keyword arguments, as buildutil.ExtractValue returns them
+	Raw        build.Expr
 }
 
 func (u *UnknownStatement) Position() Position { return u.Pos }
@@ -0,0 +1,369 @@
+package ast
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/albertocavalcante/go-bzlmod/internal/buildutil"
+	"github.com/albertocavalcante/go-bzlmod/label"
+	"github.com/albertocavalcante/go-bzlmod/third_party/buildtools/build"
+)
+
+// Format parses MODULE.bazel content and writes it back with Write: the
+// buildifier layout with module(), bazel_dep() and override attributes in
+// Bazel's documented order.
+func Format(content []byte) ([]byte, error) {
+	result, err := ParseContent("MODULE.bazel", content)
+	if err != nil {
+		return nil, err
+	}
+	return Write(result.File)
+}
+
+// Write serializes f as MODULE.bazel text, in the order of f.Statements, so
+// a file can be edited programmatically:
+//
+//	result, _ := ast.ParseFile("MODULE.bazel")
+//	for _, stmt := range result.File.Statements {
+//	    if dep, ok := stmt.(*ast.BazelDep); ok && dep.Name.String() == "rules_go" {
+//	        dep.Version = label.MustVersion("0.51.0")
+//	    }
+//	}
+//	out, err := ast.Write(result.File)
+//
+// A statement parsed from the file is written from its source, updated with
+// the fields changed since parsing. Only changed attributes are rewritten, so
+// comments, attributes the statement type does not model and values that are
+// not literals (such as a version read from a variable) are kept. Statements
+// removed from f.Statements are dropped; comment blocks and other source
+// statements the parser does not model stay in front of the statement that
+// followed them.
+//
+// Statements added to f.Statements are generated from their fields. That
+// works for module(), bazel_dep(), the *_override() calls,
+// register_toolchains(), register_execution_platforms(), include() and
+// flag_alias(), and for any statement with a Raw expression; adding other
+// statement types is an error.
+//
+// Attributes are ordered as NormalizeAttributeOrder does. Write updates
+// f.Raw() to match the output.
+func Write(f *ModuleFile) ([]byte, error) {
+	if f == nil {
+		return nil, fmt.Errorf("write: nil module file")
+	}
+	if f.raw == nil {
+		f.raw = &build.File{Path: f.Path, Type: build.TypeModule}
+	}
+	if f.exprs == nil {
+		f.exprs = make(map[Statement]build.Expr)
+	}
+
+	p := &Parser{filename: f.Path}
+	keep := make(map[Statement]bool, len(f.Statements))
+	exprs := make([]build.Expr, 0, len(f.Statements))
+	for _, stmt := range f.Statements {
+		if keep[stmt] {
+			continue
+		}
+		keep[stmt] = true
+
+		var old Statement
+		expr, ok := f.exprs[stmt]
+		if ok {
+			old = p.parseStatement(expr)
+		} else {
+			var err error
+			if expr, err = newStatementExpr(stmt); err != nil {
+				return nil, err
+			}
+			f.exprs[stmt] = expr
+		}
+		if call, ok := expr.(*build.CallExpr); ok {
+			syncStatement(call, old, stmt)
+		}
+		exprs = append(exprs, expr)
+	}
+
+	typed := make(map[build.Expr]bool, len(f.exprs))
+	dropped := make(map[build.Expr]bool)
+	for stmt, expr := range f.exprs {
+		if keep[stmt] {
+			typed[expr] = true
+		} else {
+			dropped[expr] = true
+			delete(f.exprs, stmt)
+		}
+	}
+
+	// Source statements without a Statement stay in front of the next one
+	// that has one.
+	leading := make(map[build.Expr][]build.Expr)
+	var pending []build.Expr
+	for _, expr := range f.raw.Stmt {
+		switch {
+		case dropped[expr]:
+		case typed[expr]:
+			leading[expr] = pending
+			pending = nil
+		default:
+			pending = append(pending, expr)
+		}
+	}
+	stmts := make([]build.Expr, 0, len(exprs)+len(f.raw.Stmt))
+	for _, expr := range exprs {
+		stmts = append(stmts, leading[expr]...)
+		stmts = append(stmts, expr)
+	}
+	f.raw.Stmt = append(stmts, pending...)
+
+	NormalizeAttributeOrder(f)
+	return build.FormatWithoutRewriting(f.raw), nil
+}
+
+// newStatementExpr returns an empty call for a statement added to a file, to
+// be filled in by syncStatement, or the statement's Raw expression.
+func newStatementExpr(stmt Statement) (build.Expr, error) {
+	var name string
+	multiLine := false
+	switch s := stmt.(type) {
+	case *ModuleDecl:
+		name, multiLine = "module", true
+	case *BazelDep:
+		name = "bazel_dep"
+	case *SingleVersionOverride:
+		name, multiLine = "single_version_override", true
+	case *MultipleVersionOverride:
+		name, multiLine = "multiple_version_override", true
+	case *GitOverride:
+		name, multiLine = "git_override", true
+	case *ArchiveOverride:
+		name, multiLine = "archive_override", true
+	case *LocalPathOverride:
+		name, multiLine = "local_path_override", true
+	case *RegisterToolchains:
+		name = "register_toolchains"
+	case *RegisterExecutionPlatforms:
+		name = "register_execution_platforms"
+	case *Include:
+		name = "include"
+	case *FlagAlias:
+		name = "flag_alias"
+	case *ExtensionTagCall:
+		if s.Raw != nil {
+			return s.Raw, nil
+		}
+	case *RepoRuleCall:
+		if s.Raw != nil {
+			return s.Raw, nil
+		}
+	case *UnknownStatement:
+		if s.Raw != nil {
+			return s.Raw, nil
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("write: cannot generate a new %T statement", stmt)
+	}
+	return &build.CallExpr{X: &build.Ident{Name: name}, ForceMultiLine: multiLine}, nil
+}
+
+// syncStatement rewrites the attributes of call whose value in stmt differs
+// from old, the statement call was parsed into. A nil old stands for a new,
+// empty call. Statement types without editable fields are left alone.
+func syncStatement(call *build.CallExpr, old, stmt Statement) {
+	switch s := stmt.(type) {
+	case *ModuleDecl:
+		o := orZero[ModuleDecl](old)
+		setString(call, "name", o.Name.String(), s.Name.String())
+		setString(call, "version", o.Version.String(), s.Version.String())
+		setInt(call, "compatibility_level", o.CompatibilityLevel, s.CompatibilityLevel)
+		setString(call, "repo_name", o.RepoName.String(), s.RepoName.String())
+		setStringList(call, "bazel_compatibility", o.BazelCompatibility, s.BazelCompatibility)
+	case *BazelDep:
+		o := orZero[BazelDep](old)
+		setString(call, "name", o.Name.String(), s.Name.String())
+		setString(call, "version", o.Version.String(), s.Version.String())
+		setInt(call, "max_compatibility_level", o.MaxCompatibilityLevel, s.MaxCompatibilityLevel)
+		setString(call, "repo_name", o.RepoName.String(), s.RepoName.String())
+		setBool(call, "dev_dependency", o.DevDependency, s.DevDependency)
+	case *SingleVersionOverride:
+		o := orZero[SingleVersionOverride](old)
+		setString(call, "module_name", o.Module.String(), s.Module.String())
+		setString(call, "version", o.Version.String(), s.Version.String())
+		setString(call, "registry", o.Registry, s.Registry)
+		setStringList(call, "patches", o.Patches, s.Patches)
+		setStringList(call, "patch_cmds", o.PatchCmds, s.PatchCmds)
+		setInt(call, "patch_strip", o.PatchStrip, s.PatchStrip)
+	case *MultipleVersionOverride:
+		o := orZero[MultipleVersionOverride](old)
+		setString(call, "module_name", o.Module.String(), s.Module.String())
+		setStringList(call, "versions", versionStrings(o.Versions), versionStrings(s.Versions))
+		setString(call, "registry", o.Registry, s.Registry)
+	case *GitOverride:
+		o := orZero[GitOverride](old)
+		setString(call, "module_name", o.Module.String(), s.Module.String())
+		setString(call, "remote", o.Remote, s.Remote)
+		setString(call, "commit", o.Commit, s.Commit)
+		setString(call, "tag", o.Tag, s.Tag)
+		setString(call, "branch", o.Branch, s.Branch)
+		setStringList(call, "patches", o.Patches, s.Patches)
+		setStringList(call, "patch_cmds", o.PatchCmds, s.PatchCmds)
+		setInt(call, "patch_strip", o.PatchStrip, s.PatchStrip)
+		setBool(call, "init_submodules", o.InitSubmodules, s.InitSubmodules)
+		setString(call, "strip_prefix", o.StripPrefix, s.StripPrefix)
+	case *ArchiveOverride:
+		o := orZero[ArchiveOverride](old)
+		setString(call, "module_name", o.Module.String(), s.Module.String())
+		setStringList(call, "urls", o.URLs, s.URLs)
+		setString(call, "integrity", o.Integrity, s.Integrity)
+		setString(call, "strip_prefix", o.StripPrefix, s.StripPrefix)
+		setStringList(call, "patches", o.Patches, s.Patches)
+		setStringList(call, "patch_cmds", o.PatchCmds, s.PatchCmds)
+		setInt(call, "patch_strip", o.PatchStrip, s.PatchStrip)
+	case *LocalPathOverride:
+		o := orZero[LocalPathOverride](old)
+		setString(call, "module_name", o.Module.String(), s.Module.String())
+		setString(call, "path", o.Path, s.Path)
+	case *RegisterToolchains:
+		o := orZero[RegisterToolchains](old)
+		setPositionalStrings(call, o.Patterns, s.Patterns)
+		setBool(call, "dev_dependency", o.DevDependency, s.DevDependency)
+	case *RegisterExecutionPlatforms:
+		o := orZero[RegisterExecutionPlatforms](old)
+		setPositionalStrings(call, o.Patterns, s.Patterns)
+		setBool(call, "dev_dependency", o.DevDependency, s.DevDependency)
+	case *Include:
+		o := orZero[Include](old)
+		if buildutil.String(call, "label") != "" {
+			setString(call, "label", o.Label, s.Label)
+		} else if o.Label != s.Label {
+			setPositionalStrings(call, []string{o.Label}, []string{s.Label})
+		}
+	case *FlagAlias:
+		o := orZero[FlagAlias](old)
+		setString(call, "name", o.Name, s.Name)
+		setString(call, "starlark_flag", o.StarlarkFlag, s.StarlarkFlag)
+	}
+}
+
+// orZero returns old as a *T, or a zero *T if old is of another type.
+func orZero[T any, P interface {
+	*T
+	Statement
+}](old Statement) P {
+	if o, ok := old.(P); ok && o != nil {
+		return o
+	}
+	return P(new(T))
+}
+
+func versionStrings(versions []label.Version) []string {
+	if len(versions) == 0 {
+		return nil
+	}
+	out := make([]string, len(versions))
+	for i, v := range versions {
+		out[i] = v.String()
+	}
+	return out
+}
+
+func setString(call *build.CallExpr, name, old, value string) {
+	if old == value {
+		return
+	}
+	if value == "" {
+		deleteAttr(call, name)
+		return
+	}
+	setAttr(call, name, &build.StringExpr{Value: value})
+}
+
+func setInt(call *build.CallExpr, name string, old, value int) {
+	if old == value {
+		return
+	}
+	if value == 0 {
+		deleteAttr(call, name)
+		return
+	}
+	setAttr(call, name, &build.LiteralExpr{Token: strconv.Itoa(value)})
+}
+
+func setBool(call *build.CallExpr, name string, old, value bool) {
+	if old == value {
+		return
+	}
+	if !value {
+		deleteAttr(call, name)
+		return
+	}
+	setAttr(call, name, &build.Ident{Name: "True"})
+}
+
+func setStringList(call *build.CallExpr, name string, old, value []string) {
+	if slices.Equal(old, value) {
+		return
+	}
+	if len(value) == 0 {
+		deleteAttr(call, name)
+		return
+	}
+	setAttr(call, name, stringList(value))
+}
+
+// setPositionalStrings replaces the positional string arguments of call.
+func setPositionalStrings(call *build.CallExpr, old, value []string) {
+	if slices.Equal(old, value) {
+		return
+	}
+	args := make([]build.Expr, 0, len(value)+len(call.List))
+	for _, v := range value {
+		args = append(args, &build.StringExpr{Value: v})
+	}
+	for _, arg := range call.List {
+		if _, ok := arg.(*build.StringExpr); !ok {
+			args = append(args, arg)
+		}
+	}
+	call.List = args
+}
+
+func stringList(values []string) *build.ListExpr {
+	list := &build.ListExpr{ForceMultiLine: len(values) > 1}
+	for _, v := range values {
+		list.List = append(list.List, &build.StringExpr{Value: v})
+	}
+	return list
+}
+
+// setAttr sets the keyword argument name of call to value, keeping the
+// comments of an existing argument, or appends it.
+func setAttr(call *build.CallExpr, name string, value build.Expr) {
+	for _, arg := range call.List {
+		if assign, ok := arg.(*build.AssignExpr); ok && attrName(assign) == name {
+			assign.RHS = value
+			return
+		}
+	}
+	call.List = append(call.List, &build.AssignExpr{
+		LHS: &build.Ident{Name: name},
+		Op:  "=",
+		RHS: value,
+	})
+}
+
+func deleteAttr(call *build.CallExpr, name string) {
+	call.List = slices.DeleteFunc(call.List, func(arg build.Expr) bool {
+		assign, ok := arg.(*build.AssignExpr)
+		return ok && attrName(assign) == name
+	})
+}
+
+func attrName(assign *build.AssignExpr) string {
+	if ident, ok := assign.LHS.(*build.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
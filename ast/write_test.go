@@ -0,0 +1,148 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/albertocavalcante/go-bzlmod/label"
+)
+
+func TestWrite_EditVersion(t *testing.T) {
+	content := `# My module.
+module(name = "my_module", version = "1.0.0")
+
+# Go rules.
+bazel_dep(name = "rules_go", version = "0.50.1", repo_name = "io_bazel_rules_go")  # keep
+bazel_dep(name = "gazelle", version = GAZELLE_VERSION)
+
+go_sdk = use_extension("@rules_go//go:extensions.bzl", "go_sdk")
+go_sdk.download(version = "1.23.0")
+use_repo(go_sdk, "go_toolchains")
+`
+	// buildifier puts the attributes of module() on separate lines.
+	want := `# My module.
+module(
+    name = "my_module",
+    version = "1.0.0",
+)
+
+# Go rules.
+bazel_dep(name = "rules_go", version = "0.51.0", repo_name = "io_bazel_rules_go")  # keep
+bazel_dep(name = "gazelle", version = GAZELLE_VERSION)
+
+go_sdk = use_extension("@rules_go//go:extensions.bzl", "go_sdk")
+go_sdk.download(version = "1.23.0")
+use_repo(go_sdk, "go_toolchains")
+`
+	result, err := ParseContent("MODULE.bazel", []byte(content))
+	if err != nil {
+		t.Fatalf("ParseContent error: %v", err)
+	}
+	for _, stmt := range result.File.Statements {
+		if dep, ok := stmt.(*BazelDep); ok && dep.Name.String() == "rules_go" {
+			dep.Version = label.MustVersion("0.51.0")
+		}
+	}
+
+	got, err := Write(result.File)
+	if err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Writing again without edits is stable.
+	again, err := Write(result.File)
+	if err != nil || string(again) != want {
+		t.Errorf("second Write() = %q, %v; want unchanged output", again, err)
+	}
+}
+
+func TestWrite_AddAndRemoveStatements(t *testing.T) {
+	content := `module(name = "my_module")
+
+bazel_dep(name = "old_dep", version = "1.0")
+
+# Pinned.
+bazel_dep(name = "rules_cc", version = "0.1.0")
+`
+	// buildifier separates dev from non-dev dependencies and keeps an
+	// override next to the bazel_dep it overrides.
+	want := `module(name = "my_module")
+
+# Pinned.
+bazel_dep(name = "rules_cc", version = "0.1.0")
+
+bazel_dep(name = "rules_java", version = "8.0.0", dev_dependency = True)
+single_version_override(
+    module_name = "rules_java",
+    version = "8.0.0",
+    patches = [
+        "//patches:a.patch",
+        "//patches:b.patch",
+    ],
+    patch_strip = 1,
+)
+`
+	result, err := ParseContent("MODULE.bazel", []byte(content))
+	if err != nil {
+		t.Fatalf("ParseContent error: %v", err)
+	}
+	f := result.File
+	f.Statements = append([]Statement{f.Statements[0], f.Statements[2]},
+		&BazelDep{
+			Name:          label.MustModule("rules_java"),
+			Version:       label.MustVersion("8.0.0"),
+			DevDependency: true,
+		},
+		&SingleVersionOverride{
+			Module:     label.MustModule("rules_java"),
+			Version:    label.MustVersion("8.0.0"),
+			Patches:    []string{"//patches:a.patch", "//patches:b.patch"},
+			PatchStrip: 1,
+		},
+	)
+
+	got, err := Write(f)
+	if err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", got, want)
+	}
+
+	// The written file parses back into the same statements.
+	reparsed, err := ParseContent("MODULE.bazel", got)
+	if err != nil {
+		t.Fatalf("ParseContent(written) error: %v", err)
+	}
+	if len(reparsed.File.Statements) != 4 {
+		t.Errorf("written file has %d statements, want 4", len(reparsed.File.Statements))
+	}
+}
+
+func TestWrite_UnsupportedNewStatement(t *testing.T) {
+	f := &ModuleFile{Statements: []Statement{&UseRepo{Repos: []string{"foo"}}}}
+	if _, err := Write(f); err == nil {
+		t.Error("Write() with a new use_repo succeeded, want error")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	content := `bazel_dep(dev_dependency=True, version="1.0", name="foo")
+local_path_override(path="../foo", module_name="foo")
+`
+	want := `bazel_dep(name = "foo", version = "1.0", dev_dependency = True)
+local_path_override(
+    module_name = "foo",
+    path = "../foo",
+)
+`
+	got, err := Format([]byte(content))
+	if err != nil {
+		t.Fatalf("Format error: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("Format() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,272 @@
+// Package cache provides ModuleCache implementations for gobzlmod.WithCache.
+//
+// DiskCache persists MODULE.bazel files across resolutions and processes:
+//
+//	c := cache.NewDiskCache(filepath.Join(os.Getenv("HOME"), ".cache", "bzlmod"),
+//	    cache.WithMaxBytes(64<<20))
+//	result, err := gobzlmod.Resolve(ctx, src, gobzlmod.WithCache(c))
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// moduleFileName is the name of the file each cached module version is
+// stored in.
+const moduleFileName = "MODULE.bazel"
+
+// Directory and file permissions of the cache tree.
+const (
+	dirPermissions  = 0o755
+	filePermissions = 0o644
+)
+
+// DiskCache stores MODULE.bazel content under
+// rootDir/{name}/{version}/MODULE.bazel. It satisfies gobzlmod.ModuleCache.
+//
+// Put writes to a temporary file in the target directory and renames it into
+// place, so a concurrent Get, in this or another process, sees either no file
+// or the complete one. A DiskCache is safe for concurrent use.
+//
+// With WithMaxBytes, the cache evicts least recently used module files once
+// their total size exceeds the limit. Recency survives restarts: a hit
+// updates the file's modification time, and the first use of the cache
+// orders the files already on disk by it.
+type DiskCache struct {
+	root     string
+	maxBytes int64
+
+	loadOnce sync.Once
+	loadErr  error
+
+	mu    sync.Mutex
+	lru   *list.List               // *diskEntry, most recently used first
+	index map[string]*list.Element // keyed by "name@version"
+	size  int64                    // total size of the indexed files
+}
+
+// diskEntry is one cached module file tracked for LRU eviction.
+type diskEntry struct {
+	name, version string
+	size          int64
+}
+
+// DiskCacheOption configures a DiskCache.
+type DiskCacheOption func(*DiskCache)
+
+// WithMaxBytes bounds the total size of the cached MODULE.bazel files.
+// Least recently used files are evicted as soon as the limit is exceeded.
+// n <= 0 means unbounded, the default.
+func WithMaxBytes(n int64) DiskCacheOption {
+	return func(c *DiskCache) {
+		c.maxBytes = n
+	}
+}
+
+// NewDiskCache returns a cache rooted at rootDir. The directory is created on
+// the first Put.
+func NewDiskCache(rootDir string, opts ...DiskCacheOption) *DiskCache {
+	c := &DiskCache{
+		root:  rootDir,
+		lru:   list.New(),
+		index: make(map[string]*list.Element),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Get returns the cached MODULE.bazel content of name@version.
+func (c *DiskCache) Get(ctx context.Context, name, version string) ([]byte, bool, error) {
+	path, err := c.path(name, version)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := c.load(); err != nil {
+		return nil, false, err
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Recency is best effort: a failure to record it does not fail the hit.
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	c.mu.Lock()
+	c.track(name, version, int64(len(content)))
+	c.evict()
+	c.mu.Unlock()
+
+	return content, true, nil
+}
+
+// Put stores the MODULE.bazel content of name@version, replacing any cached
+// copy, and evicts least recently used files if the cache is over its limit.
+func (c *DiskCache) Put(ctx context.Context, name, version string, content []byte) error {
+	path, err := c.path(name, version)
+	if err != nil {
+		return err
+	}
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	// Eviction may remove the directory, once empty, between MkdirAll and the
+	// write; create it again in that case.
+	for attempt := 0; ; attempt++ {
+		if err := os.MkdirAll(filepath.Dir(path), dirPermissions); err != nil {
+			return err
+		}
+		err := writeFileAtomic(path, content)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, fs.ErrNotExist) || attempt == 2 {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.track(name, version, int64(len(content)))
+	c.evict()
+	return nil
+}
+
+// Size returns the total size in bytes of the cached module files.
+func (c *DiskCache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// path returns the file name@version is cached in. name and version must be
+// single path elements so entries cannot escape the cache root.
+func (c *DiskCache) path(name, version string) (string, error) {
+	for _, elem := range []string{name, version} {
+		if !filepath.IsLocal(elem) || strings.ContainsAny(elem, `/\`) {
+			return "", fmt.Errorf("cache: invalid module key %s@%s", name, version)
+		}
+	}
+	return filepath.Join(c.root, name, version, moduleFileName), nil
+}
+
+// load indexes the files already in the cache directory, oldest first, the
+// first time the cache is used.
+func (c *DiskCache) load() error {
+	c.loadOnce.Do(func() {
+		type file struct {
+			entry   diskEntry
+			modTime time.Time
+		}
+		var files []file
+		matches, err := filepath.Glob(filepath.Join(c.root, "*", "*", moduleFileName))
+		if err != nil {
+			c.loadErr = err
+			return
+		}
+		for _, m := range matches {
+			info, err := os.Stat(m)
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			versionDir := filepath.Dir(m)
+			files = append(files, file{
+				entry: diskEntry{
+					name:    filepath.Base(filepath.Dir(versionDir)),
+					version: filepath.Base(versionDir),
+					size:    info.Size(),
+				},
+				modTime: info.ModTime(),
+			})
+		}
+		slices.SortStableFunc(files, func(a, b file) int {
+			return a.modTime.Compare(b.modTime)
+		})
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for _, f := range files {
+			c.track(f.entry.name, f.entry.version, f.entry.size)
+		}
+	})
+	return c.loadErr
+}
+
+// track marks name@version, of the given size, as the most recently used
+// entry. c.mu must be held.
+func (c *DiskCache) track(name, version string, size int64) {
+	key := name + "@" + version
+	if elem, ok := c.index[key]; ok {
+		entry := elem.Value.(*diskEntry)
+		c.size += size - entry.size
+		entry.size = size
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.index[key] = c.lru.PushFront(&diskEntry{name: name, version: version, size: size})
+	c.size += size
+}
+
+// evict removes least recently used files until the cache fits its limit. The
+// most recently used entry is never evicted, so a file larger than the limit
+// stays cached until another one is used. c.mu must be held.
+func (c *DiskCache) evict() {
+	if c.maxBytes <= 0 {
+		return
+	}
+	for c.size > c.maxBytes && c.lru.Len() > 1 {
+		elem := c.lru.Back()
+		entry := elem.Value.(*diskEntry)
+		versionDir := filepath.Join(c.root, entry.name, entry.version)
+		if err := os.Remove(filepath.Join(versionDir, moduleFileName)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return // keep the entry accounted for; retry on the next Put
+		}
+		// Drop the directories if they are now empty.
+		_ = os.Remove(versionDir)
+		_ = os.Remove(filepath.Dir(versionDir))
+
+		c.lru.Remove(elem)
+		delete(c.index, entry.name+"@"+entry.version)
+		c.size -= entry.size
+	}
+}
+
+// writeFileAtomic writes content to a temporary file next to path and renames
+// it over path.
+func writeFileAtomic(path string, content []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+moduleFileName+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(filePermissions); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
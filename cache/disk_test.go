@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	gobzlmod "github.com/albertocavalcante/go-bzlmod"
+)
+
+var _ gobzlmod.ModuleCache = (*DiskCache)(nil)
+
+func TestDiskCache_GetPut(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	c := NewDiskCache(root)
+
+	if _, found, err := c.Get(ctx, "rules_go", "0.50.1"); err != nil || found {
+		t.Fatalf("Get() on empty cache = found %v, err %v; want miss", found, err)
+	}
+
+	content := []byte(`module(name = "rules_go", version = "0.50.1")`)
+	if err := c.Put(ctx, "rules_go", "0.50.1", content); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	onDisk, err := os.ReadFile(filepath.Join(root, "rules_go", "0.50.1", "MODULE.bazel"))
+	if err != nil || string(onDisk) != string(content) {
+		t.Errorf("file on disk = %q, %v; want %q", onDisk, err, content)
+	}
+
+	// A fresh cache over the same directory sees the entry.
+	got, found, err := NewDiskCache(root).Get(ctx, "rules_go", "0.50.1")
+	if err != nil || !found || string(got) != string(content) {
+		t.Errorf("Get() = %q, %v, %v; want %q, true, nil", got, found, err, content)
+	}
+
+	entries, _ := os.ReadDir(filepath.Join(root, "rules_go", "0.50.1"))
+	if len(entries) != 1 {
+		t.Errorf("version directory has %d entries, want only MODULE.bazel", len(entries))
+	}
+}
+
+func TestDiskCache_InvalidKey(t *testing.T) {
+	c := NewDiskCache(t.TempDir())
+	for _, key := range [][2]string{{"..", "1.0"}, {"a/b", "1.0"}, {"mod", "../1.0"}, {"", "1.0"}} {
+		if err := c.Put(context.Background(), key[0], key[1], []byte("x")); err == nil {
+			t.Errorf("Put(%q, %q) succeeded, want error", key[0], key[1])
+		}
+	}
+}
+
+func TestDiskCache_MaxBytesEvictsLRU(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	c := NewDiskCache(root, WithMaxBytes(30))
+	content := []byte("0123456789") // 10 bytes
+
+	for _, v := range []string{"1.0", "2.0", "3.0"} {
+		if err := c.Put(ctx, "mod", v, content); err != nil {
+			t.Fatalf("Put(%s) error = %v", v, err)
+		}
+	}
+	// Touch 1.0 so 2.0 becomes the least recently used.
+	if _, found, _ := c.Get(ctx, "mod", "1.0"); !found {
+		t.Fatal("Get(1.0) missed")
+	}
+	if err := c.Put(ctx, "mod", "4.0", content); err != nil {
+		t.Fatalf("Put(4.0) error = %v", err)
+	}
+
+	for v, want := range map[string]bool{"1.0": true, "2.0": false, "3.0": true, "4.0": true} {
+		if _, found, _ := c.Get(ctx, "mod", v); found != want {
+			t.Errorf("Get(%s) found = %v, want %v", v, found, want)
+		}
+	}
+	if c.Size() != 30 {
+		t.Errorf("Size() = %d, want 30", c.Size())
+	}
+	if _, err := os.Stat(filepath.Join(root, "mod", "2.0")); !os.IsNotExist(err) {
+		t.Errorf("evicted version directory still exists: %v", err)
+	}
+}
+
+func TestDiskCache_LoadsExistingFilesInLRUOrder(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	old := time.Now().Add(-time.Hour)
+	for i, v := range []string{"1.0", "2.0"} {
+		dir := filepath.Join(root, "mod", v)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, "MODULE.bazel")
+		if err := os.WriteFile(path, []byte("0123456789"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		mtime := old.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := NewDiskCache(root, WithMaxBytes(20))
+	if err := c.Put(ctx, "other", "1.0", []byte("0123456789")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, found, _ := c.Get(ctx, "mod", "1.0"); found {
+		t.Error("oldest file on disk was not evicted")
+	}
+	if _, found, _ := c.Get(ctx, "mod", "2.0"); !found {
+		t.Error("newer file on disk was evicted")
+	}
+}
+
+func TestDiskCache_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	c := NewDiskCache(t.TempDir(), WithMaxBytes(200))
+
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Go(func() {
+			for j := range 20 {
+				version := fmt.Sprintf("%d.0", j%10)
+				content := []byte("module(name = \"mod\", version = \"" + version + "\")")
+				if err := c.Put(ctx, "mod", version, content); err != nil {
+					t.Errorf("worker %d: Put() error = %v", i, err)
+				}
+				if got, found, err := c.Get(ctx, "mod", version); err != nil {
+					t.Errorf("worker %d: Get() error = %v", i, err)
+				} else if found && string(got) != string(content) {
+					t.Errorf("worker %d: Get() = %q, want %q", i, got, content)
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	if c.Size() > 200 {
+		t.Errorf("Size() = %d, want <= 200", c.Size())
+	}
+}
@@ -2,8 +2,11 @@ package gobzlmod
 
 import (
 	"cmp"
+	"maps"
 	"slices"
+	"strings"
 
+	"github.com/albertocavalcante/go-bzlmod/graph"
 	"github.com/albertocavalcante/go-bzlmod/selection/version"
 )
 
@@ -58,6 +61,9 @@ type ResolutionDiff struct {
 
 	// Downgraded contains modules where the new version is lower.
 	Downgraded []ModuleUpgrade `json:"downgraded,omitempty"`
+
+	// oldList and newList are the compared resolutions, kept for IndirectBumps.
+	oldList, newList *ResolutionList
 }
 
 // IsEmpty returns true if there are no differences between the resolutions.
@@ -90,7 +96,7 @@ func (d *ResolutionDiff) TotalChanges() int {
 //
 // Results are sorted alphabetically by module name for consistent output.
 func DiffResolutions(oldList, newList *ResolutionList) *ResolutionDiff {
-	diff := &ResolutionDiff{}
+	diff := &ResolutionDiff{oldList: oldList, newList: newList}
 
 	// Build lookup maps for O(1) comparison
 	oldModules := make(map[string]string) // name -> version
@@ -155,6 +161,204 @@ func DiffResolutions(oldList, newList *ResolutionList) *ResolutionDiff {
 	return diff
 }
 
+// IndirectBump is a version change of a module that the root module did not
+// touch: its own request for the module is the same in both resolutions, so
+// the change is a side effect of other modules' requirements.
+type IndirectBump struct {
+	// Name is the module name.
+	Name string `json:"name"`
+
+	// OldVersion is the version in the old resolution.
+	OldVersion string `json:"old_version"`
+
+	// NewVersion is the version in the new resolution.
+	NewVersion string `json:"new_version"`
+
+	// Causes lists the requesters whose requested version of the module
+	// changed between the resolutions, sorted by requester. It is empty when
+	// no request changed, for example when a non-root override moved the
+	// version.
+	Causes []RequestChange `json:"causes,omitempty"`
+}
+
+// RequestChange is a change in the version one module requests of another.
+type RequestChange struct {
+	// Requester is the name of the requesting module.
+	Requester string `json:"requester"`
+
+	// OldRequest is the version requested in the old resolution, or empty if
+	// the requester did not depend on the module.
+	OldRequest string `json:"old_request,omitempty"`
+
+	// NewRequest is the version requested in the new resolution, or empty if
+	// the requester no longer depends on the module.
+	NewRequest string `json:"new_request,omitempty"`
+}
+
+// IndirectBumps returns the upgrades and downgrades in d that the root module
+// did not cause directly, sorted by module name. A change is direct when the
+// root module requests the module at a different version (or only in one of
+// the resolutions); every other change is indirect and is attributed to the
+// requesters whose version requirement changed.
+//
+// Request data comes from the resolutions' dependency graphs, so this needs a
+// diff made by DiffResolutions from resolutions produced by Resolve. It
+// returns nil otherwise, for example for resolutions deserialized from JSON.
+func (d *ResolutionDiff) IndirectBumps() []IndirectBump {
+	if d.oldList == nil || d.newList == nil || d.oldList.Graph == nil || d.newList.Graph == nil {
+		return nil
+	}
+
+	var bumps []IndirectBump
+	for _, changes := range [][]ModuleUpgrade{d.Upgraded, d.Downgraded} {
+		for _, change := range changes {
+			oldRoot, oldRequests := moduleRequests(d.oldList.Graph, change.Name, change.OldVersion)
+			newRoot, newRequests := moduleRequests(d.newList.Graph, change.Name, change.NewVersion)
+			if oldRoot != newRoot {
+				continue
+			}
+
+			bump := IndirectBump{
+				Name:       change.Name,
+				OldVersion: change.OldVersion,
+				NewVersion: change.NewVersion,
+			}
+			requesters := slices.Sorted(maps.Keys(oldRequests))
+			for requester := range newRequests {
+				if _, ok := oldRequests[requester]; !ok {
+					requesters = append(requesters, requester)
+				}
+			}
+			slices.Sort(requesters)
+			for _, requester := range requesters {
+				if oldRequests[requester] != newRequests[requester] {
+					bump.Causes = append(bump.Causes, RequestChange{
+						Requester:  requester,
+						OldRequest: oldRequests[requester],
+						NewRequest: newRequests[requester],
+					})
+				}
+			}
+			bumps = append(bumps, bump)
+		}
+	}
+
+	slices.SortFunc(bumps, func(a, b IndirectBump) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return bumps
+}
+
+// moduleRequests returns the version the root of g requests of name@version
+// (empty if none) and the versions requested by every other module, keyed by
+// requester name.
+func moduleRequests(g *graph.Graph, name, version string) (root string, requests map[string]string) {
+	requests = make(map[string]string)
+	node := g.Modules[graph.ModuleKey{Name: name, Version: version}]
+	if node == nil {
+		return "", requests
+	}
+	for requester, requested := range node.RequestedVersions {
+		if requester == g.Root {
+			root = requested
+			continue
+		}
+		requests[requester.Name] = requested
+	}
+	return root, requests
+}
+
+// VersionJump classifies a version change by the most significant release
+// segment that changed.
+type VersionJump string
+
+const (
+	// VersionJumpMajor is a change of the first release segment.
+	VersionJumpMajor VersionJump = "major"
+
+	// VersionJumpMinor is a change of the second release segment.
+	VersionJumpMinor VersionJump = "minor"
+
+	// VersionJumpPatch is a change of the third release segment.
+	VersionJumpPatch VersionJump = "patch"
+
+	// VersionJumpPrerelease is a change of the prerelease only, with the same
+	// release (e.g. 1.0.0-rc1 to 1.0.0).
+	VersionJumpPrerelease VersionJump = "prerelease"
+
+	// VersionJumpOther is a change between versions that are not
+	// MAJOR[.MINOR[.PATCH]] with numeric segments, such as BCR patch releases
+	// ("1.2.3.bcr.1") or four-segment versions.
+	VersionJumpOther VersionJump = "other"
+)
+
+// DiffClassify classifies every upgrade and downgrade in d by the release
+// segment that changed, keyed by module name. Versions are parsed with Bazel's
+// version semantics; missing minor and patch segments count as 0, so 1 to
+// 1.0.1 is a patch change. Added and removed modules are not included.
+//
+// Example:
+//
+//	jumps := DiffClassify(diff)
+//	majors := 0
+//	for _, jump := range jumps {
+//	    if jump == VersionJumpMajor {
+//	        majors++
+//	    }
+//	}
+func DiffClassify(d *ResolutionDiff) map[string]VersionJump {
+	jumps := make(map[string]VersionJump, len(d.Upgraded)+len(d.Downgraded))
+	for _, changes := range [][]ModuleUpgrade{d.Upgraded, d.Downgraded} {
+		for _, change := range changes {
+			jumps[change.Name] = classifyVersionJump(change.OldVersion, change.NewVersion)
+		}
+	}
+	return jumps
+}
+
+// classifyVersionJump returns the VersionJump between two versions.
+func classifyVersionJump(oldVersion, newVersion string) VersionJump {
+	oldRelease, oldPre, ok := semverRelease(oldVersion)
+	if !ok {
+		return VersionJumpOther
+	}
+	newRelease, newPre, ok := semverRelease(newVersion)
+	if !ok {
+		return VersionJumpOther
+	}
+
+	switch {
+	case oldRelease[0] != newRelease[0]:
+		return VersionJumpMajor
+	case oldRelease[1] != newRelease[1]:
+		return VersionJumpMinor
+	case oldRelease[2] != newRelease[2]:
+		return VersionJumpPatch
+	case oldPre != newPre:
+		return VersionJumpPrerelease
+	}
+	return VersionJumpOther
+}
+
+// semverRelease returns the MAJOR, MINOR and PATCH release segments of v and
+// its prerelease, or false if the release is not one to three numbers.
+func semverRelease(v string) (release [3]uint64, prerelease string, ok bool) {
+	parsed, err := version.Parse(v)
+	if err != nil || parsed.IsEmpty || len(parsed.Release) > len(release) {
+		return release, "", false
+	}
+	for i, id := range parsed.Release {
+		if !id.IsDigitsOnly {
+			return release, "", false
+		}
+		release[i] = id.AsNumber
+	}
+	if _, pre, found := strings.Cut(parsed.Normalized, "-"); found {
+		prerelease = pre
+	}
+	return release, prerelease, true
+}
+
 // sortModuleChanges sorts a slice of ModuleChange by name.
 func sortModuleChanges(changes []ModuleChange) {
 	slices.SortFunc(changes, func(a, b ModuleChange) int {
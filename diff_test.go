@@ -1,6 +1,11 @@
 package gobzlmod
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"slices"
 	"testing"
 )
@@ -360,3 +365,104 @@ func BenchmarkDiffResolutions(b *testing.B) {
 		_ = DiffResolutions(old, new)
 	}
 }
+
+func TestDiffClassify(t *testing.T) {
+	oldList := &ResolutionList{
+		Modules: []ModuleToResolve{
+			{Name: "protobuf", Version: "27.0"},
+			{Name: "rules_go", Version: "0.49.0"},
+			{Name: "bazel_skylib", Version: "1.7.0"},
+			{Name: "abseil-cpp", Version: "20240116.2"},
+			{Name: "zlib", Version: "1.3.1.bcr.1"},
+			{Name: "rules_cc", Version: "0.1.0-rc1"},
+			{Name: "platforms", Version: "0.0.11"},
+		},
+	}
+	newList := &ResolutionList{
+		Modules: []ModuleToResolve{
+			{Name: "protobuf", Version: "29.0"},
+			{Name: "rules_go", Version: "0.50.1"},
+			{Name: "bazel_skylib", Version: "1.7.1"},
+			{Name: "abseil-cpp", Version: "20240722.0"},
+			{Name: "zlib", Version: "1.3.1.bcr.3"},
+			{Name: "rules_cc", Version: "0.1.0"},
+			{Name: "platforms", Version: "0.0.10"},
+		},
+	}
+
+	got := DiffClassify(DiffResolutions(oldList, newList))
+
+	want := map[string]VersionJump{
+		"protobuf":     VersionJumpMajor,
+		"rules_go":     VersionJumpMinor,
+		"bazel_skylib": VersionJumpPatch,
+		"abseil-cpp":   VersionJumpMajor,
+		"zlib":         VersionJumpOther,
+		"rules_cc":     VersionJumpPrerelease,
+		"platforms":    VersionJumpPatch, // downgrades are classified too
+	}
+	if len(got) != len(want) {
+		t.Errorf("DiffClassify() = %v, want %v", got, want)
+	}
+	for name, jump := range want {
+		if got[name] != jump {
+			t.Errorf("DiffClassify()[%s] = %q, want %q", name, got[name], jump)
+		}
+	}
+}
+
+func TestResolutionDiff_IndirectBumps(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "a", version = "1.0.0")
+bazel_dep(name = "c", version = "1.0.0")`)
+		case "/modules/a/1.1.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "a", version = "1.1.0")
+bazel_dep(name = "c", version = "1.2.0")`)
+		case "/modules/b/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "b", version = "1.0.0")
+bazel_dep(name = "c", version = "1.0.0")`)
+		case "/modules/c/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "c", version = "1.0.0")`)
+		case "/modules/c/1.2.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "c", version = "1.2.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolve := func(aVersion string) *ResolutionList {
+		t.Helper()
+		content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "a", version = "` + aVersion + `")
+bazel_dep(name = "b", version = "1.0.0")`
+		list, err := Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL))
+		if err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		return list
+	}
+
+	diff := DiffResolutions(resolve("1.0.0"), resolve("1.1.0"))
+	if len(diff.Upgraded) != 2 {
+		t.Fatalf("Upgraded = %v, want a and c", diff.Upgraded)
+	}
+
+	want := []IndirectBump{{
+		Name:       "c",
+		OldVersion: "1.0.0",
+		NewVersion: "1.2.0",
+		Causes:     []RequestChange{{Requester: "a", OldRequest: "1.0.0", NewRequest: "1.2.0"}},
+	}}
+	if got := diff.IndirectBumps(); !reflect.DeepEqual(got, want) {
+		t.Errorf("IndirectBumps() = %+v, want %+v", got, want)
+	}
+
+	// A diff without request data has nothing to attribute.
+	manual := &ResolutionDiff{Upgraded: diff.Upgraded}
+	if got := manual.IndirectBumps(); got != nil {
+		t.Errorf("IndirectBumps() on a hand-built diff = %+v, want nil", got)
+	}
+}
@@ -16,3 +16,7 @@ var (
 	// ErrUnauthorized indicates authentication is required or failed.
 	ErrUnauthorized = errors.New("unauthorized")
 )
+
+// ErrResolutionTimeout indicates ResolutionOptions.TotalTimeout ran out.
+// The error is a *ResolutionTimeoutError.
+var ErrResolutionTimeout = errors.New("resolution timed out")
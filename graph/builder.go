@@ -1,6 +1,10 @@
 package graph
 
 import (
+	"fmt"
+	"maps"
+	"slices"
+
 	"github.com/albertocavalcante/go-bzlmod/selection"
 )
 
@@ -39,6 +43,19 @@ func (b *Builder) RecordOverride(moduleName, version string) {
 	b.Overrides[moduleName] = version
 }
 
+// FromSelectionResult converts the output of selection.Run into a Graph, for
+// callers that use the selection package directly. Nodes keep their
+// compatibility levels, dependencies keep their declaration order, and
+// dependents are recorded in the result's BFS order, so ToText and the other
+// exporters walk the graph in the same order selection did.
+//
+// The result carries no pre-selection version requests, so Explain reports
+// every module as the only version requested. Use a Builder with
+// RecordRequest to include them.
+func FromSelectionResult(res *selection.Result, rootKey selection.ModuleKey) *Graph {
+	return NewBuilder().BuildFromSelection(res, rootKey)
+}
+
 // BuildFromSelection constructs a Graph from selection results.
 func (b *Builder) BuildFromSelection(result *selection.Result, rootKey selection.ModuleKey) *Graph {
 	g := &Graph{
@@ -49,18 +66,20 @@ func (b *Builder) BuildFromSelection(result *selection.Result, rootKey selection
 	// First pass: create all nodes
 	for selKey, module := range result.ResolvedGraph {
 		node := &Node{
-			Key:               selKey,
-			Dependencies:      make([]ModuleKey, 0, len(module.Deps)),
-			Dependents:        make([]ModuleKey, 0),
-			RequestedVersions: make(map[ModuleKey]string),
-			IsRoot:            selKey == rootKey,
+			Key:                selKey,
+			Dependencies:       make([]ModuleKey, 0, len(module.Deps)),
+			Dependents:         make([]ModuleKey, 0),
+			RequestedVersions:  make(map[ModuleKey]string),
+			IsRoot:             selKey == rootKey,
+			CompatibilityLevel: module.CompatLevel,
 		}
 
-		// Convert dependencies
+		// Convert dependencies. Deps in the resolved graph already name the
+		// selected versions; fall back to a lookup by name otherwise.
 		for _, dep := range module.Deps {
-			// Find the resolved version for this dependency
-			resolvedKey := b.findResolvedVersion(result.ResolvedGraph, dep.Name)
-			if resolvedKey != nil {
+			if _, ok := result.ResolvedGraph[dep.ToModuleKey()]; ok {
+				node.Dependencies = append(node.Dependencies, dep.ToModuleKey())
+			} else if resolvedKey := b.findResolvedVersion(result.ResolvedGraph, dep.Name); resolvedKey != nil {
 				node.Dependencies = append(node.Dependencies, *resolvedKey)
 			}
 		}
@@ -71,8 +90,16 @@ func (b *Builder) BuildFromSelection(result *selection.Result, rootKey selection
 		g.Modules[selKey] = node
 	}
 
-	// Second pass: build reverse edges (dependents)
-	for key, node := range g.Modules {
+	// Second pass: build reverse edges (dependents), in BFS order when known
+	order := result.BFSOrder
+	if len(order) != len(g.Modules) {
+		order = slices.SortedFunc(maps.Keys(g.Modules), compareModuleKeys)
+	}
+	for _, key := range order {
+		node := g.Modules[key]
+		if node == nil {
+			continue
+		}
 		for _, depKey := range node.Dependencies {
 			if depNode, ok := g.Modules[depKey]; ok {
 				depNode.Dependents = append(depNode.Dependents, key)
@@ -184,12 +211,13 @@ func Build(root ModuleKey, modules []SimpleModule) *Graph {
 	for _, m := range modules {
 		key := ModuleKey{Name: m.Name, Version: m.Version}
 		node := &Node{
-			Key:               key,
-			Dependencies:      make([]ModuleKey, len(m.Dependencies)),
-			Dependents:        make([]ModuleKey, 0),
-			RequestedVersions: make(map[ModuleKey]string),
-			IsRoot:            key == root,
-			DevDependency:     m.DevDependency,
+			Key:                key,
+			Dependencies:       make([]ModuleKey, len(m.Dependencies)),
+			Dependents:         make([]ModuleKey, 0),
+			RequestedVersions:  make(map[ModuleKey]string),
+			IsRoot:             key == root,
+			DevDependency:      m.DevDependency,
+			CompatibilityLevel: m.CompatibilityLevel,
 		}
 		copy(node.Dependencies, m.Dependencies)
 		g.Modules[key] = node
@@ -207,10 +235,58 @@ func Build(root ModuleKey, modules []SimpleModule) *Graph {
 	return g
 }
 
+// Subgraph returns a new graph containing root and its transitive
+// dependencies, with root as the graph's root. Edges from modules outside the
+// subgraph are dropped from Dependents and RequestedVersions; Selection info
+// is shared with g, so it still describes the selection in the full graph.
+func (g *Graph) Subgraph(root ModuleKey) (*Graph, error) {
+	if !g.Contains(root) {
+		return nil, fmt.Errorf("module %s not found in graph", root)
+	}
+
+	keys := append([]ModuleKey{root}, g.TransitiveDeps(root)...)
+	sub := &Graph{
+		Root:    root,
+		Modules: make(map[ModuleKey]*Node, len(keys)),
+	}
+	members := make(map[ModuleKey]bool, len(keys))
+	for _, key := range keys {
+		members[key] = true
+	}
+
+	for _, key := range keys {
+		node := g.Modules[key]
+		subNode := &Node{
+			Key:                key,
+			Dependencies:       slices.Clone(node.Dependencies),
+			Dependents:         make([]ModuleKey, 0, len(node.Dependents)),
+			RequestedVersions:  make(map[ModuleKey]string, len(node.RequestedVersions)),
+			Selection:          node.Selection,
+			IsRoot:             key == root,
+			DevDependency:      node.DevDependency,
+			CompatibilityLevel: node.CompatibilityLevel,
+		}
+		for _, dependent := range node.Dependents {
+			if members[dependent] {
+				subNode.Dependents = append(subNode.Dependents, dependent)
+			}
+		}
+		for requester, version := range node.RequestedVersions {
+			if members[requester] {
+				subNode.RequestedVersions[requester] = version
+			}
+		}
+		sub.Modules[key] = subNode
+	}
+
+	return sub, nil
+}
+
 // SimpleModule is a simplified module representation for building graphs.
 type SimpleModule struct {
-	Name          string
-	Version       string
-	Dependencies  []ModuleKey
-	DevDependency bool
+	Name               string
+	Version            string
+	Dependencies       []ModuleKey
+	DevDependency      bool
+	CompatibilityLevel int
 }
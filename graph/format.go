@@ -5,6 +5,8 @@ import (
 	"cmp"
 	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
 	"slices"
 	"strings"
 )
@@ -32,7 +34,9 @@ type BazelDependency struct {
 	Unexpanded           bool              `json:"unexpanded,omitempty"`
 }
 
-// ToJSON outputs the graph in Bazel-compatible mod graph JSON format.
+// ToJSON outputs the graph in Bazel-compatible mod graph JSON format: a tree
+// nested from the root, in which each module's dependencies are expanded at
+// its first occurrence in breadth-first order and marked unexpanded elsewhere.
 func (g *Graph) ToJSON() ([]byte, error) {
 	bazelGraph := g.toBazelFormat()
 	return json.MarshalIndent(bazelGraph, "", "  ")
@@ -45,7 +49,6 @@ func (g *Graph) toBazelFormat() *BazelModGraph {
 		return &BazelModGraph{}
 	}
 
-	visited := make(map[ModuleKey]bool)
 	cycles := g.FindCycles()
 	cycleKeys := make(map[ModuleKey]bool)
 	for _, cycle := range cycles {
@@ -59,30 +62,52 @@ func (g *Graph) toBazelFormat() *BazelModGraph {
 		Name:         g.Root.Name,
 		Version:      g.Root.Version,
 		Root:         true,
-		Dependencies: g.buildBazelDeps(rootNode, visited, cycleKeys),
+		Dependencies: g.buildBazelDeps(rootNode, g.bfsExpansionParents(), cycleKeys),
 	}
 }
 
+// bfsExpansionParents maps each module reachable from the root to the module
+// under which the Bazel tree expands it: the one whose edge first reaches it
+// in breadth-first order. Like Bazel, this expands every module at its
+// shallowest occurrence; every other occurrence is unexpanded.
+func (g *Graph) bfsExpansionParents() map[ModuleKey]ModuleKey {
+	parents := make(map[ModuleKey]ModuleKey)
+	seen := map[ModuleKey]bool{g.Root: true}
+	queue := []ModuleKey{g.Root}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dep := range g.DirectDeps(current) {
+			if !seen[dep] {
+				seen[dep] = true
+				parents[dep] = current
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return parents
+}
+
 // buildBazelDeps recursively builds Bazel-format dependencies.
-func (g *Graph) buildBazelDeps(node *Node, visited, cycleKeys map[ModuleKey]bool) []BazelDependency {
+func (g *Graph) buildBazelDeps(node *Node, parents map[ModuleKey]ModuleKey, cycleKeys map[ModuleKey]bool) []BazelDependency {
 	if node == nil {
 		return nil
 	}
 
 	deps := make([]BazelDependency, 0, len(node.Dependencies))
+	expanded := make(map[ModuleKey]bool)
 
 	for _, depKey := range node.Dependencies {
-		if visited[depKey] {
-			// Already visited, mark as unexpanded to avoid infinite recursion
+		if parent, ok := parents[depKey]; !ok || parent != node.Key || expanded[depKey] {
+			// Expanded elsewhere in the tree; marking it unexpanded also
+			// avoids infinite recursion
 			deps = append(deps, BazelDependency{
 				Key:        depKey.String(),
 				Unexpanded: true,
 			})
 			continue
 		}
-
-		visited[depKey] = true
-		depNode := g.Modules[depKey]
+		expanded[depKey] = true
 
 		bazelDep := BazelDependency{
 			Key: depKey.String(),
@@ -91,8 +116,8 @@ func (g *Graph) buildBazelDeps(node *Node, visited, cycleKeys map[ModuleKey]bool
 		if cycleKeys[depKey] {
 			// This node is part of a cycle
 			bazelDep.Cycles = []BazelDependency{{Key: depKey.String()}}
-		} else if depNode != nil {
-			bazelDep.Dependencies = g.buildBazelDeps(depNode, visited, cycleKeys)
+		} else if depNode := g.Modules[depKey]; depNode != nil {
+			bazelDep.Dependencies = g.buildBazelDeps(depNode, parents, cycleKeys)
 		}
 
 		deps = append(deps, bazelDep)
@@ -101,33 +126,342 @@ func (g *Graph) buildBazelDeps(node *Node, visited, cycleKeys map[ModuleKey]bool
 	return deps
 }
 
+// FlatModGraphNode is one module in the document produced by ToFlatJSON.
+type FlatModGraphNode struct {
+	Key          string   `json:"key"`
+	Dependencies []string `json:"dependencies,omitempty"`
+	Root         bool     `json:"root,omitempty"`
+}
+
+// ToFlatJSON outputs the graph as a flat list of modules, each with the keys
+// of its direct dependencies. The root comes first, followed by the other
+// modules in name, then version order. Unlike ToJSON, every module appears
+// exactly once and nothing is nested, which suits tools that index by key.
+func (g *Graph) ToFlatJSON() ([]byte, error) {
+	var keys []ModuleKey
+	if g.Modules[g.Root] != nil {
+		keys = append(keys, g.Root)
+	}
+	for _, key := range slices.SortedFunc(maps.Keys(g.Modules), compareModuleKeys) {
+		if key != g.Root {
+			keys = append(keys, key)
+		}
+	}
+
+	nodes := make([]FlatModGraphNode, 0, len(keys))
+	for _, key := range keys {
+		node := g.Modules[key]
+		flat := FlatModGraphNode{Key: key.String(), Root: node.IsRoot}
+		for _, dep := range node.Dependencies {
+			flat.Dependencies = append(flat.Dependencies, dep.String())
+		}
+		nodes = append(nodes, flat)
+	}
+	return json.MarshalIndent(nodes, "", "  ")
+}
+
+// NDJSONModule is one line of WriteNDJSON output.
+type NDJSONModule struct {
+	Key          string   `json:"key"`
+	Name         string   `json:"name"`
+	Version      string   `json:"version"`
+	Dependencies []string `json:"dependencies"`
+
+	// Depth is the shortest distance from the root, which has depth 0, or -1
+	// if the module is unreachable from the root.
+	Depth int `json:"depth"`
+
+	DevDependency bool `json:"devDependency"`
+}
+
+// WriteNDJSON writes the graph as newline-delimited JSON, one NDJSONModule
+// per line, so consumers can process modules as they arrive. Modules are
+// written in breadth-first order from the root, visiting dependencies in
+// declaration order, followed by any modules unreachable from the root in
+// name, then version order.
+func (g *Graph) WriteNDJSON(w io.Writer) error {
+	depths := g.depthsFromRoot()
+
+	var order []ModuleKey
+	seen := make(map[ModuleKey]bool, len(g.Modules))
+	if g.Modules[g.Root] != nil {
+		order = append(order, g.Root)
+		seen[g.Root] = true
+	}
+	for i := 0; i < len(order); i++ {
+		for _, dep := range g.Modules[order[i]].Dependencies {
+			if !seen[dep] && g.Modules[dep] != nil {
+				seen[dep] = true
+				order = append(order, dep)
+			}
+		}
+	}
+	for _, key := range slices.SortedFunc(maps.Keys(g.Modules), compareModuleKeys) {
+		if !seen[key] {
+			order = append(order, key)
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, key := range order {
+		node := g.Modules[key]
+		line := NDJSONModule{
+			Key:           key.String(),
+			Name:          key.Name,
+			Version:       key.Version,
+			Dependencies:  make([]string, 0, len(node.Dependencies)),
+			Depth:         -1,
+			DevDependency: node.DevDependency,
+		}
+		if depth, ok := depths[key]; ok {
+			line.Depth = depth
+		}
+		for _, dep := range node.Dependencies {
+			line.Dependencies = append(line.Dependencies, dep.String())
+		}
+		if err := encoder.Encode(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExtendedModGraph is the document produced by ToJSONExtended. It has every
+// field of BazelModGraph, plus go-bzlmod resolution metadata for the root.
+type ExtendedModGraph struct {
+	Key                  string               `json:"key"`
+	Name                 string               `json:"name,omitempty"`
+	Version              string               `json:"version,omitempty"`
+	Dependencies         []ExtendedDependency `json:"dependencies,omitempty"`
+	IndirectDependencies []ExtendedDependency `json:"indirectDependencies,omitempty"`
+	Cycles               []ExtendedDependency `json:"cycles,omitempty"`
+	Root                 bool                 `json:"root,omitempty"`
+	NodeMetadata
+}
+
+// ExtendedDependency is a BazelDependency with go-bzlmod resolution metadata.
+type ExtendedDependency struct {
+	Key                  string               `json:"key"`
+	Dependencies         []ExtendedDependency `json:"dependencies,omitempty"`
+	IndirectDependencies []ExtendedDependency `json:"indirectDependencies,omitempty"`
+	Cycles               []ExtendedDependency `json:"cycles,omitempty"`
+	Unexpanded           bool                 `json:"unexpanded,omitempty"`
+	NodeMetadata
+}
+
+// NodeMetadata is the go-bzlmod extension attached to every node by
+// ToJSONExtended. Bazel's mod graph output has no equivalent.
+type NodeMetadata struct {
+	// Depth is the shortest distance from the root, which has depth 0.
+	Depth int `json:"depth"`
+
+	// DevDependency is true if the module is only a dev dependency.
+	DevDependency bool `json:"devDependency"`
+
+	// CompatibilityLevel is the module's declared compatibility_level.
+	CompatibilityLevel int `json:"compatibilityLevel"`
+
+	// RequesterCount is the number of modules that directly depend on this one.
+	RequesterCount int `json:"requesterCount"`
+
+	// RequiredBy lists the keys of the modules that directly depend on this
+	// one, sorted as ReverseDeps sorts them.
+	RequiredBy []string `json:"requiredBy,omitempty"`
+}
+
+// ToJSONExtended outputs the graph in the same shape as ToJSON, with
+// NodeMetadata fields added to every node. This is a go-bzlmod extension:
+// removing those fields yields exactly the ToJSON document, so consumers of
+// Bazel's format can read it, but Bazel itself never produces it.
+func (g *Graph) ToJSONExtended() ([]byte, error) {
+	depths := g.depthsFromRoot()
+	bazelGraph := g.toBazelFormat()
+
+	extended := &ExtendedModGraph{
+		Key:                  bazelGraph.Key,
+		Name:                 bazelGraph.Name,
+		Version:              bazelGraph.Version,
+		Dependencies:         g.extendDeps(bazelGraph.Dependencies, depths),
+		IndirectDependencies: g.extendDeps(bazelGraph.IndirectDependencies, depths),
+		Cycles:               g.extendDeps(bazelGraph.Cycles, depths),
+		Root:                 bazelGraph.Root,
+	}
+	if bazelGraph.Root {
+		extended.NodeMetadata = g.nodeMetadata(g.Root, depths)
+	}
+	return json.MarshalIndent(extended, "", "  ")
+}
+
+// extendDeps attaches NodeMetadata to a Bazel-format dependency tree.
+func (g *Graph) extendDeps(deps []BazelDependency, depths map[ModuleKey]int) []ExtendedDependency {
+	if deps == nil {
+		return nil
+	}
+	extended := make([]ExtendedDependency, len(deps))
+	for i, dep := range deps {
+		extended[i] = ExtendedDependency{
+			Key:                  dep.Key,
+			Dependencies:         g.extendDeps(dep.Dependencies, depths),
+			IndirectDependencies: g.extendDeps(dep.IndirectDependencies, depths),
+			Cycles:               g.extendDeps(dep.Cycles, depths),
+			Unexpanded:           dep.Unexpanded,
+			NodeMetadata:         g.nodeMetadata(parseModuleKey(dep.Key), depths),
+		}
+	}
+	return extended
+}
+
+func (g *Graph) nodeMetadata(key ModuleKey, depths map[ModuleKey]int) NodeMetadata {
+	meta := NodeMetadata{Depth: depths[key]}
+	if node := g.Modules[key]; node != nil {
+		meta.DevDependency = node.DevDependency
+		meta.CompatibilityLevel = node.CompatibilityLevel
+		meta.RequesterCount = len(node.Dependents)
+		for _, dependent := range g.ReverseDeps(key) {
+			meta.RequiredBy = append(meta.RequiredBy, dependent.String())
+		}
+	}
+	return meta
+}
+
+// depthsFromRoot returns the shortest distance from the root to every
+// reachable module, computed breadth-first.
+func (g *Graph) depthsFromRoot() map[ModuleKey]int {
+	depths := map[ModuleKey]int{g.Root: 0}
+	queue := []ModuleKey{g.Root}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dep := range g.DirectDeps(current) {
+			if _, seen := depths[dep]; !seen {
+				depths[dep] = depths[current] + 1
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return depths
+}
+
+// GraphExportOptions holds options shared by the diagram exporters.
+type GraphExportOptions struct {
+	// LabelEdgesWithRequested labels an edge "req 1.0 → 2.0" when the
+	// dependent requested a different version than the one resolved, making
+	// MVS bumps visible. Edges whose versions match, or whose requested
+	// version is unknown (Node.RequestedVersions), are left unlabeled.
+	LabelEdgesWithRequested bool
+}
+
+// edgeLabel returns the label for the edge from -> to, or "" for none.
+func (g *Graph) edgeLabel(from, to ModuleKey, opts GraphExportOptions) string {
+	if !opts.LabelEdgesWithRequested {
+		return ""
+	}
+	node := g.Modules[to]
+	if node == nil {
+		return ""
+	}
+	requested := node.RequestedVersions[from]
+	if requested == "" || requested == to.Version {
+		return ""
+	}
+	return fmt.Sprintf("req %s → %s", requested, to.Version)
+}
+
+// DOTOptions controls the styling of ToDOTWithOptions output. The zero value
+// produces the same output as ToDOT.
+type DOTOptions struct {
+	GraphExportOptions
+
+	// StyleEdges renders edges to dev dependencies dashed and all other
+	// edges solid, so the two dependency classes are visibly separate.
+	StyleEdges bool
+
+	// ProductionColor and DevColor set the Graphviz color of nodes and of the
+	// edges leading to them, by dependency kind. Empty leaves the default.
+	ProductionColor string
+	DevColor        string
+
+	// RootColor fills the root node with the given Graphviz color.
+	RootColor string
+
+	// ClusterByDepth groups nodes into one cluster per depth (shortest
+	// distance from the root). Nodes unreachable from the root are left
+	// outside any cluster.
+	ClusterByDepth bool
+}
+
 // ToDOT outputs the graph in Graphviz DOT format.
 func (g *Graph) ToDOT() string {
+	return g.ToDOTWithOptions(DOTOptions{})
+}
+
+// ToDOTWithOptions outputs the graph in Graphviz DOT format, styled by opts.
+// Nodes and edges are written in name, then version order.
+func (g *Graph) ToDOTWithOptions(opts DOTOptions) string {
 	var buf bytes.Buffer
 
 	buf.WriteString("digraph dependencies {\n")
 	buf.WriteString("  rankdir=LR;\n")
 	buf.WriteString("  node [shape=box];\n\n")
 
+	keys := slices.SortedFunc(maps.Keys(g.Modules), compareModuleKeys)
+
 	// Add nodes (using explicit quotes for DOT format compatibility)
-	for key, node := range g.Modules {
-		label := fmt.Sprintf("%s\\n%s", key.Name, key.Version)
-		attrs := fmt.Sprintf(`label="%s"`, label) //nolint:gocritic // DOT format requires this quote style
-		if node.IsRoot {
-			attrs += ", style=bold"
+	if opts.ClusterByDepth {
+		depths := g.depthsFromRoot()
+		byDepth := make(map[int][]ModuleKey)
+		var unreachable []ModuleKey
+		for _, key := range keys {
+			if depth, ok := depths[key]; ok {
+				byDepth[depth] = append(byDepth[depth], key)
+			} else {
+				unreachable = append(unreachable, key)
+			}
 		}
-		if node.DevDependency {
-			attrs += ", style=dashed"
+		for _, depth := range slices.Sorted(maps.Keys(byDepth)) {
+			fmt.Fprintf(&buf, "  subgraph cluster_depth_%d {\n", depth)
+			fmt.Fprintf(&buf, "    label=\"depth %d\";\n", depth)
+			for _, key := range byDepth[depth] {
+				buf.WriteString("  ")
+				g.writeDOTNode(&buf, key, opts)
+			}
+			buf.WriteString("  }\n")
+		}
+		for _, key := range unreachable {
+			g.writeDOTNode(&buf, key, opts)
+		}
+	} else {
+		for _, key := range keys {
+			g.writeDOTNode(&buf, key, opts)
 		}
-		fmt.Fprintf(&buf, "  %q [%s];\n", key.String(), attrs)
 	}
 
 	buf.WriteString("\n")
 
 	// Add edges
-	for key, node := range g.Modules {
-		for _, dep := range node.Dependencies {
-			fmt.Fprintf(&buf, "  %q -> %q;\n", key.String(), dep.String())
+	for _, key := range keys {
+		for _, dep := range g.Modules[key].Dependencies {
+			var attrs []string
+			isDev := g.Modules[dep] != nil && g.Modules[dep].DevDependency
+			if opts.StyleEdges {
+				if isDev {
+					attrs = append(attrs, "style=dashed")
+				} else {
+					attrs = append(attrs, "style=solid")
+				}
+			}
+			if color := dotKindColor(opts, isDev); color != "" {
+				attrs = append(attrs, fmt.Sprintf("color=%q", color))
+			}
+			if label := g.edgeLabel(key, dep, opts.GraphExportOptions); label != "" {
+				attrs = append(attrs, fmt.Sprintf("label=%q", label))
+			}
+			if len(attrs) > 0 {
+				fmt.Fprintf(&buf, "  %q -> %q [%s];\n", key.String(), dep.String(), strings.Join(attrs, ", "))
+			} else {
+				fmt.Fprintf(&buf, "  %q -> %q;\n", key.String(), dep.String())
+			}
 		}
 	}
 
@@ -135,6 +469,99 @@ func (g *Graph) ToDOT() string {
 	return buf.String()
 }
 
+func (g *Graph) writeDOTNode(buf *bytes.Buffer, key ModuleKey, opts DOTOptions) {
+	node := g.Modules[key]
+	label := fmt.Sprintf("%s\\n%s", key.Name, key.Version)
+	attrs := fmt.Sprintf(`label="%s"`, label) //nolint:gocritic // DOT format requires this quote style
+	if node.IsRoot {
+		if opts.RootColor != "" {
+			attrs += fmt.Sprintf(`, style="bold,filled", fillcolor=%q`, opts.RootColor)
+		} else {
+			attrs += ", style=bold"
+		}
+	}
+	if node.DevDependency {
+		attrs += ", style=dashed"
+	}
+	if color := dotKindColor(opts, node.DevDependency); color != "" && !node.IsRoot {
+		attrs += fmt.Sprintf(", color=%q", color)
+	}
+	fmt.Fprintf(buf, "  %q [%s];\n", key.String(), attrs)
+}
+
+// dotKindColor returns the configured color for a dependency kind.
+func dotKindColor(opts DOTOptions, dev bool) string {
+	if dev {
+		return opts.DevColor
+	}
+	return opts.ProductionColor
+}
+
+// ToMermaid outputs the graph as a Mermaid flowchart, which renders natively
+// in GitHub and GitLab markdown.
+func (g *Graph) ToMermaid() string {
+	return g.ToMermaidWithOptions(GraphExportOptions{})
+}
+
+// ToMermaidWithOptions outputs the graph as a Mermaid flowchart. Nodes are
+// labeled name@version; edges to dev dependencies are dotted (-.->) and all
+// other edges solid (-->). Nodes and edges are written in name, then version
+// order.
+func (g *Graph) ToMermaidWithOptions(opts GraphExportOptions) string {
+	var buf bytes.Buffer
+	buf.WriteString("graph TD\n")
+
+	keys := slices.SortedFunc(maps.Keys(g.Modules), compareModuleKeys)
+	ids := mermaidIDs(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "    %s[\"%s\"]\n", ids[key], key.String())
+	}
+
+	for _, key := range keys {
+		for _, dep := range g.Modules[key].Dependencies {
+			depID, ok := ids[dep]
+			if !ok {
+				continue
+			}
+			arrow := "-->"
+			if g.Modules[dep].DevDependency {
+				arrow = "-.->"
+			}
+			if label := g.edgeLabel(key, dep, opts); label != "" {
+				arrow += fmt.Sprintf("|\"%s\"|", label)
+			}
+			fmt.Fprintf(&buf, "    %s %s %s\n", ids[key], arrow, depID)
+		}
+	}
+
+	return buf.String()
+}
+
+// mermaidIDs assigns each key a Mermaid node ID. Mermaid IDs cannot contain
+// characters such as '@', '.' or '/', so every character other than an ASCII
+// letter, digit or underscore becomes '_'. Keys that sanitize to the same ID
+// get a numeric suffix, in the order given.
+func mermaidIDs(keys []ModuleKey) map[ModuleKey]string {
+	ids := make(map[ModuleKey]string, len(keys))
+	used := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		base := strings.Map(func(r rune) rune {
+			if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+				return r
+			}
+			return '_'
+		}, key.String())
+		id := base
+		for n := 2; used[id]; n++ {
+			id = fmt.Sprintf("%s_%d", base, n)
+		}
+		used[id] = true
+		ids[key] = id
+	}
+	return ids
+}
+
 // ToText outputs a human-readable text representation of the graph.
 func (g *Graph) ToText() string {
 	var buf bytes.Buffer
@@ -231,9 +658,12 @@ func (g *Graph) ToExplainText(moduleName string) (string, error) {
 	buf.WriteString(strings.Repeat("=", separatorWidth) + "\n\n")
 
 	// Version selection info
+	buf.WriteString("Version Selection:\n")
+	fmt.Fprintf(&buf, "  Selected version: %s\n", explanation.SelectedVersion)
+	if explanation.Overridden {
+		buf.WriteString("  Forced by override\n")
+	}
 	if explanation.Selection != nil {
-		buf.WriteString("Version Selection:\n")
-		fmt.Fprintf(&buf, "  Selected version: %s\n", explanation.Selection.SelectedVersion)
 		fmt.Fprintf(&buf, "  Strategy: %s\n", explanation.Selection.Strategy)
 		fmt.Fprintf(&buf, "  Deciding factor: %s\n", explanation.Selection.DecidingFactor)
 
@@ -256,6 +686,16 @@ func (g *Graph) ToExplainText(moduleName string) (string, error) {
 			}
 		}
 	}
+	if (explanation.Selection == nil || len(explanation.Selection.Candidates) == 0) && len(explanation.Requests) > 0 {
+		buf.WriteString("\n  Requests:\n")
+		for _, r := range explanation.Requests {
+			status := "  "
+			if r.Won {
+				status = "✓ "
+			}
+			fmt.Fprintf(&buf, "    %s%s requested %s\n", status, r.Requester.String(), r.Version)
+		}
+	}
 
 	// Dependency chains
 	if len(explanation.DependencyChains) > 0 {
@@ -1,11 +1,21 @@
 package graph
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"maps"
 	"os"
 	"os/exec"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/albertocavalcante/go-bzlmod/selection"
 )
 
 // Helper to create a test graph:
@@ -221,6 +231,41 @@ func TestGraph_TransitiveDependents(t *testing.T) {
 	}
 }
 
+func TestGraph_ReverseDeps(t *testing.T) {
+	root := ModuleKey{Name: "root", Version: "1.0.0"}
+	rulesGo := ModuleKey{Name: "rules_go", Version: "0.50.1"}
+	gazelle := ModuleKey{Name: "gazelle", Version: "0.38.0"}
+	protobuf := ModuleKey{Name: "protobuf", Version: "29.0"}
+	grpc := ModuleKey{Name: "grpc", Version: "1.66.0"}
+
+	g := Build(root, []SimpleModule{
+		{Name: "root", Version: "1.0.0", Dependencies: []ModuleKey{rulesGo, grpc, gazelle}},
+		{Name: "gazelle", Version: "0.38.0", Dependencies: []ModuleKey{rulesGo}},
+		{Name: "grpc", Version: "1.66.0", Dependencies: []ModuleKey{rulesGo, protobuf}},
+		{Name: "protobuf", Version: "29.0"},
+		{Name: "rules_go", Version: "0.50.1"},
+	})
+
+	got := g.ReverseDeps(rulesGo)
+	want := []ModuleKey{gazelle, grpc, root}
+	if !slices.Equal(got, want) {
+		t.Errorf("ReverseDeps(rules_go) = %v, want %v", got, want)
+	}
+
+	got = g.TransitiveReverseDeps(protobuf)
+	want = []ModuleKey{grpc, root}
+	if !slices.Equal(got, want) {
+		t.Errorf("TransitiveReverseDeps(protobuf) = %v, want %v", got, want)
+	}
+
+	if got := g.ReverseDeps(root); len(got) != 0 {
+		t.Errorf("ReverseDeps(root) = %v, want none", got)
+	}
+	if got := g.ReverseDeps(ModuleKey{Name: "missing", Version: "1.0"}); len(got) != 0 {
+		t.Errorf("ReverseDeps(missing) = %v, want none", got)
+	}
+}
+
 func TestGraph_Path(t *testing.T) {
 	g := createTestGraph()
 
@@ -291,6 +336,50 @@ func TestGraph_Stats(t *testing.T) {
 	if stats.MaxDepth != 2 {
 		t.Errorf("MaxDepth: expected 2, got %d", stats.MaxDepth)
 	}
+	if stats.AverageFanOut != 1 { // 4 edges over 4 modules
+		t.Errorf("AverageFanOut: expected 1, got %v", stats.AverageFanOut)
+	}
+	if stats.LeafModules != 1 {
+		t.Errorf("LeafModules: expected 1, got %d", stats.LeafModules)
+	}
+	if stats.SharedModules != 1 {
+		t.Errorf("SharedModules: expected 1, got %d", stats.SharedModules)
+	}
+	if want := (ModuleKey{Name: "c", Version: "2.0.0"}); stats.MostDependedUpon != want || stats.MostDependedUponCount != 2 {
+		t.Errorf("MostDependedUpon: expected %s (2), got %s (%d)", want, stats.MostDependedUpon, stats.MostDependedUponCount)
+	}
+}
+
+func TestGraph_ModuleCount(t *testing.T) {
+	// root depends on c@1.0.0 via a and c@2.0.0 via b, as allowed by a
+	// multiple_version_override on c.
+	root := ModuleKey{Name: "root", Version: "1.0.0"}
+	a := ModuleKey{Name: "a", Version: "1.0.0"}
+	b := ModuleKey{Name: "b", Version: "1.0.0"}
+	c1 := ModuleKey{Name: "c", Version: "1.0.0"}
+	c2 := ModuleKey{Name: "c", Version: "2.0.0"}
+
+	g := Build(root, []SimpleModule{
+		{Name: "root", Version: "1.0.0", Dependencies: []ModuleKey{a, b}},
+		{Name: "a", Version: "1.0.0", Dependencies: []ModuleKey{c1}},
+		{Name: "b", Version: "1.0.0", Dependencies: []ModuleKey{c2}},
+		{Name: "c", Version: "1.0.0"},
+		{Name: "c", Version: "2.0.0"},
+	})
+
+	if got := g.ModuleCount(); got != 4 {
+		t.Errorf("ModuleCount() = %d, want 4", got)
+	}
+	if got := g.ModuleVersionCount(); got != 5 {
+		t.Errorf("ModuleVersionCount() = %d, want 5", got)
+	}
+
+	// Without multiple versions, the counts agree.
+	g = createTestGraph()
+	if g.ModuleCount() != g.ModuleVersionCount() {
+		t.Errorf("ModuleCount() = %d, ModuleVersionCount() = %d, want equal",
+			g.ModuleCount(), g.ModuleVersionCount())
+	}
 }
 
 func TestGraph_Stats_CyclicGraphWithDeeperReentryDoesNotCrash(t *testing.T) {
@@ -395,6 +484,39 @@ func TestGraph_FindCycles(t *testing.T) {
 	}
 }
 
+func TestGraph_FindCycles_Distinct(t *testing.T) {
+	root := ModuleKey{Name: "root", Version: "1.0.0"}
+	gazelle := ModuleKey{Name: "gazelle", Version: "0.38.0"}
+	rulesGo := ModuleKey{Name: "rules_go", Version: "0.50.1"}
+	a := ModuleKey{Name: "a", Version: "1.0.0"}
+	b := ModuleKey{Name: "b", Version: "1.0.0"}
+	c := ModuleKey{Name: "c", Version: "1.0.0"}
+	self := ModuleKey{Name: "self", Version: "1.0.0"}
+
+	g := Build(root, []SimpleModule{
+		{Name: "root", Version: "1.0.0", Dependencies: []ModuleKey{rulesGo, gazelle, c, self}},
+		{Name: "rules_go", Version: "0.50.1", Dependencies: []ModuleKey{gazelle}},
+		{Name: "gazelle", Version: "0.38.0", Dependencies: []ModuleKey{rulesGo}},
+		// Entered at c, so the walk finds the cycle as c -> a -> b.
+		{Name: "c", Version: "1.0.0", Dependencies: []ModuleKey{a}},
+		{Name: "a", Version: "1.0.0", Dependencies: []ModuleKey{b}},
+		{Name: "b", Version: "1.0.0", Dependencies: []ModuleKey{c}},
+		{Name: "self", Version: "1.0.0", Dependencies: []ModuleKey{self}},
+	})
+
+	want := [][]ModuleKey{
+		{a, b, c},
+		{gazelle, rulesGo},
+		{self},
+	}
+	for range 3 {
+		got := g.FindCycles()
+		if !slices.EqualFunc(got, want, slices.Equal) {
+			t.Fatalf("FindCycles() = %v, want %v", got, want)
+		}
+	}
+}
+
 func TestGraph_Explain(t *testing.T) {
 	g := createTestGraph()
 
@@ -419,6 +541,81 @@ func TestGraph_Explain(t *testing.T) {
 	}
 }
 
+func TestGraph_Explain_Structured(t *testing.T) {
+	a := ModuleKey{Name: "a", Version: "1.0.0"}
+	b := ModuleKey{Name: "b", Version: "1.0.0"}
+
+	t.Run("mvs", func(t *testing.T) {
+		g := createTestGraph()
+		g.Modules[ModuleKey{Name: "c", Version: "2.0.0"}].RequestedVersions = map[ModuleKey]string{
+			b: "2.0.0",
+			a: "1.5.0",
+		}
+
+		explanation, err := g.Explain("c")
+		if err != nil {
+			t.Fatalf("Explain() error: %v", err)
+		}
+		if explanation.SelectedVersion != "2.0.0" {
+			t.Errorf("SelectedVersion = %s, want 2.0.0", explanation.SelectedVersion)
+		}
+		if explanation.Overridden {
+			t.Error("Overridden = true, want false")
+		}
+		want := []VersionRequest{
+			{Requester: a, Version: "1.5.0"},
+			{Requester: b, Version: "2.0.0", Won: true},
+		}
+		if len(explanation.Requests) != len(want) {
+			t.Fatalf("Requests = %+v, want %+v", explanation.Requests, want)
+		}
+		for i := range want {
+			if explanation.Requests[i] != want[i] {
+				t.Errorf("Requests[%d] = %+v, want %+v", i, explanation.Requests[i], want[i])
+			}
+		}
+
+		// The structure is meant for tooling, so it must encode as JSON.
+		if _, err := json.Marshal(explanation); err != nil {
+			t.Errorf("json.Marshal(explanation) error: %v", err)
+		}
+
+		text, err := g.ToExplainText("c")
+		if err != nil {
+			t.Fatalf("ToExplainText() error: %v", err)
+		}
+		if !strings.Contains(text, "✓ b@1.0.0 requested 2.0.0") {
+			t.Errorf("ToExplainText() missing winning request:\n%s", text)
+		}
+	})
+
+	t.Run("override", func(t *testing.T) {
+		g := createTestGraph()
+		node := g.Modules[ModuleKey{Name: "c", Version: "2.0.0"}]
+		node.Selection = &SelectionInfo{
+			Strategy:        StrategyOverride,
+			SelectedVersion: "2.0.0",
+			DecidingFactor:  "single_version_override",
+			Candidates: []VersionCandidate{
+				{Version: "1.0.0", RequestedBy: []ModuleKey{a, b}},
+			},
+		}
+
+		explanation, err := g.Explain("c")
+		if err != nil {
+			t.Fatalf("Explain() error: %v", err)
+		}
+		if !explanation.Overridden {
+			t.Error("Overridden = false, want true")
+		}
+		for _, r := range explanation.Requests {
+			if r.Won {
+				t.Errorf("request %+v won, but the override selected a version nobody requested", r)
+			}
+		}
+	})
+}
+
 func TestGraph_WhyIncluded(t *testing.T) {
 	g := createTestGraph()
 
@@ -462,6 +659,133 @@ func TestGraph_ToJSON(t *testing.T) {
 	}
 }
 
+func TestGraph_ToJSON_ExpandsInBFSOrder(t *testing.T) {
+	root := ModuleKey{Name: "root", Version: "1.0.0"}
+	a := ModuleKey{Name: "a", Version: "1.0.0"}
+	c := ModuleKey{Name: "c", Version: "1.0.0"}
+	d := ModuleKey{Name: "d", Version: "1.0.0"}
+
+	// c is reachable as root -> a -> c and root -> c. A depth-first walk
+	// would expand it under a; Bazel expands it at its shallowest occurrence.
+	g := Build(root, []SimpleModule{
+		{Name: "root", Version: "1.0.0", Dependencies: []ModuleKey{a, c}},
+		{Name: "a", Version: "1.0.0", Dependencies: []ModuleKey{c}},
+		{Name: "c", Version: "1.0.0", Dependencies: []ModuleKey{d}},
+		{Name: "d", Version: "1.0.0"},
+	})
+
+	jsonBytes, err := g.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error: %v", err)
+	}
+	var result BazelModGraph
+	if err := json.Unmarshal(jsonBytes, &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	want := []BazelDependency{
+		{Key: "a@1.0.0", Dependencies: []BazelDependency{{Key: "c@1.0.0", Unexpanded: true}}},
+		{Key: "c@1.0.0", Dependencies: []BazelDependency{{Key: "d@1.0.0"}}},
+	}
+	if !reflect.DeepEqual(result.Dependencies, want) {
+		t.Errorf("Dependencies = %+v, want %+v", result.Dependencies, want)
+	}
+}
+
+func TestGraph_ToFlatJSON(t *testing.T) {
+	g := createTestGraph()
+
+	jsonBytes, err := g.ToFlatJSON()
+	if err != nil {
+		t.Fatalf("ToFlatJSON() error: %v", err)
+	}
+	var nodes []FlatModGraphNode
+	if err := json.Unmarshal(jsonBytes, &nodes); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	want := []FlatModGraphNode{
+		{Key: "root@1.0.0", Dependencies: []string{"a@1.0.0", "b@1.0.0"}, Root: true},
+		{Key: "a@1.0.0", Dependencies: []string{"c@2.0.0"}},
+		{Key: "b@1.0.0", Dependencies: []string{"c@2.0.0"}},
+		{Key: "c@2.0.0"},
+	}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Errorf("ToFlatJSON() = %+v, want %+v", nodes, want)
+	}
+}
+
+func TestGraph_ToJSONExtended(t *testing.T) {
+	g := createTestGraph()
+	c := ModuleKey{Name: "c", Version: "2.0.0"}
+	g.Modules[c].CompatibilityLevel = 2
+	g.Modules[c].DevDependency = true
+
+	extendedBytes, err := g.ToJSONExtended()
+	if err != nil {
+		t.Fatalf("ToJSONExtended() error: %v", err)
+	}
+
+	var extended ExtendedModGraph
+	if err := json.Unmarshal(extendedBytes, &extended); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if extended.Depth != 0 || extended.RequesterCount != 0 {
+		t.Errorf("root metadata = %+v, want depth 0 and no requesters", extended.NodeMetadata)
+	}
+	if len(extended.Dependencies) != 2 {
+		t.Fatalf("root has %d dependencies, want 2", len(extended.Dependencies))
+	}
+	a := extended.Dependencies[0]
+	if a.Key != "a@1.0.0" || a.Depth != 1 || a.RequesterCount != 1 {
+		t.Errorf("a = %+v, want depth 1 and 1 requester", a)
+	}
+	if len(a.Dependencies) != 1 {
+		t.Fatalf("a has %d dependencies, want 1", len(a.Dependencies))
+	}
+	if !slices.Equal(a.RequiredBy, []string{"root@1.0.0"}) {
+		t.Errorf("a requiredBy = %v, want [root@1.0.0]", a.RequiredBy)
+	}
+	want := NodeMetadata{Depth: 2, DevDependency: true, CompatibilityLevel: 2, RequesterCount: 2,
+		RequiredBy: []string{"a@1.0.0", "b@1.0.0"}}
+	if got := a.Dependencies[0].NodeMetadata; !reflect.DeepEqual(got, want) {
+		t.Errorf("c metadata = %+v, want %+v", got, want)
+	}
+
+	// Stripping the extension fields must give back the ToJSON document.
+	var stripped, standard any
+	if err := json.Unmarshal(extendedBytes, &stripped); err != nil {
+		t.Fatal(err)
+	}
+	stripNodeMetadata(stripped)
+	standardBytes, err := g.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON() error: %v", err)
+	}
+	if err := json.Unmarshal(standardBytes, &standard); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(stripped, standard) {
+		t.Errorf("ToJSONExtended() without extension fields = %v, want ToJSON() = %v", stripped, standard)
+	}
+}
+
+func stripNodeMetadata(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		for _, field := range []string{"depth", "devDependency", "compatibilityLevel", "requesterCount", "requiredBy"} {
+			delete(v, field)
+		}
+		for _, child := range v {
+			stripNodeMetadata(child)
+		}
+	case []any:
+		for _, child := range v {
+			stripNodeMetadata(child)
+		}
+	}
+}
+
 func TestGraph_ToDOT(t *testing.T) {
 	g := createTestGraph()
 
@@ -482,6 +806,128 @@ func TestGraph_ToDOT(t *testing.T) {
 	}
 }
 
+func TestGraph_ToDOTWithOptions(t *testing.T) {
+	root := ModuleKey{Name: "root", Version: "1.0.0"}
+	prod := ModuleKey{Name: "prod", Version: "1.0.0"}
+	dev := ModuleKey{Name: "dev", Version: "1.0.0"}
+	leaf := ModuleKey{Name: "leaf", Version: "1.0.0"}
+
+	g := Build(root, []SimpleModule{
+		{Name: "root", Version: "1.0.0", Dependencies: []ModuleKey{prod, dev}},
+		{Name: "prod", Version: "1.0.0", Dependencies: []ModuleKey{leaf}},
+		{Name: "dev", Version: "1.0.0", DevDependency: true},
+		{Name: "leaf", Version: "1.0.0"},
+	})
+
+	if got, want := g.ToDOTWithOptions(DOTOptions{}), g.ToDOT(); got != want {
+		t.Errorf("zero DOTOptions output differs from ToDOT():\n%s\nwant:\n%s", got, want)
+	}
+
+	dot := g.ToDOTWithOptions(DOTOptions{
+		StyleEdges:      true,
+		ProductionColor: "black",
+		DevColor:        "gray",
+		RootColor:       "lightblue",
+		ClusterByDepth:  true,
+	})
+	for _, want := range []string{
+		`"root@1.0.0" -> "dev@1.0.0" [style=dashed, color="gray"];`,
+		`"root@1.0.0" -> "prod@1.0.0" [style=solid, color="black"];`,
+		`"prod@1.0.0" -> "leaf@1.0.0" [style=solid, color="black"];`,
+		`style="bold,filled", fillcolor="lightblue"`,
+		`"dev@1.0.0" [label="dev\n1.0.0", style=dashed, color="gray"];`,
+		"subgraph cluster_depth_0 {",
+		"subgraph cluster_depth_2 {",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("ToDOTWithOptions() missing %s in:\n%s", want, dot)
+		}
+	}
+}
+
+func TestGraph_ToMermaid(t *testing.T) {
+	root := ModuleKey{Name: "root", Version: "1.0.0"}
+	rulesGo := ModuleKey{Name: "rules_go", Version: "0.50.1"}
+	dev := ModuleKey{Name: "dev", Version: "1.0.0"}
+
+	g := Build(root, []SimpleModule{
+		{Name: "root", Version: "1.0.0", Dependencies: []ModuleKey{rulesGo, dev}},
+		{Name: "rules_go", Version: "0.50.1"},
+		{Name: "dev", Version: "1.0.0", DevDependency: true},
+	})
+	g.Modules[rulesGo].RequestedVersions[root] = "0.48.0"
+
+	want := `graph TD
+    dev_1_0_0["dev@1.0.0"]
+    root_1_0_0["root@1.0.0"]
+    rules_go_0_50_1["rules_go@0.50.1"]
+    root_1_0_0 --> rules_go_0_50_1
+    root_1_0_0 -.-> dev_1_0_0
+`
+	if got := g.ToMermaid(); got != want {
+		t.Errorf("ToMermaid() =\n%s\nwant:\n%s", got, want)
+	}
+
+	labeled := g.ToMermaidWithOptions(GraphExportOptions{LabelEdgesWithRequested: true})
+	if wantEdge := `root_1_0_0 -->|"req 0.48.0 → 0.50.1"| rules_go_0_50_1`; !strings.Contains(labeled, wantEdge) {
+		t.Errorf("ToMermaidWithOptions() missing %s in:\n%s", wantEdge, labeled)
+	}
+}
+
+func TestMermaidIDs_Collision(t *testing.T) {
+	dotted := ModuleKey{Name: "a.b", Version: "1.0"}
+	underscored := ModuleKey{Name: "a_b", Version: "1.0"}
+
+	ids := mermaidIDs([]ModuleKey{dotted, underscored})
+	if ids[dotted] != "a_b_1_0" || ids[underscored] != "a_b_1_0_2" {
+		t.Errorf("mermaidIDs() = %v, want distinct sanitized IDs", ids)
+	}
+}
+
+func TestGraph_WriteNDJSON(t *testing.T) {
+	g := createTestGraph()
+	orphan := ModuleKey{Name: "orphan", Version: "1.0.0"}
+	g.Modules[orphan] = &Node{Key: orphan, DevDependency: true}
+
+	var buf bytes.Buffer
+	if err := g.WriteNDJSON(&buf); err != nil {
+		t.Fatalf("WriteNDJSON() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(g.Modules) {
+		t.Fatalf("got %d lines, want %d (one per module):\n%s", len(lines), len(g.Modules), buf.String())
+	}
+
+	var keys []string
+	for i, line := range lines {
+		var m NDJSONModule
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v\n%s", i, err, line)
+		}
+		keys = append(keys, m.Key)
+		switch m.Key {
+		case "c@2.0.0":
+			if m.Depth != 2 || m.Name != "c" || m.Version != "2.0.0" {
+				t.Errorf("c line = %+v, want depth 2", m)
+			}
+		case "orphan@1.0.0":
+			if m.Depth != -1 || !m.DevDependency {
+				t.Errorf("orphan line = %+v, want depth -1 and dev", m)
+			}
+		case "root@1.0.0":
+			if !slices.Equal(m.Dependencies, []string{"a@1.0.0", "b@1.0.0"}) {
+				t.Errorf("root dependencies = %v", m.Dependencies)
+			}
+		}
+	}
+
+	want := []string{"root@1.0.0", "a@1.0.0", "b@1.0.0", "c@2.0.0", "orphan@1.0.0"}
+	if !slices.Equal(keys, want) {
+		t.Errorf("order = %v, want %v", keys, want)
+	}
+}
+
 func TestGraph_ToText(t *testing.T) {
 	g := createTestGraph()
 
@@ -664,6 +1110,53 @@ func TestGraph_EmptyGraph(t *testing.T) {
 	}
 }
 
+func TestFromSelectionResult(t *testing.T) {
+	root := ModuleKey{Name: "root", Version: "1.0.0"}
+	b10 := ModuleKey{Name: "b", Version: "1.0.0"}
+	a10 := ModuleKey{Name: "a", Version: "1.0.0"}
+	c10 := ModuleKey{Name: "c", Version: "1.0.0"}
+	c20 := ModuleKey{Name: "c", Version: "2.0.0"}
+
+	res, err := selection.Run(&selection.DepGraph{
+		RootKey: root,
+		Modules: map[ModuleKey]*selection.Module{
+			root: {Key: root, Deps: []selection.DepSpec{
+				{Name: "b", Version: "1.0.0", MaxCompatibilityLevel: -1},
+				{Name: "a", Version: "1.0.0", MaxCompatibilityLevel: -1},
+			}},
+			a10: {Key: a10, Deps: []selection.DepSpec{{Name: "c", Version: "2.0.0", MaxCompatibilityLevel: -1}}},
+			b10: {Key: b10, Deps: []selection.DepSpec{{Name: "c", Version: "1.0.0", MaxCompatibilityLevel: -1}}},
+			c10: {Key: c10, CompatLevel: 1},
+			c20: {Key: c20, CompatLevel: 1},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("selection.Run() error: %v", err)
+	}
+
+	g := FromSelectionResult(res, root)
+
+	if len(g.Modules) != 4 || g.Contains(c10) {
+		t.Fatalf("Modules = %v, want root, a, b and c@2.0.0", slices.Collect(maps.Keys(g.Modules)))
+	}
+	if !g.Modules[root].IsRoot {
+		t.Error("root node is not marked IsRoot")
+	}
+	if got := g.Modules[c20].CompatibilityLevel; got != 1 {
+		t.Errorf("c CompatibilityLevel = %d, want 1", got)
+	}
+	// Declaration order is kept, and b is visited before a.
+	if got := g.DirectDeps(root); !slices.Equal(got, []ModuleKey{b10, a10}) {
+		t.Errorf("DirectDeps(root) = %v, want [b a]", got)
+	}
+	if got := g.DirectDependents(c20); !slices.Equal(got, []ModuleKey{b10, a10}) {
+		t.Errorf("DirectDependents(c) = %v, want BFS order [b a]", got)
+	}
+	if text := g.ToText(); strings.Index(text, "b@1.0.0") > strings.Index(text, "a@1.0.0") {
+		t.Errorf("ToText() does not list b first:\n%s", text)
+	}
+}
+
 func TestBuilder_SelectionInfo(t *testing.T) {
 	b := NewBuilder()
 
@@ -713,3 +1206,331 @@ func TestBuilder_OverrideSelection(t *testing.T) {
 		t.Errorf("expected 'single_version_override', got %s", info.DecidingFactor)
 	}
 }
+
+func TestGraph_RetentionReason(t *testing.T) {
+	root := ModuleKey{Name: "root", Version: "1.0.0"}
+	a := ModuleKey{Name: "a", Version: "1.0.0"}
+	b := ModuleKey{Name: "b", Version: "1.0.0"}
+	c := ModuleKey{Name: "c", Version: "2.0.0"}
+	d := ModuleKey{Name: "d", Version: "1.0.0"}
+	pinned := ModuleKey{Name: "pinned", Version: "3.0.0"}
+	orphan := ModuleKey{Name: "orphan", Version: "1.0.0"}
+
+	g := Build(root, []SimpleModule{
+		{Name: "root", Version: "1.0.0", Dependencies: []ModuleKey{a, b}},
+		{Name: "a", Version: "1.0.0", Dependencies: []ModuleKey{c}},
+		{Name: "b", Version: "1.0.0", Dependencies: []ModuleKey{c}},
+		{Name: "c", Version: "2.0.0", Dependencies: []ModuleKey{d}},
+		{Name: "d", Version: "1.0.0"},
+		{Name: "pinned", Version: "3.0.0"},
+		{Name: "orphan", Version: "1.0.0"},
+	})
+	g.Modules[pinned].Selection = &SelectionInfo{Strategy: StrategySingleVersion, SelectedVersion: "3.0.0"}
+
+	tests := []struct {
+		key     ModuleKey
+		want    string
+		wantErr bool
+	}{
+		{key: root, want: "root"},
+		{key: a, want: "direct dependency of root"},
+		{key: c, want: "transitive via root@1.0.0 -> a@1.0.0 -> c@2.0.0"},
+		{key: d, want: "transitive via root@1.0.0 -> a@1.0.0 -> c@2.0.0 -> d@1.0.0"},
+		{key: pinned, want: "kept by override"},
+		{key: orphan, wantErr: true},
+		{key: ModuleKey{Name: "missing", Version: "1.0.0"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key.String(), func(t *testing.T) {
+			got, err := g.RetentionReason(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RetentionReason() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RetentionReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraph_ConflictMatrix(t *testing.T) {
+	root := ModuleKey{Name: "root", Version: "1.0.0"}
+	rulesGo := ModuleKey{Name: "rules_go", Version: "0.50.0"}
+	gazelle := ModuleKey{Name: "gazelle", Version: "0.38.0"}
+	goTools := ModuleKey{Name: "go_tools", Version: "1.0.0"}
+	protobuf := ModuleKey{Name: "protobuf", Version: "29.0"}
+	skylib := ModuleKey{Name: "skylib", Version: "1.7.1"}
+
+	g := Build(root, []SimpleModule{
+		{Name: "root", Version: "1.0.0", Dependencies: []ModuleKey{rulesGo, gazelle}},
+		{Name: "rules_go", Version: "0.50.0", Dependencies: []ModuleKey{protobuf, skylib}},
+		{Name: "gazelle", Version: "0.38.0", Dependencies: []ModuleKey{rulesGo, goTools, skylib}},
+		{Name: "go_tools", Version: "1.0.0", Dependencies: []ModuleKey{protobuf}},
+		{Name: "protobuf", Version: "29.0"},
+		{Name: "skylib", Version: "1.7.1"},
+	})
+	g.Modules[protobuf].RequestedVersions = map[ModuleKey]string{
+		rulesGo: "29.0",
+		goTools: "3.19.6",
+	}
+	// Both subtrees agree on skylib.
+	g.Modules[skylib].RequestedVersions = map[ModuleKey]string{
+		rulesGo: "1.7.1",
+		gazelle: "1.7.1",
+	}
+	// Only gazelle's subtree requests rules_go; the root's own request does not count.
+	g.Modules[rulesGo].RequestedVersions = map[ModuleKey]string{
+		root:    "0.50.0",
+		gazelle: "0.40.0",
+	}
+
+	conflicts := g.ConflictMatrix()
+	if len(conflicts) != 1 {
+		t.Fatalf("ConflictMatrix() = %+v, want only protobuf", conflicts)
+	}
+
+	got := conflicts[0]
+	if got.Module != "protobuf" || got.SelectedVersion != "29.0" {
+		t.Errorf("conflict = %s@%s, want protobuf@29.0", got.Module, got.SelectedVersion)
+	}
+	want := []SubtreeRequest{
+		// gazelle depends on rules_go, so its subtree carries both requests.
+		{DirectDep: gazelle, Versions: []string{"3.19.6", "29.0"}},
+		{DirectDep: rulesGo, Versions: []string{"29.0"}},
+	}
+	if len(got.Subtrees) != len(want) {
+		t.Fatalf("Subtrees = %+v, want %+v", got.Subtrees, want)
+	}
+	for i := range want {
+		if got.Subtrees[i].DirectDep != want[i].DirectDep ||
+			strings.Join(got.Subtrees[i].Versions, ",") != strings.Join(want[i].Versions, ",") {
+			t.Errorf("Subtrees[%d] = %+v, want %+v", i, got.Subtrees[i], want[i])
+		}
+	}
+}
+
+func TestGraph_TopologicalOrder(t *testing.T) {
+	root := ModuleKey{Name: "root", Version: "1.0.0"}
+	a := ModuleKey{Name: "a", Version: "1.0.0"}
+	b := ModuleKey{Name: "b", Version: "1.0.0"}
+	c := ModuleKey{Name: "c", Version: "1.0.0"}
+
+	g := Build(root, []SimpleModule{
+		{Name: "root", Version: "1.0.0", Dependencies: []ModuleKey{b, a}},
+		{Name: "a", Version: "1.0.0", Dependencies: []ModuleKey{c}},
+		{Name: "b", Version: "1.0.0", Dependencies: []ModuleKey{c}},
+		{Name: "c", Version: "1.0.0"},
+	})
+
+	order, err := g.TopologicalOrder()
+	if err != nil {
+		t.Fatalf("TopologicalOrder() error = %v", err)
+	}
+	want := []ModuleKey{c, a, b, root}
+	if len(order) != len(want) {
+		t.Fatalf("TopologicalOrder() = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("TopologicalOrder()[%d] = %s, want %s", i, order[i], want[i])
+		}
+	}
+
+	g = Build(root, []SimpleModule{
+		{Name: "root", Version: "1.0.0", Dependencies: []ModuleKey{a}},
+		{Name: "a", Version: "1.0.0", Dependencies: []ModuleKey{b}},
+		{Name: "b", Version: "1.0.0", Dependencies: []ModuleKey{a}},
+	})
+	if _, err := g.TopologicalOrder(); err == nil {
+		t.Error("TopologicalOrder() on cyclic graph: expected error")
+	} else if err.Error() != "dependency cycle: a@1.0.0 -> b@1.0.0 -> a@1.0.0" {
+		t.Errorf("error = %q", err)
+	}
+}
+
+func TestGraph_Subgraph(t *testing.T) {
+	g := createTestGraph()
+	a := ModuleKey{Name: "a", Version: "1.0.0"}
+	b := ModuleKey{Name: "b", Version: "1.0.0"}
+	c := ModuleKey{Name: "c", Version: "2.0.0"}
+	g.Modules[c].RequestedVersions[a] = "1.5.0"
+	g.Modules[c].RequestedVersions[b] = "2.0.0"
+
+	sub, err := g.Subgraph(a)
+	if err != nil {
+		t.Fatalf("Subgraph() error = %v", err)
+	}
+
+	if sub.Root != a || !sub.Modules[a].IsRoot {
+		t.Errorf("Root = %v, want %v marked as root", sub.Root, a)
+	}
+	if got := slices.SortedFunc(maps.Keys(sub.Modules), compareModuleKeys); !slices.Equal(got, []ModuleKey{a, c}) {
+		t.Errorf("Modules = %v, want [a@1.0.0 c@2.0.0]", got)
+	}
+	if got := sub.DirectDependents(c); !slices.Equal(got, []ModuleKey{a}) {
+		t.Errorf("DirectDependents(c) = %v, want [a@1.0.0]", got)
+	}
+	if got := sub.Modules[c].RequestedVersions; len(got) != 1 || got[a] != "1.5.0" {
+		t.Errorf("RequestedVersions(c) = %v, want only a's request", got)
+	}
+	if path := sub.Path(a, c); len(path) != 2 {
+		t.Errorf("Path(a, c) = %v, want [a c]", path)
+	}
+	if dot := sub.ToDOT(); strings.Contains(dot, "root@1.0.0") || strings.Contains(dot, "b@1.0.0") {
+		t.Errorf("ToDOT() includes modules outside the subgraph:\n%s", dot)
+	}
+
+	// The original graph is unchanged.
+	if len(g.Modules) != 4 || len(g.DirectDependents(c)) != 2 || g.Modules[a].IsRoot {
+		t.Error("Subgraph() modified the original graph")
+	}
+
+	if _, err := g.Subgraph(ModuleKey{Name: "missing", Version: "1.0.0"}); err == nil {
+		t.Error("Subgraph() of a missing module: expected error")
+	}
+}
+
+func TestGraph_ToCycloneDX(t *testing.T) {
+	g := createTestGraph()
+	c := ModuleKey{Name: "c", Version: "2.0.0"}
+	digest := sha256.Sum256([]byte("c"))
+	meta := SBOMMetadata{
+		ToolName:    "go-bzlmod",
+		ToolVersion: "1.0.0",
+		Timestamp:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Sources: map[ModuleKey]SBOMSource{
+			c: {URL: "https://example.com/c.tar.gz", Integrity: "sha256-" + base64.StdEncoding.EncodeToString(digest[:])},
+		},
+	}
+
+	data, err := g.ToCycloneDX(meta)
+	if err != nil {
+		t.Fatalf("ToCycloneDX() error: %v", err)
+	}
+	var bom struct {
+		BOMFormat   string `json:"bomFormat"`
+		SpecVersion string `json:"specVersion"`
+		Metadata    struct {
+			Timestamp string             `json:"timestamp"`
+			Component cycloneDXComponent `json:"component"`
+		} `json:"metadata"`
+		Components   []cycloneDXComponent  `json:"components"`
+		Dependencies []cycloneDXDependency `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &bom); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if bom.BOMFormat != "CycloneDX" || bom.SpecVersion != "1.5" || bom.Metadata.Timestamp != "2025-01-02T03:04:05Z" {
+		t.Errorf("header = %q %q %q", bom.BOMFormat, bom.SpecVersion, bom.Metadata.Timestamp)
+	}
+	if root := bom.Metadata.Component; root.Type != "application" || root.BOMRef != "root@1.0.0" {
+		t.Errorf("metadata.component = %+v, want the root application", root)
+	}
+	var refs []string
+	for _, component := range bom.Components {
+		refs = append(refs, component.BOMRef)
+	}
+	if want := []string{"a@1.0.0", "b@1.0.0", "c@2.0.0"}; !slices.Equal(refs, want) {
+		t.Errorf("components = %v, want %v", refs, want)
+	}
+	cComponent := bom.Components[2]
+	if cComponent.PURL != "pkg:bazel/c@2.0.0" {
+		t.Errorf("c purl = %q", cComponent.PURL)
+	}
+	wantRef := []cycloneDXExternalReference{{
+		Type:   "distribution",
+		URL:    "https://example.com/c.tar.gz",
+		Hashes: []cycloneDXHash{{Alg: "SHA-256", Content: hex.EncodeToString(digest[:])}},
+	}}
+	if !reflect.DeepEqual(cComponent.ExternalReferences, wantRef) {
+		t.Errorf("c externalReferences = %+v, want %+v", cComponent.ExternalReferences, wantRef)
+	}
+
+	wantDeps := []cycloneDXDependency{
+		{Ref: "a@1.0.0", DependsOn: []string{"c@2.0.0"}},
+		{Ref: "b@1.0.0", DependsOn: []string{"c@2.0.0"}},
+		{Ref: "c@2.0.0", DependsOn: []string{}},
+		{Ref: "root@1.0.0", DependsOn: []string{"a@1.0.0", "b@1.0.0"}},
+	}
+	if !reflect.DeepEqual(bom.Dependencies, wantDeps) {
+		t.Errorf("dependencies = %+v, want %+v", bom.Dependencies, wantDeps)
+	}
+}
+
+func TestGraph_ToSPDX(t *testing.T) {
+	g := Build(ModuleKey{Name: "root", Version: "1.0.0"}, []SimpleModule{
+		{Name: "root", Version: "1.0.0", Dependencies: []ModuleKey{
+			{Name: "rules_cc", Version: "0.1.1.bcr.1"},
+			{Name: "rules-cc", Version: "0.1.1.bcr.1"},
+		}},
+		{Name: "rules_cc", Version: "0.1.1.bcr.1"},
+		{Name: "rules-cc", Version: "0.1.1.bcr.1"},
+	})
+	digest := sha256.Sum256([]byte("rules_cc"))
+	meta := SBOMMetadata{
+		Namespace: "https://example.com/spdx/root-1.0.0",
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Sources: map[ModuleKey]SBOMSource{
+			{Name: "rules_cc", Version: "0.1.1.bcr.1"}: {
+				URL:       "https://example.com/rules_cc.tar.gz",
+				Integrity: "sha256-" + base64.StdEncoding.EncodeToString(digest[:]),
+			},
+		},
+	}
+
+	if _, err := g.ToSPDX(SBOMMetadata{}); err == nil {
+		t.Error("ToSPDX() without a namespace succeeded, want error")
+	}
+	data, err := g.ToSPDX(meta)
+	if err != nil {
+		t.Fatalf("ToSPDX() error: %v", err)
+	}
+	var doc spdxDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if doc.SPDXVersion != "SPDX-2.3" || doc.DataLicense != "CC0-1.0" || doc.SPDXID != "SPDXRef-DOCUMENT" ||
+		doc.Name != "root@1.0.0" || doc.DocumentNamespace != meta.Namespace {
+		t.Errorf("document header = %+v", doc)
+	}
+	if doc.CreationInfo.Created != "2025-01-02T03:04:05Z" || !slices.Equal(doc.CreationInfo.Creators, []string{"Tool: go-bzlmod"}) {
+		t.Errorf("creationInfo = %+v", doc.CreationInfo)
+	}
+
+	// Packages are sorted by name; "rules-cc" and "rules_cc" map to the same
+	// identifier, so the second gets a suffix.
+	want := []spdxPackage{
+		{
+			Name: "root", SPDXID: "SPDXRef-root-1.0.0", VersionInfo: "1.0.0", DownloadLocation: "NOASSERTION",
+			ExternalRefs:          []spdxExternalRef{{"PACKAGE-MANAGER", "purl", "pkg:bazel/root@1.0.0"}},
+			PrimaryPackagePurpose: "APPLICATION",
+		},
+		{
+			Name: "rules-cc", SPDXID: "SPDXRef-rules-cc-0.1.1.bcr.1", VersionInfo: "0.1.1.bcr.1", DownloadLocation: "NOASSERTION",
+			ExternalRefs:          []spdxExternalRef{{"PACKAGE-MANAGER", "purl", "pkg:bazel/rules-cc@0.1.1.bcr.1"}},
+			PrimaryPackagePurpose: "LIBRARY",
+		},
+		{
+			Name: "rules_cc", SPDXID: "SPDXRef-rules-cc-0.1.1.bcr.1-2", VersionInfo: "0.1.1.bcr.1",
+			DownloadLocation:      "https://example.com/rules_cc.tar.gz",
+			Checksums:             []spdxChecksum{{"SHA256", hex.EncodeToString(digest[:])}},
+			ExternalRefs:          []spdxExternalRef{{"PACKAGE-MANAGER", "purl", "pkg:bazel/rules_cc@0.1.1.bcr.1"}},
+			PrimaryPackagePurpose: "LIBRARY",
+		},
+	}
+	if !reflect.DeepEqual(doc.Packages, want) {
+		t.Errorf("packages = %+v\nwant %+v", doc.Packages, want)
+	}
+
+	wantRels := []spdxRelationship{
+		{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-root-1.0.0"},
+		{"SPDXRef-root-1.0.0", "DEPENDS_ON", "SPDXRef-rules-cc-0.1.1.bcr.1"},
+		{"SPDXRef-root-1.0.0", "DEPENDS_ON", "SPDXRef-rules-cc-0.1.1.bcr.1-2"},
+	}
+	if !reflect.DeepEqual(doc.Relationships, wantRels) {
+		t.Errorf("relationships = %+v, want %+v", doc.Relationships, wantRels)
+	}
+}
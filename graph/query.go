@@ -1,8 +1,13 @@
 package graph
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+
+	"github.com/albertocavalcante/go-bzlmod/selection/version"
 )
 
 // Get returns the node for a module key, or nil if not found.
@@ -108,6 +113,24 @@ func (g *Graph) TransitiveDependents(key ModuleKey) []ModuleKey {
 	return result
 }
 
+// ReverseDeps returns the modules that directly depend on key, including the
+// root when it declares the dependency. Unlike DirectDependents, the result
+// is a new slice sorted by name, then version.
+func (g *Graph) ReverseDeps(key ModuleKey) []ModuleKey {
+	deps := slices.Clone(g.DirectDependents(key))
+	slices.SortFunc(deps, compareModuleKeys)
+	return deps
+}
+
+// TransitiveReverseDeps returns every module that transitively depends on
+// key: the modules affected if key is bumped or removed. Unlike
+// TransitiveDependents, the result is sorted by name, then version.
+func (g *Graph) TransitiveReverseDeps(key ModuleKey) []ModuleKey {
+	deps := g.TransitiveDependents(key)
+	slices.SortFunc(deps, compareModuleKeys)
+	return deps
+}
+
 // Path finds the shortest dependency path from one module to another.
 // Returns nil if no path exists.
 func (g *Graph) Path(from, to ModuleKey) []ModuleKey {
@@ -190,8 +213,16 @@ func (g *Graph) Explain(moduleName string) (*Explanation, error) {
 	}
 
 	explanation := &Explanation{
-		Module:    node.Key,
-		Selection: node.Selection,
+		Module:          node.Key,
+		Selection:       node.Selection,
+		SelectedVersion: node.Key.Version,
+		Requests:        versionRequests(node),
+	}
+	if node.Selection != nil {
+		switch node.Selection.Strategy {
+		case StrategyOverride, StrategySingleVersion:
+			explanation.Overridden = true
+		}
 	}
 
 	// Find all paths from root to this module
@@ -217,6 +248,35 @@ func (g *Graph) Explain(moduleName string) (*Explanation, error) {
 	return explanation, nil
 }
 
+// versionRequests lists the requests for node. Pre-selection candidates are
+// preferred since they include requesters that selection later dropped; the
+// graph's edges are used when no candidates were recorded.
+func versionRequests(node *Node) []VersionRequest {
+	var requests []VersionRequest
+	if node.Selection != nil && len(node.Selection.Candidates) > 0 {
+		for _, candidate := range node.Selection.Candidates {
+			for _, requester := range candidate.RequestedBy {
+				requests = append(requests, VersionRequest{Requester: requester, Version: candidate.Version})
+			}
+		}
+	} else {
+		for requester, v := range node.RequestedVersions {
+			requests = append(requests, VersionRequest{Requester: requester, Version: v})
+		}
+	}
+
+	for i := range requests {
+		requests[i].Won = requests[i].Version == node.Key.Version
+	}
+	slices.SortFunc(requests, func(a, b VersionRequest) int {
+		if c := compareModuleKeys(a.Requester, b.Requester); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return requests
+}
+
 func (g *Graph) buildRequestSummary(node *Node) string {
 	if node.Selection == nil || len(node.Selection.Candidates) == 0 {
 		return fmt.Sprintf("%s is at version %s", node.Key.Name, node.Key.Version)
@@ -259,6 +319,203 @@ func (g *Graph) WhyIncluded(moduleName string) ([]DependencyChain, error) {
 	return chains, nil
 }
 
+// RetentionReason explains why a module is present in the graph at all, as
+// opposed to Explain, which explains the version it is at. The result is one of:
+//
+//   - "root"
+//   - "direct dependency of root"
+//   - "transitive via <chain>", using the shortest chain from the root
+//   - "kept by override", for modules unreachable from the root that an
+//     override keeps in the graph
+//
+// Returns an error if key is not in the graph, or if it is neither reachable
+// from the root nor kept by an override.
+func (g *Graph) RetentionReason(key ModuleKey) (string, error) {
+	node := g.Modules[key]
+	if node == nil {
+		return "", fmt.Errorf("module %s not found in graph", key)
+	}
+	if node.IsRoot || key == g.Root {
+		return "root", nil
+	}
+
+	path := g.Path(g.Root, key)
+	switch {
+	case len(path) == 2:
+		return "direct dependency of root", nil
+	case len(path) > 2:
+		return "transitive via " + DependencyChain{Path: path}.String(), nil
+	}
+
+	if node.Selection != nil {
+		switch node.Selection.Strategy {
+		case StrategyOverride, StrategySingleVersion:
+			return "kept by override", nil
+		}
+	}
+	return "", fmt.Errorf("module %s is not reachable from root %s", key, g.Root)
+}
+
+// ConflictMatrix reports shared modules that different direct dependencies of
+// the root require at different versions, for example "rules_go and gazelle
+// disagree on protobuf". A module is reported when requests for it come from
+// within at least two direct-dependency subtrees and those requests name more
+// than one distinct version.
+//
+// Requests are read from Node.RequestedVersions, which resolution fills in;
+// graphs built without pre-selection request data report no conflicts.
+// Results are sorted by module name.
+func (g *Graph) ConflictMatrix() []SharedDepConflict {
+	root := g.Modules[g.Root]
+	if root == nil {
+		return nil
+	}
+
+	directs := slices.Clone(root.Dependencies)
+	slices.SortFunc(directs, compareModuleKeys)
+	subtrees := make([]map[ModuleKey]bool, len(directs))
+	for i, direct := range directs {
+		subtrees[i] = map[ModuleKey]bool{direct: true}
+		for _, key := range g.TransitiveDeps(direct) {
+			subtrees[i][key] = true
+		}
+	}
+
+	var conflicts []SharedDepConflict
+	for key, node := range g.Modules {
+		if node.IsRoot || key == g.Root || len(node.RequestedVersions) == 0 {
+			continue
+		}
+
+		var requests []SubtreeRequest
+		distinct := make(map[string]bool)
+		for i, direct := range directs {
+			if direct == key {
+				continue
+			}
+			versions := make(map[string]bool)
+			for requester, v := range node.RequestedVersions {
+				if v != "" && subtrees[i][requester] {
+					versions[v] = true
+					distinct[v] = true
+				}
+			}
+			if len(versions) > 0 {
+				requests = append(requests, SubtreeRequest{
+					DirectDep: direct,
+					Versions:  slices.SortedFunc(maps.Keys(versions), version.Compare),
+				})
+			}
+		}
+
+		if len(requests) >= 2 && len(distinct) >= 2 {
+			conflicts = append(conflicts, SharedDepConflict{
+				Module:          key.Name,
+				SelectedVersion: key.Version,
+				Subtrees:        requests,
+			})
+		}
+	}
+
+	slices.SortFunc(conflicts, func(a, b SharedDepConflict) int {
+		if c := cmp.Compare(a.Module, b.Module); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.SelectedVersion, b.SelectedVersion)
+	})
+	return conflicts
+}
+
+func compareModuleKeys(a, b ModuleKey) int {
+	if c := cmp.Compare(a.Name, b.Name); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Version, b.Version)
+}
+
+// TopologicalOrder returns every module in the graph ordered so that each one
+// comes after all of its dependencies. Among modules whose dependencies are
+// all placed, the order is by name and then version, so the result is
+// deterministic.
+//
+// Returns a *CycleError naming one cycle if cycles prevent a total order.
+func (g *Graph) TopologicalOrder() ([]ModuleKey, error) {
+	pending := make(map[ModuleKey]int, len(g.Modules))
+	for key, node := range g.Modules {
+		for _, dep := range node.Dependencies {
+			if _, ok := g.Modules[dep]; ok && dep != key {
+				pending[key]++
+			} else if dep == key {
+				return nil, &CycleError{Cycle: []ModuleKey{key, key}}
+			}
+		}
+	}
+
+	var ready []ModuleKey
+	for key := range g.Modules {
+		if pending[key] == 0 {
+			ready = append(ready, key)
+		}
+	}
+
+	order := make([]ModuleKey, 0, len(g.Modules))
+	placed := make(map[ModuleKey]bool, len(g.Modules))
+	for len(ready) > 0 {
+		slices.SortFunc(ready, compareModuleKeys)
+		key := ready[0]
+		ready = ready[1:]
+		order = append(order, key)
+		placed[key] = true
+
+		for _, dependent := range g.Modules[key].Dependents {
+			if _, ok := g.Modules[dependent]; !ok || placed[dependent] {
+				continue
+			}
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(order) == len(g.Modules) {
+		return order, nil
+	}
+	return nil, &CycleError{Cycle: g.cycleAmong(placed)}
+}
+
+// cycleAmong returns a cycle among the modules not in placed. Every such module
+// has an unplaced dependency, so walking unplaced dependencies from the
+// smallest unplaced module must revisit a module.
+func (g *Graph) cycleAmong(placed map[ModuleKey]bool) []ModuleKey {
+	var remaining []ModuleKey
+	for key := range g.Modules {
+		if !placed[key] {
+			remaining = append(remaining, key)
+		}
+	}
+	slices.SortFunc(remaining, compareModuleKeys)
+
+	var path []ModuleKey
+	index := make(map[ModuleKey]int)
+	current := remaining[0]
+	for {
+		if i, seen := index[current]; seen {
+			return append(path[i:], current)
+		}
+		index[current] = len(path)
+		path = append(path, current)
+
+		var next []ModuleKey
+		for _, dep := range g.Modules[current].Dependencies {
+			if _, ok := g.Modules[dep]; ok && !placed[dep] {
+				next = append(next, dep)
+			}
+		}
+		current = slices.MinFunc(next, compareModuleKeys)
+	}
+}
+
 // Stats returns statistics about the graph.
 func (g *Graph) Stats() GraphStats {
 	stats := GraphStats{
@@ -276,12 +533,25 @@ func (g *Graph) Stats() GraphStats {
 		stats.TransitiveDependencies = 0
 	}
 
-	// Count dev dependencies
-	for _, node := range g.Modules {
+	// Count dev dependencies, edges and requesters
+	var edges int
+	for key, node := range g.Modules {
 		if node.DevDependency {
 			stats.DevDependencies++
 		}
+		edges += len(node.Dependencies)
+		if len(node.Dependents) > 1 {
+			stats.SharedModules++
+		}
+		if n := len(node.Dependents); n > 0 && (n > stats.MostDependedUponCount ||
+			n == stats.MostDependedUponCount && compareModuleKeys(key, stats.MostDependedUpon) < 0) {
+			stats.MostDependedUpon, stats.MostDependedUponCount = key, n
+		}
 	}
+	if stats.TotalModules > 0 {
+		stats.AverageFanOut = float64(edges) / float64(stats.TotalModules)
+	}
+	stats.LeafModules = len(g.Leaves())
 
 	// Calculate max depth
 	stats.MaxDepth = g.calculateMaxDepth()
@@ -289,6 +559,23 @@ func (g *Graph) Stats() GraphStats {
 	return stats
 }
 
+// ModuleCount returns the number of distinct module names in the graph,
+// including the root. A module present at several versions (for example
+// under a multiple_version_override) is counted once.
+func (g *Graph) ModuleCount() int {
+	names := make(map[string]bool, len(g.Modules))
+	for key := range g.Modules {
+		names[key.Name] = true
+	}
+	return len(names)
+}
+
+// ModuleVersionCount returns the number of distinct name@version nodes in
+// the graph, including the root.
+func (g *Graph) ModuleVersionCount() int {
+	return len(g.Modules)
+}
+
 func (g *Graph) calculateMaxDepth() int {
 	depths := make(map[ModuleKey]int)
 	onPath := make(map[ModuleKey]bool)
@@ -387,9 +674,18 @@ func (g *Graph) HasCycles() bool {
 	return false
 }
 
-// FindCycles returns all cycles in the graph.
+// FindCycles returns the cycles closed by back-edges of a depth-first walk
+// over the graph's edges. Each cycle is an ordered slice of keys in which
+// every module depends on the next and the last depends on the first; a
+// module that depends on itself is a one-element cycle.
+//
+// Cycles are rotated to start at their smallest key (by name, then version)
+// so rotations of the same cycle are reported once, and are returned in
+// sorted order. The walk starts from the root and then visits the remaining
+// modules by key, so the result is deterministic.
 func (g *Graph) FindCycles() [][]ModuleKey {
 	var cycles [][]ModuleKey
+	seen := make(map[string]bool)
 	visited := make(map[ModuleKey]bool)
 	recStack := make(map[ModuleKey]bool)
 	path := make([]ModuleKey, 0)
@@ -415,9 +711,11 @@ func (g *Graph) FindCycles() [][]ModuleKey {
 						}
 					}
 					if cycleStart >= 0 {
-						cycle := make([]ModuleKey, len(path)-cycleStart)
-						copy(cycle, path[cycleStart:])
-						cycles = append(cycles, cycle)
+						cycle := canonicalCycle(path[cycleStart:])
+						if id := fmt.Sprint(cycle); !seen[id] {
+							seen[id] = true
+							cycles = append(cycles, cycle)
+						}
 					}
 				}
 			}
@@ -427,11 +725,30 @@ func (g *Graph) FindCycles() [][]ModuleKey {
 		recStack[key] = false
 	}
 
-	for key := range g.Modules {
+	if g.Modules[g.Root] != nil {
+		findCycles(g.Root)
+	}
+	for _, key := range slices.SortedFunc(maps.Keys(g.Modules), compareModuleKeys) {
 		if !visited[key] {
 			findCycles(key)
 		}
 	}
 
+	slices.SortFunc(cycles, func(a, b []ModuleKey) int {
+		return slices.CompareFunc(a, b, compareModuleKeys)
+	})
 	return cycles
 }
+
+// canonicalCycle returns a copy of cycle rotated to start at its smallest key.
+func canonicalCycle(cycle []ModuleKey) []ModuleKey {
+	start := 0
+	for i, key := range cycle {
+		if compareModuleKeys(key, cycle[start]) < 0 {
+			start = i
+		}
+	}
+	rotated := make([]ModuleKey, 0, len(cycle))
+	rotated = append(rotated, cycle[start:]...)
+	return append(rotated, cycle[:start]...)
+}
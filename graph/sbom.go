@@ -0,0 +1,351 @@
+package graph
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SBOMMetadata describes the document an SBOM export produces and supplies
+// the module source details the graph does not hold.
+type SBOMMetadata struct {
+	// ToolName and ToolVersion identify the tool that produced the SBOM.
+	// The tool is omitted if ToolName is empty.
+	ToolName    string
+	ToolVersion string
+
+	// Timestamp is when the SBOM was created. ToCycloneDX omits it if zero,
+	// so the same graph always gives the same document; SPDX requires it, so
+	// ToSPDX uses the current time instead.
+	Timestamp time.Time
+
+	// SerialNumber uniquely identifies the BOM, as a "urn:uuid:..." URN.
+	// It is omitted if empty. Used by ToCycloneDX.
+	SerialNumber string
+
+	// DocumentName names the SPDX document. It defaults to the root's key.
+	// Used by ToSPDX.
+	DocumentName string
+
+	// Namespace is the unique URI of the SPDX document, such as
+	// "https://example.com/spdx/my_project-1.0.0-<uuid>". ToSPDX requires it.
+	Namespace string
+
+	// Sources holds the source archive of each module, such as the URL and
+	// integrity from its source.json. Modules without an entry have no
+	// download location in the SBOM.
+	Sources map[ModuleKey]SBOMSource
+}
+
+// SBOMSource is where a module's source comes from.
+type SBOMSource struct {
+	// URL is the download URL of the source archive or the git remote.
+	URL string
+
+	// Integrity is the SRI hash of the archive (e.g., "sha256-..."), if known.
+	Integrity string
+}
+
+// cycloneDXBOM is a CycloneDX 1.5 JSON document.
+// See: https://cyclonedx.org/docs/1.5/json/
+type cycloneDXBOM struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber,omitempty"`
+	Version      int                   `json:"version"`
+	Metadata     cycloneDXMetadata     `json:"metadata"`
+	Components   []cycloneDXComponent  `json:"components"`
+	Dependencies []cycloneDXDependency `json:"dependencies"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string              `json:"timestamp,omitempty"`
+	Tools     *cycloneDXTools     `json:"tools,omitempty"`
+	Component *cycloneDXComponent `json:"component,omitempty"`
+}
+
+type cycloneDXTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	Type               string                       `json:"type"`
+	BOMRef             string                       `json:"bom-ref,omitempty"`
+	Name               string                       `json:"name"`
+	Version            string                       `json:"version,omitempty"`
+	PURL               string                       `json:"purl,omitempty"`
+	ExternalReferences []cycloneDXExternalReference `json:"externalReferences,omitempty"`
+}
+
+type cycloneDXExternalReference struct {
+	Type   string          `json:"type"`
+	URL    string          `json:"url"`
+	Hashes []cycloneDXHash `json:"hashes,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// ToCycloneDX outputs the graph as a CycloneDX 1.5 JSON BOM. The root is the
+// top-level component in the metadata, every other module is a library
+// component, and each module lists its direct dependencies. Components are
+// identified by their "name@version" key and by a "pkg:bazel/name@version"
+// package URL. purl has no registered Bazel type, so scanners may not
+// recognize the latter.
+func (g *Graph) ToCycloneDX(meta SBOMMetadata) ([]byte, error) {
+	bom := cycloneDXBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: meta.SerialNumber,
+		Version:      1,
+		Components:   []cycloneDXComponent{},
+		Dependencies: []cycloneDXDependency{},
+	}
+	if !meta.Timestamp.IsZero() {
+		bom.Metadata.Timestamp = meta.Timestamp.UTC().Format(time.RFC3339)
+	}
+	if meta.ToolName != "" {
+		bom.Metadata.Tools = &cycloneDXTools{Components: []cycloneDXComponent{{
+			Type:    "application",
+			Name:    meta.ToolName,
+			Version: meta.ToolVersion,
+		}}}
+	}
+
+	for _, key := range slices.SortedFunc(maps.Keys(g.Modules), compareModuleKeys) {
+		component := cycloneDXComponent{
+			Type:    "library",
+			BOMRef:  key.String(),
+			Name:    key.Name,
+			Version: key.Version,
+			PURL:    bazelPURL(key),
+		}
+		if source, ok := meta.Sources[key]; ok && source.URL != "" {
+			ref := cycloneDXExternalReference{Type: "distribution", URL: source.URL}
+			if alg, digest, ok := sriDigest(source.Integrity); ok {
+				ref.Hashes = []cycloneDXHash{{Alg: alg, Content: digest}}
+			}
+			component.ExternalReferences = []cycloneDXExternalReference{ref}
+		}
+		if key == g.Root {
+			component.Type = "application"
+			bom.Metadata.Component = &component
+		} else {
+			bom.Components = append(bom.Components, component)
+		}
+
+		deps := slices.SortedFunc(slices.Values(g.Modules[key].Dependencies), compareModuleKeys)
+		dependsOn := make([]string, 0, len(deps))
+		for _, dep := range deps {
+			if g.Modules[dep] != nil {
+				dependsOn = append(dependsOn, dep.String())
+			}
+		}
+		bom.Dependencies = append(bom.Dependencies, cycloneDXDependency{Ref: key.String(), DependsOn: dependsOn})
+	}
+
+	return json.MarshalIndent(bom, "", "  ")
+}
+
+// bazelPURL returns the package URL of a module, "pkg:bazel/name@version",
+// or "pkg:bazel/name" if it has no version.
+func bazelPURL(key ModuleKey) string {
+	purl := "pkg:bazel/" + url.PathEscape(key.Name)
+	if key.Version != "" {
+		purl += "@" + url.PathEscape(key.Version)
+	}
+	return purl
+}
+
+// sriDigest converts an SRI hash such as "sha256-<base64>" to a CycloneDX
+// algorithm name and hex digest. ok is false for anything else.
+func sriDigest(integrity string) (alg, digest string, ok bool) {
+	name, encoded, found := strings.Cut(integrity, "-")
+	if !found {
+		return "", "", false
+	}
+	switch name {
+	case "sha256":
+		alg = "SHA-256"
+	case "sha384":
+		alg = "SHA-384"
+	case "sha512":
+		alg = "SHA-512"
+	default:
+		return "", "", false
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", "", false
+	}
+	return alg, hex.EncodeToString(raw), true
+}
+
+// spdxDocument is an SPDX 2.3 JSON document.
+// See: https://spdx.github.io/spdx-spec/v2.3/
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name                  string            `json:"name"`
+	SPDXID                string            `json:"SPDXID"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	Checksums             []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// ToSPDX outputs the graph as an SPDX 2.3 JSON document with one package per
+// module, a DESCRIBES relationship from the document to the root and a
+// DEPENDS_ON relationship for every graph edge. Versions are carried
+// verbatim in versionInfo. A module's downloadLocation is its source URL
+// from meta.Sources, or NOASSERTION if unknown. meta.Namespace is required.
+func (g *Graph) ToSPDX(meta SBOMMetadata) ([]byte, error) {
+	if meta.Namespace == "" {
+		return nil, errors.New("SPDX export requires a document namespace")
+	}
+	created := meta.Timestamp
+	if created.IsZero() {
+		created = time.Now()
+	}
+	creator := "Tool: go-bzlmod"
+	if meta.ToolName != "" {
+		creator = "Tool: " + meta.ToolName
+		if meta.ToolVersion != "" {
+			creator += "-" + meta.ToolVersion
+		}
+	}
+	name := meta.DocumentName
+	if name == "" {
+		name = g.Root.String()
+	}
+
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              name,
+		DocumentNamespace: meta.Namespace,
+		CreationInfo: spdxCreationInfo{
+			Created:  created.UTC().Format(time.RFC3339),
+			Creators: []string{creator},
+		},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+
+	keys := slices.SortedFunc(maps.Keys(g.Modules), compareModuleKeys)
+	ids := spdxIDs(keys)
+	for _, key := range keys {
+		pkg := spdxPackage{
+			Name:                  key.Name,
+			SPDXID:                ids[key],
+			VersionInfo:           key.Version,
+			DownloadLocation:      "NOASSERTION",
+			PrimaryPackagePurpose: "LIBRARY",
+			ExternalRefs: []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  bazelPURL(key),
+			}},
+		}
+		if source, ok := meta.Sources[key]; ok && source.URL != "" {
+			pkg.DownloadLocation = source.URL
+			if alg, digest, ok := sriDigest(source.Integrity); ok {
+				pkg.Checksums = []spdxChecksum{{Algorithm: strings.ReplaceAll(alg, "-", ""), ChecksumValue: digest}}
+			}
+		}
+		if key == g.Root {
+			pkg.PrimaryPackagePurpose = "APPLICATION"
+			doc.Relationships = append(doc.Relationships, spdxRelationship{
+				SPDXElementID:      doc.SPDXID,
+				RelationshipType:   "DESCRIBES",
+				RelatedSPDXElement: ids[key],
+			})
+		}
+		doc.Packages = append(doc.Packages, pkg)
+	}
+	for _, key := range keys {
+		for _, dep := range slices.SortedFunc(slices.Values(g.Modules[key].Dependencies), compareModuleKeys) {
+			if g.Modules[dep] == nil {
+				continue
+			}
+			doc.Relationships = append(doc.Relationships, spdxRelationship{
+				SPDXElementID:      ids[key],
+				RelationshipType:   "DEPENDS_ON",
+				RelatedSPDXElement: ids[dep],
+			})
+		}
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// spdxIDs assigns each module an SPDX identifier, "SPDXRef-name-version"
+// with characters SPDX does not allow replaced by "-". keys must be sorted;
+// a module whose identifier is taken gets a numeric suffix.
+func spdxIDs(keys []ModuleKey) map[ModuleKey]string {
+	ids := make(map[ModuleKey]string, len(keys))
+	used := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		base := "SPDXRef-" + strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+				return r
+			}
+			return '-'
+		}, key.Name+"-"+key.Version)
+		id := base
+		for n := 2; used[id]; n++ {
+			id = base + "-" + strconv.Itoa(n)
+		}
+		used[id] = true
+		ids[key] = id
+	}
+	return ids
+}
@@ -44,6 +44,9 @@ type Node struct {
 
 	// DevDependency is true if this module is only a dev dependency.
 	DevDependency bool
+
+	// CompatibilityLevel is the compatibility_level declared by the module.
+	CompatibilityLevel int
 }
 
 // SelectionInfo explains why a particular version was selected.
@@ -101,6 +104,18 @@ type Explanation struct {
 	// Selection explains how the version was selected.
 	Selection *SelectionInfo
 
+	// SelectedVersion is the version the module resolved to.
+	SelectedVersion string
+
+	// Requests lists every module that requested this one and the version it
+	// asked for, sorted by requester. Requests for the selected version are
+	// marked as having won.
+	Requests []VersionRequest
+
+	// Overridden is true if an override forced the selected version rather
+	// than MVS choosing it among the requests.
+	Overridden bool
+
 	// DependencyChains shows all paths from the root to this module.
 	DependencyChains []DependencyChain
 
@@ -108,6 +123,18 @@ type Explanation struct {
 	RequestSummary string
 }
 
+// VersionRequest is a single requester's request for a module version.
+type VersionRequest struct {
+	// Requester is the module that declared the dependency.
+	Requester ModuleKey
+
+	// Version is the version the requester asked for.
+	Version string
+
+	// Won is true if Version is the selected version.
+	Won bool
+}
+
 // DependencyChain represents a path of dependencies from root to a module.
 type DependencyChain struct {
 	// Path is the sequence of modules from root to target.
@@ -132,6 +159,17 @@ func (c DependencyChain) String() string {
 	return result
 }
 
+// CycleError is returned when a dependency cycle prevents an operation that
+// needs an acyclic graph, such as TopologicalOrder.
+type CycleError struct {
+	// Cycle lists the modules on the cycle, starting and ending with the same module.
+	Cycle []ModuleKey
+}
+
+func (e *CycleError) Error() string {
+	return "dependency cycle: " + DependencyChain{Path: e.Cycle}.String()
+}
+
 // GraphStats provides statistics about the graph.
 type GraphStats struct {
 	// TotalModules is the total number of modules in the graph.
@@ -148,4 +186,50 @@ type GraphStats struct {
 
 	// DevDependencies is the number of dev-only dependencies.
 	DevDependencies int
+
+	// AverageFanOut is the mean number of direct dependencies per module.
+	AverageFanOut float64
+
+	// LeafModules is the number of modules without dependencies.
+	LeafModules int
+
+	// SharedModules is the number of modules that more than one module
+	// depends on directly.
+	SharedModules int
+
+	// MostDependedUpon is the module with the most direct dependents, the
+	// least by name and version on a tie. It is the zero ModuleKey if no
+	// module has a dependent.
+	MostDependedUpon ModuleKey
+
+	// MostDependedUponCount is the number of direct dependents of
+	// MostDependedUpon.
+	MostDependedUponCount int
+}
+
+// SharedDepConflict describes a module that several direct dependencies of the
+// root require, transitively, at different versions. MVS resolves such
+// disagreements by bumping every requester to the highest version.
+type SharedDepConflict struct {
+	// Module is the name of the shared module.
+	Module string
+
+	// SelectedVersion is the version MVS selected for Module.
+	SelectedVersion string
+
+	// Subtrees lists, for each direct dependency whose subtree requires Module,
+	// the versions requested from within that subtree. Sorted by direct
+	// dependency; there are always at least two.
+	Subtrees []SubtreeRequest
+}
+
+// SubtreeRequest records the versions of a module requested from within the
+// subtree of one direct dependency of the root.
+type SubtreeRequest struct {
+	// DirectDep is the direct dependency of the root heading the subtree.
+	DirectDep ModuleKey
+
+	// Versions are the distinct versions requested before selection, sorted
+	// in ascending version order.
+	Versions []string
 }
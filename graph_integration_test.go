@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -301,3 +302,98 @@ bazel_dep(name = "a", version = "1.0.0")`
 		t.Errorf("expected max depth 2, got %d", stats.MaxDepth)
 	}
 }
+
+// TestResolutionList_Graph_EdgeLabels tests that MVS bumps show up as edge
+// labels when the graph is exported with requested versions.
+func TestResolutionList_Graph_EdgeLabels(t *testing.T) {
+	// root -> a@1.0.0 -> c@1.0.0 (bumped to 2.0.0)
+	//      -> b@1.0.0 -> c@2.0.0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "a", version = "1.0.0")
+bazel_dep(name = "c", version = "1.0.0")`)
+		case "/modules/b/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "b", version = "1.0.0")
+bazel_dep(name = "c", version = "2.0.0")`)
+		case "/modules/c/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "c", version = "1.0.0")`)
+		case "/modules/c/2.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "c", version = "2.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	moduleContent := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "a", version = "1.0.0")
+bazel_dep(name = "b", version = "1.0.0")`
+
+	result, err := Resolve(context.Background(), ContentSource(moduleContent), WithRegistries(server.URL))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	c := result.Graph.GetByName("c")
+	a := graph.ModuleKey{Name: "a", Version: "1.0.0"}
+	if got := c.RequestedVersions[a]; got != "1.0.0" {
+		t.Errorf("c.RequestedVersions[a] = %q, want 1.0.0", got)
+	}
+
+	dot := result.Graph.ToDOTWithOptions(graph.DOTOptions{
+		GraphExportOptions: graph.GraphExportOptions{LabelEdgesWithRequested: true},
+	})
+	if !strings.Contains(dot, `"a@1.0.0" -> "c@2.0.0" [label="req 1.0.0 → 2.0.0"];`) {
+		t.Errorf("bumped edge is not labeled:\n%s", dot)
+	}
+	if !strings.Contains(dot, `"b@1.0.0" -> "c@2.0.0";`) {
+		t.Errorf("edge without a bump should be unlabeled:\n%s", dot)
+	}
+}
+
+// TestResolutionList_Graph_ConflictMatrix tests that the graph of a
+// resolution records the requests ConflictMatrix reads.
+func TestResolutionList_Graph_ConflictMatrix(t *testing.T) {
+	//   root -> a@1.0.0 -> c@1.0.0
+	//        -> b@1.0.0 -> c@1.1.0 (selected)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "a", version = "1.0.0")
+bazel_dep(name = "c", version = "1.0.0")`)
+		case "/modules/b/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "b", version = "1.0.0")
+bazel_dep(name = "c", version = "1.1.0")`)
+		case "/modules/c/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "c", version = "1.0.0")`)
+		case "/modules/c/1.1.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "c", version = "1.1.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "a", version = "1.0.0")
+bazel_dep(name = "b", version = "1.0.0")`
+
+	result, err := Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	got := result.Graph.ConflictMatrix()
+	want := []graph.SharedDepConflict{{
+		Module:          "c",
+		SelectedVersion: "1.1.0",
+		Subtrees: []graph.SubtreeRequest{
+			{DirectDep: graph.ModuleKey{Name: "a", Version: "1.0.0"}, Versions: []string{"1.0.0"}},
+			{DirectDep: graph.ModuleKey{Name: "b", Version: "1.0.0"}, Versions: []string{"1.1.0"}},
+		},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ConflictMatrix() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,124 @@
+package gobzlmod
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// BumpImpact describes how pinning one module to a new version would change
+// the rest of a resolution.
+type BumpImpact struct {
+	// Module is the name of the bumped module.
+	Module string `json:"module"`
+
+	// FromVersion is the module's version in the original resolution.
+	FromVersion string `json:"from_version"`
+
+	// ToVersion is the version the module was bumped to.
+	ToVersion string `json:"to_version"`
+
+	// Affected lists other modules whose selected version changed as a
+	// consequence of the bump, sorted by name.
+	Affected []ModuleUpgrade `json:"affected,omitempty"`
+
+	// Added lists modules that enter the resolution because of the bump.
+	Added []ModuleChange `json:"added,omitempty"`
+
+	// Removed lists modules that leave the resolution because of the bump.
+	Removed []ModuleChange `json:"removed,omitempty"`
+
+	// Result is the hypothetical resolution with the bump applied.
+	Result *ResolutionList `json:"-"`
+}
+
+// HasImpact returns true if the bump changes any module other than the bumped one.
+func (b *BumpImpact) HasImpact() bool {
+	return len(b.Affected) > 0 || len(b.Added) > 0 || len(b.Removed) > 0
+}
+
+// ImpactOfBump re-resolves the root module as if it pinned name to newVersion
+// with a single_version_override, and reports which other modules would change
+// as a consequence. For example, bumping rules_go may force gazelle up.
+//
+// The original resolution is not modified. Options configure the hypothetical
+// resolution the same way they configure Resolve; pass the options used for
+// the original resolution to get a like-for-like comparison.
+//
+// Returns an error if the resolution carries no root module information
+// (for example, one that was deserialized from JSON), if name is not part of
+// the resolution, or if the hypothetical resolution fails.
+func (r *ResolutionList) ImpactOfBump(ctx context.Context, name, newVersion string, opts ...Option) (*BumpImpact, error) {
+	if r.root == nil {
+		return nil, fmt.Errorf("resolution has no root module information")
+	}
+	current := r.Module(name)
+	if current == nil {
+		return nil, fmt.Errorf("module %s is not part of the resolution", name)
+	}
+	if newVersion == "" {
+		return nil, fmt.Errorf("new version for %s is empty", name)
+	}
+
+	cfg, err := newResolverConfig(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+	resOpts := cfg.toResolutionOptions()
+
+	root := withPinnedVersion(r.root, name, newVersion)
+	resolver := newDependencyResolverWithOptions(registryFromOptions(resOpts), resOpts)
+	bumped, err := resolver.ResolveDependencies(ctx, root)
+	if err != nil {
+		return nil, fmt.Errorf("resolve with %s@%s: %w", name, newVersion, err)
+	}
+
+	diff := DiffResolutions(r, bumped)
+	impact := &BumpImpact{
+		Module:      name,
+		FromVersion: current.Version,
+		ToVersion:   newVersion,
+		Result:      bumped,
+	}
+	for _, change := range slices.Concat(diff.Upgraded, diff.Downgraded) {
+		if change.Name != name {
+			impact.Affected = append(impact.Affected, change)
+		}
+	}
+	sortModuleUpgrades(impact.Affected)
+	for _, change := range diff.Added {
+		if change.Name != name {
+			impact.Added = append(impact.Added, change)
+		}
+	}
+	for _, change := range diff.Removed {
+		if change.Name != name {
+			impact.Removed = append(impact.Removed, change)
+		}
+	}
+
+	return impact, nil
+}
+
+// withPinnedVersion returns a copy of root whose overrides pin name to
+// version, replacing any existing override for that module. The registry of
+// an existing single_version_override is preserved.
+func withPinnedVersion(root *ModuleInfo, name, version string) *ModuleInfo {
+	clone := *root
+	clone.Dependencies = slices.Clone(root.Dependencies)
+	clone.NodepDependencies = slices.Clone(root.NodepDependencies)
+	clone.Overrides = make([]Override, 0, len(root.Overrides)+1)
+
+	pin := Override{Type: overrideTypeSingleVersion, ModuleName: name, Version: version}
+	for _, o := range root.Overrides {
+		if o.ModuleName != name {
+			clone.Overrides = append(clone.Overrides, o)
+			continue
+		}
+		if o.Type == overrideTypeSingleVersion {
+			pin.Registry = o.Registry
+		}
+	}
+	clone.Overrides = append(clone.Overrides, pin)
+	return &clone
+}
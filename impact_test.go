@@ -0,0 +1,116 @@
+package gobzlmod
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolutionList_ImpactOfBump(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/rules_go/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "rules_go", version = "1.0.0")`)
+		case "/modules/rules_go/2.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "rules_go", version = "2.0.0")
+bazel_dep(name = "gazelle", version = "2.0.0")
+bazel_dep(name = "skylib", version = "1.0.0")`)
+		case "/modules/gazelle/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "gazelle", version = "1.0.0")`)
+		case "/modules/gazelle/2.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "gazelle", version = "2.0.0")`)
+		case "/modules/skylib/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "skylib", version = "1.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "rules_go", version = "1.0.0")
+bazel_dep(name = "gazelle", version = "1.0.0")`
+
+	ctx := context.Background()
+	list, err := Resolve(ctx, ContentSource(content), WithRegistries(server.URL))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	impact, err := list.ImpactOfBump(ctx, "rules_go", "2.0.0", WithRegistries(server.URL))
+	if err != nil {
+		t.Fatalf("ImpactOfBump() error = %v", err)
+	}
+
+	if impact.FromVersion != "1.0.0" || impact.ToVersion != "2.0.0" {
+		t.Errorf("impact versions = %s -> %s, want 1.0.0 -> 2.0.0", impact.FromVersion, impact.ToVersion)
+	}
+	if !impact.HasImpact() {
+		t.Fatal("HasImpact() = false, want true")
+	}
+	if len(impact.Affected) != 1 {
+		t.Fatalf("Affected = %v, want only gazelle", impact.Affected)
+	}
+	if got := impact.Affected[0]; got.Name != "gazelle" || got.OldVersion != "1.0.0" || got.NewVersion != "2.0.0" {
+		t.Errorf("Affected[0] = %+v, want gazelle 1.0.0 -> 2.0.0", got)
+	}
+	if len(impact.Added) != 1 || impact.Added[0].Name != "skylib" {
+		t.Errorf("Added = %v, want [skylib]", impact.Added)
+	}
+	if len(impact.Removed) != 0 {
+		t.Errorf("Removed = %v, want none", impact.Removed)
+	}
+
+	// The original resolution is untouched.
+	if got := list.Module("gazelle").Version; got != "1.0.0" {
+		t.Errorf("original gazelle = %s, want 1.0.0", got)
+	}
+	if got := impact.Result.Module("rules_go").Version; got != "2.0.0" {
+		t.Errorf("bumped rules_go = %s, want 2.0.0", got)
+	}
+}
+
+func TestResolutionList_ImpactOfBump_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	// A list without root information, e.g. decoded from JSON.
+	if _, err := (&ResolutionList{}).ImpactOfBump(ctx, "a", "1.0.0"); err == nil {
+		t.Error("ImpactOfBump() on list without root: expected error")
+	}
+
+	list := &ResolutionList{
+		Modules: []ModuleToResolve{{Name: "a", Version: "1.0.0"}},
+		root:    &ModuleInfo{Name: "root"},
+	}
+	if _, err := list.ImpactOfBump(ctx, "missing", "1.0.0"); err == nil {
+		t.Error("ImpactOfBump() for unknown module: expected error")
+	}
+	if _, err := list.ImpactOfBump(ctx, "a", ""); err == nil {
+		t.Error("ImpactOfBump() with empty version: expected error")
+	}
+}
+
+func TestWithPinnedVersion(t *testing.T) {
+	root := &ModuleInfo{
+		Name: "root",
+		Overrides: []Override{
+			{Type: overrideTypeSingleVersion, ModuleName: "a", Version: "1.0.0", Registry: "https://example.com"},
+			{Type: overrideTypeGit, ModuleName: "b"},
+		},
+	}
+
+	got := withPinnedVersion(root, "a", "2.0.0")
+
+	if len(root.Overrides) != 2 || root.Overrides[0].Version != "1.0.0" {
+		t.Fatalf("original overrides modified: %v", root.Overrides)
+	}
+	if len(got.Overrides) != 2 {
+		t.Fatalf("len(Overrides) = %d, want 2", len(got.Overrides))
+	}
+	pin := got.Overrides[1]
+	if pin.ModuleName != "a" || pin.Version != "2.0.0" || pin.Registry != "https://example.com" {
+		t.Errorf("pin = %+v, want a@2.0.0 keeping registry", pin)
+	}
+}
@@ -0,0 +1,98 @@
+package gobzlmod
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/albertocavalcante/go-bzlmod/label"
+)
+
+// includeSuffix is the file name suffix Bazel requires of included segments.
+const includeSuffix = ".MODULE.bazel"
+
+// followIncludes merges the segments info includes, and those they include
+// in turn, into info. Labels are resolved against rootDir, the directory of
+// the root MODULE.bazel.
+//
+// Reference: ModuleFileGlobals.include()
+// See: https://github.com/bazelbuild/bazel/blob/master/src/main/java/com/google/devtools/build/lib/bazel/bzlmod/ModuleFileGlobals.java
+func followIncludes(info *ModuleInfo, rootDir string) error {
+	return mergeIncludes(info, info.Includes, "", rootDir, nil, make(map[string]bool))
+}
+
+// mergeIncludes merges the segments named by includes, made from a file in
+// package pkg, into info. stack holds the labels of the segments being
+// merged, outermost first, and seen those merged so far.
+func mergeIncludes(info *ModuleInfo, includes []string, pkg, rootDir string, stack []string, seen map[string]bool) error {
+	for _, inc := range includes {
+		key, file, err := includeFile(inc, pkg)
+		if err != nil {
+			return err
+		}
+		if i := slices.Index(stack, key); i >= 0 {
+			return &IncludeCycleError{Chain: append(slices.Clone(stack[i:]), key)}
+		}
+		if seen[key] {
+			return fmt.Errorf("include: %s is included more than once", key)
+		}
+		seen[key] = true
+
+		filename := filepath.Join(rootDir, filepath.FromSlash(file))
+		content, err := os.ReadFile(filename) // #nosec G304 -- path confined to the root module by includeFile
+		if err != nil {
+			return fmt.Errorf("include %s: %w", key, err)
+		}
+		segment, err := parseModuleSegment(filename, content)
+		if err != nil {
+			return fmt.Errorf("include %s: %w", key, err)
+		}
+
+		info.Dependencies = append(info.Dependencies, segment.Dependencies...)
+		info.NodepDependencies = append(info.NodepDependencies, segment.NodepDependencies...)
+		info.Overrides = append(info.Overrides, segment.Overrides...)
+		info.ExtensionRepos = append(info.ExtensionRepos, segment.ExtensionRepos...)
+		info.DevExtensionRepos = append(info.DevExtensionRepos, segment.DevExtensionRepos...)
+		info.UnprovidedUseRepos = append(info.UnprovidedUseRepos, segment.UnprovidedUseRepos...)
+		for _, directive := range segment.Directives {
+			info.Directives = appendDirective(info.Directives, directive)
+		}
+
+		segmentPkg := path.Dir(file)
+		if segmentPkg == "." {
+			segmentPkg = ""
+		}
+		if err := mergeIncludes(info, segment.Includes, segmentPkg, rootDir, append(stack, key), seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// includeFile validates an include() label made from a file in package pkg
+// and returns its canonical form, "//pkg:target", and the slash-separated
+// path of the file it names relative to the root module. A relative label,
+// ":target", names a file in pkg.
+func includeFile(inc, pkg string) (key, file string, err error) {
+	lbl, err := label.ParseApparentLabel(inc)
+	if err != nil {
+		return "", "", fmt.Errorf("include: %w", err)
+	}
+	if strings.HasPrefix(inc, "@") {
+		return "", "", fmt.Errorf("include: label %q must refer to a file in the root module, without a repository", inc)
+	}
+	if !strings.HasSuffix(lbl.Target(), includeSuffix) {
+		return "", "", fmt.Errorf("include: label %q must name a file ending in %s", inc, includeSuffix)
+	}
+	if strings.HasPrefix(inc, "//") {
+		pkg = lbl.Package()
+	}
+	file = path.Join(pkg, lbl.Target())
+	if !filepath.IsLocal(filepath.FromSlash(file)) {
+		return "", "", fmt.Errorf("include: label %q refers outside the root module", inc)
+	}
+	return "//" + pkg + ":" + lbl.Target(), file, nil
+}
@@ -0,0 +1,140 @@
+package gobzlmod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeModuleTree writes files, keyed by slash-separated path, under a new
+// temporary directory and returns it.
+func writeModuleTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestResolve_FollowIncludes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var name string
+		if _, err := fmt.Sscanf(strings.ReplaceAll(r.URL.Path, "/", " "), " modules %s 1.0.0 MODULE.bazel", &name); err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, "module(name = %q, version = \"1.0.0\")\n", name)
+	}))
+	defer server.Close()
+
+	dir := writeModuleTree(t, map[string]string{
+		"MODULE.bazel": `module(name = "root", version = "1.0.0")
+bazel_dep(name = "direct", version = "1.0.0")
+include("//bazel:deps.MODULE.bazel")
+`,
+		"bazel/deps.MODULE.bazel": `bazel_dep(name = "from_segment", version = "1.0.0")
+include(":nested.MODULE.bazel")
+`,
+		"bazel/nested.MODULE.bazel": `bazel_dep(name = "from_nested", version = "1.0.0")
+`,
+	})
+	root := filepath.Join(dir, "MODULE.bazel")
+
+	list, err := Resolve(context.Background(), FileSource(root), WithRegistries(server.URL))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if len(list.Modules) != 1 {
+		t.Errorf("without WithFollowIncludes resolved %d modules, want only direct", len(list.Modules))
+	}
+
+	list, err = Resolve(context.Background(), FileSource(root), WithRegistries(server.URL), WithFollowIncludes())
+	if err != nil {
+		t.Fatalf("Resolve() with includes error = %v", err)
+	}
+	var names []string
+	for _, m := range list.Modules {
+		names = append(names, m.Name)
+	}
+	if want := []string{"direct", "from_nested", "from_segment"}; !slices.Equal(names, want) {
+		t.Errorf("resolved modules = %v, want %v", names, want)
+	}
+}
+
+func TestFollowIncludes_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{
+				"a.MODULE.bazel": `include("//:b.MODULE.bazel")`,
+				"b.MODULE.bazel": `include("//:a.MODULE.bazel")`,
+			},
+			wantErr: "include cycle: //:a.MODULE.bazel -> //:b.MODULE.bazel -> //:a.MODULE.bazel",
+		},
+		{
+			name: "included twice",
+			files: map[string]string{
+				"a.MODULE.bazel": `include("//:b.MODULE.bazel")`,
+				"b.MODULE.bazel": ``,
+				"c.MODULE.bazel": `include(":b.MODULE.bazel")`,
+			},
+			wantErr: "//:b.MODULE.bazel is included more than once",
+		},
+		{
+			name:    "wrong suffix",
+			files:   map[string]string{"a.MODULE.bazel": `include("//:deps.bzl")`},
+			wantErr: "must name a file ending in .MODULE.bazel",
+		},
+		{
+			name:    "other repository",
+			files:   map[string]string{"a.MODULE.bazel": `include("@other//:deps.MODULE.bazel")`},
+			wantErr: "without a repository",
+		},
+		{
+			name:    "module in segment",
+			files:   map[string]string{"a.MODULE.bazel": `module(name = "nope")`},
+			wantErr: "module() can only be called in the root MODULE.bazel",
+		},
+		{
+			name:    "missing file",
+			files:   map[string]string{"a.MODULE.bazel": `include("//:missing.MODULE.bazel")`},
+			wantErr: "include //:missing.MODULE.bazel",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeModuleTree(t, tt.files)
+			includes := []string{"//:a.MODULE.bazel"}
+			if tt.name == "included twice" {
+				includes = append(includes, "//:c.MODULE.bazel")
+			}
+			info := &ModuleInfo{Name: "root", Includes: includes}
+			err := followIncludes(info, dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("followIncludes() error = %v, want containing %q", err, tt.wantErr)
+			}
+			var cycleErr *IncludeCycleError
+			if isCycle := errors.As(err, &cycleErr); isCycle != (tt.name == "cycle") {
+				t.Errorf("error %v: IncludeCycleError = %v", err, isCycle)
+			}
+		})
+	}
+}
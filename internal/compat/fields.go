@@ -41,6 +41,7 @@ type FieldRequirement struct {
 // - max_compatibility_level: https://bazel.build/versions/7.0.0/external/module#bazel_dep (added in 7.0.0)
 // - include: https://bazel.build/versions/7.2.0/external/module#include (added in 7.2.0)
 // - use_repo_rule: https://bazel.build/versions/7.0.0/external/module#use_repo_rule (added in 7.0.0)
+// - nodep_dep: bazel_dep(repo_name = None), the nodep deps of Discovery.java (added in 7.6.0)
 // - override_repo/inject_repo: https://bazel.build/versions/8.0.0/external/module (added in 8.0.0)
 var fieldRegistry = []FieldRequirement{
 	// source.json fields
@@ -70,6 +71,12 @@ var fieldRegistry = []FieldRequirement{
 		Location:    LocationModule,
 		Description: "Direct repository rule invocation",
 	},
+	{
+		Name:        "nodep_dep",
+		MinVersion:  "7.6.0",
+		Location:    LocationModule,
+		Description: "bazel_dep with repo_name = None, used only for version selection",
+	},
 
 	// Extension fields (Bazel 8+)
 	{
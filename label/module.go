@@ -29,6 +29,7 @@ package label
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -105,7 +106,8 @@ func (r ApparentRepo) IsEmpty() bool {
 }
 
 // CanonicalRepo represents a fully-qualified repository name.
-// Format: module_name+version or module_name~ for root module.
+// Format: module_name+version or module_name~ for root module. For the name
+// a given Bazel release uses, see CanonicalRepoName.
 type CanonicalRepo struct {
 	module  Module
 	version Version
@@ -124,6 +126,57 @@ func (r CanonicalRepo) String() string {
 	return r.module.String() + "+" + r.version.String()
 }
 
+// bazelReleaseRegex captures the major and minor number of a Bazel release,
+// ignoring patch numbers and suffixes such as "rc1" or "-pre.20250101.1".
+var bazelReleaseRegex = regexp.MustCompile(`^(\d+)\.(\d+)`)
+
+// CanonicalRepoName returns the canonical name that Bazel release
+// bazelVersion (e.g. "7.4.1") gives the repository of a module, the
+// directory name under $(bazel info output_base)/external:
+//
+//   - Bazel 8 and later: "rules_go+", or "rules_go+0.50.1"
+//   - Bazel 7.1 to 7.x: "rules_go~", or "rules_go~0.50.1"
+//   - Bazel 6.0 to 7.0: "rules_go~0.50.1"
+//
+// Since Bazel 7.1 the version is part of the name only when the dependency
+// graph keeps several versions of the module (multiple_version_override);
+// pass an empty version for any other module. Earlier releases always need
+// the version. An empty bazelVersion means the latest naming scheme.
+//
+// Bazel 7.4 can opt into the "+" separator with
+// --incompatible_use_plus_in_repo_names; this reports the default.
+//
+// Reference: ModuleKey.getCanonicalRepoNameWithVersion and
+// getCanonicalRepoNameWithoutVersion
+// See: https://github.com/bazelbuild/bazel/blob/master/src/main/java/com/google/devtools/build/lib/bazel/bzlmod/ModuleKey.java
+func CanonicalRepoName(module Module, version Version, bazelVersion string) (string, error) {
+	if module.IsEmpty() {
+		return "", fmt.Errorf("canonical repo name: module name is empty")
+	}
+
+	separator, versionOptional := "+", true
+	if bazelVersion != "" {
+		m := bazelReleaseRegex.FindStringSubmatch(bazelVersion)
+		if m == nil {
+			return "", fmt.Errorf("canonical repo name: invalid Bazel version %q", bazelVersion)
+		}
+		major, _ := strconv.Atoi(m[1])
+		minor, _ := strconv.Atoi(m[2])
+		switch {
+		case major < 6:
+			return "", fmt.Errorf("canonical repo name: Bazel %s predates the ~ naming scheme", bazelVersion)
+		case major < 8:
+			separator = "~"
+			versionOptional = major == 7 && minor >= 1
+		}
+	}
+
+	if version.IsEmpty() && !versionOptional {
+		return "", fmt.Errorf("canonical repo name: Bazel %s names %s with its version", bazelVersion, module)
+	}
+	return module.String() + separator + version.String(), nil
+}
+
 // Module returns the module component.
 func (r CanonicalRepo) Module() Module {
 	return r.module
@@ -159,6 +212,10 @@ func (i StarlarkIdentifier) String() string {
 
 // ApparentLabel represents a label in the current context.
 // Format: @repo//package:target or //package:target or :target
+//
+// The target may be implicit: //package names the target with the same name
+// as the last package component, and @repo is short for @repo//:repo. Target
+// returns the implicit name in both cases.
 type ApparentLabel struct {
 	repo   ApparentRepo
 	pkg    string
@@ -170,10 +227,10 @@ type ApparentLabel struct {
 func ParseApparentLabel(s string) (ApparentLabel, error) {
 	label := ApparentLabel{raw: s}
 
-	// Handle @repo//pkg:target
+	// Handle @repo//pkg:target, and @repo for @repo//:repo
 	if strings.HasPrefix(s, "@") {
 		repoName, rest, found := strings.Cut(s[1:], "//")
-		if !found {
+		if !found && (repoName == "" || strings.ContainsAny(repoName, "/:")) {
 			return ApparentLabel{}, fmt.Errorf("invalid label %q: missing //", s)
 		}
 		repo, err := NewApparentRepo(repoName)
@@ -181,6 +238,10 @@ func ParseApparentLabel(s string) (ApparentLabel, error) {
 			return ApparentLabel{}, fmt.Errorf("invalid label %q: %w", s, err)
 		}
 		label.repo = repo
+		if !found {
+			label.target = repoName
+			return label, nil
+		}
 		s = "//" + rest
 	}
 
@@ -204,6 +265,10 @@ func ParseApparentLabel(s string) (ApparentLabel, error) {
 		return ApparentLabel{}, fmt.Errorf("invalid label %q", s)
 	}
 
+	if label.target == "" {
+		return ApparentLabel{}, fmt.Errorf("invalid label %q: empty target name", label.raw)
+	}
+
 	return label, nil
 }
 
@@ -212,6 +277,13 @@ func (l ApparentLabel) String() string {
 	return l.raw
 }
 
+// Equal reports whether l and other name the same target, however they were
+// written: "//foo/bar" and "//foo/bar:bar" are equal, as are "@foo" and
+// "@foo//:foo".
+func (l ApparentLabel) Equal(other ApparentLabel) bool {
+	return l.repo == other.repo && l.pkg == other.pkg && l.target == other.target
+}
+
 // Repo returns the repository component.
 func (l ApparentLabel) Repo() ApparentRepo {
 	return l.repo
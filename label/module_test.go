@@ -123,6 +123,38 @@ func TestCanonicalRepo(t *testing.T) {
 	}
 }
 
+func TestCanonicalRepoName(t *testing.T) {
+	module := MustModule("rules_go")
+	tests := []struct {
+		version      string
+		bazelVersion string
+		want         string
+		wantErr      bool
+	}{
+		{"", "", "rules_go+", false},
+		{"0.50.1", "", "rules_go+0.50.1", false},
+		{"", "8.0.0", "rules_go+", false},
+		{"", "9.0.0-pre.20250101.1", "rules_go+", false},
+		{"", "7.4.1", "rules_go~", false},
+		{"0.50.1", "7.1.0", "rules_go~0.50.1", false},
+		{"0.50.1", "7.0.2", "rules_go~0.50.1", false},
+		{"0.50.1", "6.5.0", "rules_go~0.50.1", false},
+		{"", "7.0.0", "", true},       // the version is required before 7.1
+		{"0.50.1", "5.4.0", "", true}, // no ~ naming before Bazel 6
+		{"", "latest", "", true},
+	}
+	for _, tt := range tests {
+		got, err := CanonicalRepoName(module, MustVersion(tt.version), tt.bazelVersion)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("CanonicalRepoName(%q, %q) = %q, %v; want %q, error %v", tt.version, tt.bazelVersion, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	if _, err := CanonicalRepoName(Module{}, Version{}, ""); err == nil {
+		t.Error("CanonicalRepoName with an empty module succeeded, want error")
+	}
+}
+
 func TestParseApparentLabel(t *testing.T) {
 	tests := []struct {
 		input      string
@@ -136,8 +168,15 @@ func TestParseApparentLabel(t *testing.T) {
 		{"//pkg:target", "", "pkg", "target", false},
 		{"//pkg/sub:target", "", "pkg/sub", "target", false},
 		{"//pkg", "", "pkg", "pkg", false},
+		{"//foo/bar", "", "foo/bar", "bar", false},
+		{"@foo//bar/baz", "foo", "bar/baz", "baz", false},
+		{"@foo", "foo", "", "foo", false},
+		{"@foo//:foo", "foo", "", "foo", false},
 		{":target", "", "", "target", false},
 		{"invalid", "", "", "", true},
+		{"//pkg:", "", "", "", true},
+		{"//", "", "", "", true},
+		{"@", "", "", "", true},
 	}
 
 	for _, tt := range tests {
@@ -166,6 +205,36 @@ func TestParseApparentLabel(t *testing.T) {
 	}
 }
 
+func TestApparentLabelEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"//foo/bar", "//foo/bar:bar", true},
+		{"@foo", "@foo//:foo", true},
+		{"@foo//bar/baz", "@foo//bar/baz:baz", true},
+		{"//foo/bar", "//foo:bar", false},
+		{"@foo//bar", "//bar", false},
+	}
+	for _, tt := range tests {
+		a, err := ParseApparentLabel(tt.a)
+		if err != nil {
+			t.Fatalf("ParseApparentLabel(%q) error: %v", tt.a, err)
+		}
+		b, err := ParseApparentLabel(tt.b)
+		if err != nil {
+			t.Fatalf("ParseApparentLabel(%q) error: %v", tt.b, err)
+		}
+		if got := a.Equal(b); got != tt.want {
+			t.Errorf("%q.Equal(%q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		// String keeps the form the label was written in.
+		if a.String() != tt.a {
+			t.Errorf("ParseApparentLabel(%q).String() = %q", tt.a, a.String())
+		}
+	}
+}
+
 func TestStarlarkIdentifier(t *testing.T) {
 	tests := []struct {
 		input   string
@@ -46,5 +46,7 @@
 // # Compatibility
 //
 // This package targets lockfile version 26 (Bazel 7.x/8.x). Older versions
-// may have different schemas and are not fully supported.
+// may have different schemas and are not fully supported. Use
+// ReadFileWithMigration, or Migrate on a parsed lockfile, to upgrade one to
+// version 26 while keeping its registry file hashes.
 package lockfile
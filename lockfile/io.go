@@ -23,11 +23,34 @@ func ReadFile(path string) (*Lockfile, error) {
 }
 
 // Parse parses lockfile JSON data.
+//
+// Lockfiles older than CurrentVersion are parsed leniently, so that they can
+// be passed to Migrate. Module extension results that Bazel 6 stored without
+// evaluation factors are moved under the "general" factor, and entries that
+// do not fit the current schema are left out.
 func Parse(data []byte) (*Lockfile, error) {
 	var lf Lockfile
-	if err := json.Unmarshal(data, &lf); err != nil {
+	// The outer ModuleExtensions field shadows the embedded one, so entries
+	// are decoded below, once the version is known.
+	doc := struct {
+		*lockfileFields
+		ModuleExtensions map[string]json.RawMessage `json:"moduleExtensions"`
+	}{lockfileFields: (*lockfileFields)(&lf)}
+	if err := json.Unmarshal(data, &doc); err != nil {
 		return nil, fmt.Errorf("failed to parse lockfile JSON: %w", err)
 	}
+	if doc.ModuleExtensions != nil {
+		lf.ModuleExtensions = make(map[string]ModuleExtensionEntry, len(doc.ModuleExtensions))
+	}
+	for id, raw := range doc.ModuleExtensions {
+		entry, err := parseExtensionEntry(raw, lf.Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse lockfile JSON: module extension %s: %w", id, err)
+		}
+		if entry != nil {
+			lf.ModuleExtensions[id] = entry
+		}
+	}
 
 	// Initialize nil maps to empty maps for consistency
 	if lf.RegistryFileHashes == nil {
@@ -40,12 +63,47 @@ func Parse(data []byte) (*Lockfile, error) {
 		lf.ModuleExtensions = make(map[string]ModuleExtensionEntry)
 	}
 	if lf.Facts == nil {
+		lf.omitFacts = true
 		lf.Facts = make(map[string]json.RawMessage)
 	}
 
 	return &lf, nil
 }
 
+// lockfileFields has the fields of Lockfile without its methods.
+type lockfileFields Lockfile
+
+// parseExtensionEntry decodes the results of one module extension from a
+// lockfile of the given version. It returns a nil entry for results of an
+// older version that do not fit the current schema.
+func parseExtensionEntry(raw json.RawMessage, version int) (ModuleExtensionEntry, error) {
+	var entry ModuleExtensionEntry
+	if version >= CurrentVersion {
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return nil, err
+		}
+		return entry, nil
+	}
+
+	// Bazel 6 stored the evaluation data directly, without the evaluation
+	// factor key, and always has a bzlTransitiveDigest.
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, nil
+	}
+	if _, unkeyed := fields["bzlTransitiveDigest"]; unkeyed {
+		var data ModuleExtensionData
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, nil
+		}
+		return ModuleExtensionEntry{"general": data}, nil
+	}
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, nil
+	}
+	return entry, nil
+}
+
 // WriteFile writes the lockfile to the given path with deterministic formatting.
 func (l *Lockfile) WriteFile(path string) error {
 	data, err := l.Marshal()
@@ -65,7 +123,13 @@ func (l *Lockfile) WriteTo(w io.Writer) (int64, error) {
 	return int64(n), err
 }
 
-// Marshal serializes the lockfile to JSON with deterministic key ordering.
+// Marshal serializes the lockfile to JSON the way Bazel formats it: top-level
+// keys in Bazel's order (lockFileVersion, registryFileHashes,
+// selectedYankedVersions, moduleExtensions, facts), map keys sorted, two-space
+// indentation, no HTML escaping and a trailing newline. Module extension data
+// read by Parse is written back as Bazel wrote it (see ModuleExtensionData),
+// so reading and writing an unmodified Bazel lockfile reproduces it byte for
+// byte.
 func (l *Lockfile) Marshal() ([]byte, error) {
 	// Use a custom marshaling approach for deterministic output
 	return marshalDeterministic(l)
@@ -93,7 +157,10 @@ func marshalDeterministic(l *Lockfile) ([]byte, error) {
 		RegistryFileHashes:     sortedNullableStringMap(l.RegistryFileHashes),
 		SelectedYankedVersions: sortedStringMap(l.SelectedYankedVersions),
 		ModuleExtensions:       sortedExtensions(l.ModuleExtensions),
-		Facts:                  sortedFacts(l.Facts),
+	}
+	if len(l.Facts) > 0 || !l.omitFacts {
+		facts := sortedFacts(l.Facts)
+		ordered.Facts = &facts
 	}
 
 	var buf bytes.Buffer
@@ -112,7 +179,7 @@ type orderedLockfile struct {
 	RegistryFileHashes     orderedNullableStringMap `json:"registryFileHashes"`
 	SelectedYankedVersions orderedStringMap         `json:"selectedYankedVersions"`
 	ModuleExtensions       orderedExtensionMap      `json:"moduleExtensions"`
-	Facts                  orderedRawMessageMap     `json:"facts"`
+	Facts                  *orderedRawMessageMap    `json:"facts,omitempty"`
 }
 
 // orderedNullableStringMap maintains insertion order for JSON marshaling.
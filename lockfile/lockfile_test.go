@@ -3,8 +3,11 @@ package lockfile
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -156,6 +159,38 @@ func TestFromResolution_UsesRawHexHashes(t *testing.T) {
 	}
 }
 
+func TestFromResolution_WithHashFunc(t *testing.T) {
+	stub := func(content []byte) string {
+		return fmt.Sprintf("stub-%d", len(content))
+	}
+
+	lf := FromResolution([]ModuleResolution{
+		{Name: "a", Version: "1.0.0", RegistryURL: "https://example.com", ModuleFileContent: []byte("abc")},
+		{Name: "b", Version: "2.0.0", RegistryURL: "https://example.com/", ModuleFileContent: []byte("abcdef")},
+	}, WithHashFunc(stub))
+
+	want := map[string]string{
+		"https://example.com/modules/a/1.0.0/MODULE.bazel": "stub-3",
+		"https://example.com/modules/b/2.0.0/MODULE.bazel": "stub-6",
+	}
+	if len(lf.RegistryFileHashes) != len(want) {
+		t.Fatalf("len(RegistryFileHashes) = %d, want %d", len(lf.RegistryFileHashes), len(want))
+	}
+	for url, wantHash := range want {
+		if got := lf.GetRegistryHash(url); got != wantHash {
+			t.Errorf("GetRegistryHash(%s) = %q, want %q", url, got, wantHash)
+		}
+	}
+
+	// A nil hash function keeps the Bazel-compatible default.
+	lf = FromResolution([]ModuleResolution{
+		{Name: "a", Version: "1.0.0", RegistryURL: "https://example.com", ModuleFileContent: []byte("abc")},
+	}, WithHashFunc(nil))
+	if got := lf.GetRegistryHash("https://example.com/modules/a/1.0.0/MODULE.bazel"); got != HashContent([]byte("abc")) {
+		t.Errorf("default hash = %q, want %q", got, HashContent([]byte("abc")))
+	}
+}
+
 func TestLockfile_YankedVersion(t *testing.T) {
 	lf := New()
 
@@ -361,6 +396,44 @@ func TestLockfile_Diff(t *testing.T) {
 	}
 }
 
+func TestLockfileDiff_String(t *testing.T) {
+	old := New()
+	old.SetRegistryHash("https://bcr.bazel.build/modules/a/1.0.0/MODULE.bazel", "aaa")
+	old.SetRegistryHash("https://bcr.bazel.build/modules/b/1.0.0/MODULE.bazel", "bbb")
+	old.AllowYankedVersion(ModuleKey{Name: "c", Version: "1.0.0"}, "bad release")
+	old.AllowYankedVersion(ModuleKey{Name: "d", Version: "1.0.0"}, "old reason")
+	old.ModuleExtensions["@@rules_go+//go:extensions.bzl%go_sdk"] = ModuleExtensionEntry{}
+
+	updated := New()
+	updated.SetRegistryHash("https://bcr.bazel.build/modules/b/1.0.0/MODULE.bazel", "ccc")
+	updated.SetRegistryHash("https://bcr.bazel.build/modules/e/1.0.0/MODULE.bazel", "eee")
+	updated.AllowYankedVersion(ModuleKey{Name: "d", Version: "1.0.0"}, "new reason")
+	updated.ModuleExtensions["@@gazelle+//:extensions.bzl%go_deps"] = ModuleExtensionEntry{}
+
+	diff := old.Diff(updated)
+	want := `registryFileHashes:
+  + https://bcr.bazel.build/modules/e/1.0.0/MODULE.bazel eee
+  - https://bcr.bazel.build/modules/a/1.0.0/MODULE.bazel aaa
+  ~ https://bcr.bazel.build/modules/b/1.0.0/MODULE.bazel bbb -> ccc
+selectedYankedVersions:
+  - c@1.0.0: bad release
+  ~ d@1.0.0: old reason -> new reason
+moduleExtensions:
+  + @@gazelle+//:extensions.bzl%go_deps
+  - @@rules_go+//go:extensions.bzl%go_sdk
+`
+	if got := diff.String(); got != want {
+		t.Errorf("String() =\n%s\nwant:\n%s", got, want)
+	}
+	if !strings.Contains(diff.Summary(), "extensions: 2") {
+		t.Errorf("Summary() = %q, want extension count", diff.Summary())
+	}
+
+	if got := old.Diff(old).String(); got != "no changes\n" {
+		t.Errorf("String() of identical lockfiles = %q, want %q", got, "no changes\n")
+	}
+}
+
 func TestHashContent(t *testing.T) {
 	content := []byte("hello world")
 	hash := HashContent(content)
@@ -423,6 +496,138 @@ func TestParse_RealLockfile(t *testing.T) {
 	}
 }
 
+func TestMarshal_RoundTripsBazelLockfile(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "bazel-7.4.MODULE.bazel.lock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lf, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	got, err := lf.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("Marshal() does not reproduce the Bazel lockfile:\n%s", got)
+	}
+
+	ext := lf.ModuleExtensions["@@apple_support~//crosstool:setup.bzl%apple_cc_configure_extension"]["general"]
+	if ext.UsagesDigest != "aLmqbvowmHkkBPve05yyDNGN7oh7QE9kBADr3QIZTZs=" {
+		t.Errorf("UsagesDigest = %q", ext.UsagesDigest)
+	}
+	if spec := ext.GeneratedRepoSpecs["local_config_apple_cc"]; spec.RuleClassName != "_apple_cc_autoconf" {
+		t.Errorf("GeneratedRepoSpecs[local_config_apple_cc] = %+v", spec)
+	}
+	if want := []string{"apple_support~", "bazel_tools", "bazel_tools"}; len(ext.RecordedRepoMappingEntries) != 1 ||
+		!slices.Equal(ext.RecordedRepoMappingEntries[0], want) {
+		t.Errorf("RecordedRepoMappingEntries = %v, want [%v]", ext.RecordedRepoMappingEntries, want)
+	}
+}
+
+func TestMarshal_ModifiedExtensionData(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "bazel-7.4.MODULE.bazel.lock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	id := "@@platforms//host:extension.bzl%host_platform"
+	ext := lf.ModuleExtensions[id]["general"]
+	ext.UsagesDigest = "changed"
+	lf.ModuleExtensions[id]["general"] = ext
+
+	got, err := lf.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(got), `"usagesDigest": "changed"`) {
+		t.Errorf("Marshal() did not write the modified field:\n%s", got)
+	}
+}
+
+func TestParse_DeprecatedGeneralData(t *testing.T) {
+	// Earlier versions of this package nested evaluation data under "general".
+	data := []byte(`{
+  "lockFileVersion": 13,
+  "registryFileHashes": {},
+  "selectedYankedVersions": {},
+  "moduleExtensions": {
+    "//:ext.bzl%ext": {
+      "general": {
+        "general": {
+          "usagesDigest": "abc",
+          "recordedRepoMappingEntries": [
+            "repo"
+          ]
+        },
+        "recordedInputs": [
+          "FILE:@@//:input.txt"
+        ]
+      }
+    }
+  }
+}
+`)
+	lf, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	ext := lf.ModuleExtensions["//:ext.bzl%ext"]["general"]
+	if ext.General == nil || ext.General.UsagesDigest != "abc" || !slices.Equal(ext.General.RecordedRepoMappingEntries, []string{"repo"}) {
+		t.Errorf("General = %+v, want the nested data", ext.General)
+	}
+	if !slices.Equal(ext.RecordedInputs, []string{"FILE:@@//:input.txt"}) {
+		t.Errorf("RecordedInputs = %v", ext.RecordedInputs)
+	}
+
+	got, err := lf.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("Marshal() =\n%s\nwant:\n%s", got, data)
+	}
+}
+
+func TestMarshal_Facts(t *testing.T) {
+	// Lockfiles created here target the current format, which has facts.
+	created, err := New().Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "lockFileVersion": 26,
+  "registryFileHashes": {},
+  "selectedYankedVersions": {},
+  "moduleExtensions": {},
+  "facts": {}
+}
+`
+	if string(created) != want {
+		t.Errorf("New().Marshal() =\n%s\nwant:\n%s", created, want)
+	}
+
+	// A parsed lockfile without facts stays without them.
+	lf, err := Parse([]byte(`{"lockFileVersion": 13, "registryFileHashes": {}, "selectedYankedVersions": {}, "moduleExtensions": {}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := lf.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(got), "facts") {
+		t.Errorf("Marshal() added facts to a lockfile without them:\n%s", got)
+	}
+}
+
 func TestExists(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -487,3 +692,221 @@ func TestMarshal_Deterministic(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestValidateHashes(t *testing.T) {
+	data := []byte(`{
+  "lockFileVersion": 26,
+  "registryFileHashes": {
+    "https://bcr.bazel.build/bazel_registry.json": "8a28e4aff86ee05c0e2f2d5ec8d7d1a4bb1a4b7c7d1e8b1a6f1d6d3e1e6c5a4b",
+    "https://bcr.bazel.build/modules/missing/1.0.0/MODULE.bazel": null,
+    "https://bcr.bazel.build/modules/rules_go/0.50.1/MODULE.bazel": "8a28e4aff86ee05c0e2f2d5ec8d7d1a4",
+    "https://bcr.bazel.build/modules/rules_go/0.50.1/source.json": "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=",
+    "https://bcr.bazel.build/modules/skylib/1.7.1/MODULE.bazel": "sha256-47DEQpj8HBSa+/TImW+5JCeu",
+    "https://bcr.bazel.build/modules/zlib/1.3.1/MODULE.bazel": "md5-1B2M2Y8AsgTpgAmY7PhCfg=="
+  },
+  "selectedYankedVersions": {},
+  "moduleExtensions": {}
+}`)
+
+	lf, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	errs := lf.ValidateHashes()
+	want := []struct{ url, reason string }{
+		{"https://bcr.bazel.build/modules/rules_go/0.50.1/MODULE.bazel", "sha256 hex digest is 32 characters, want 64"},
+		{"https://bcr.bazel.build/modules/skylib/1.7.1/MODULE.bazel", "sha256 digest is 18 bytes, want 32"},
+		{"https://bcr.bazel.build/modules/zlib/1.3.1/MODULE.bazel", `unsupported SRI algorithm "md5"`},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("ValidateHashes() = %v, want %d errors", errs, len(want))
+	}
+	for i, w := range want {
+		if errs[i].URL != w.url || errs[i].Reason != w.reason {
+			t.Errorf("errs[%d] = %s / %s, want %s / %s", i, errs[i].URL, errs[i].Reason, w.url, w.reason)
+		}
+		if errs[i].Value != *lf.RegistryFileHashes[w.url] {
+			t.Errorf("errs[%d].Value = %q, want the lockfile value", i, errs[i].Value)
+		}
+	}
+
+	if errs := New().ValidateHashes(); errs != nil {
+		t.Errorf("ValidateHashes() on empty lockfile = %v, want nil", errs)
+	}
+}
+
+func TestHashFormatProblem(t *testing.T) {
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{HashContent([]byte("content")), true},
+		{"sha384-OLBgp1GsljhM2TJ+sbHjaiH9txEUvgdDTAzHv2P24donTt6/529l+9Ua0vFImLlb", true},
+		{"", false},
+		{"not-hex-at-all", false},
+		{"zz" + HashContent([]byte("content"))[2:], false},
+		{strings.ToUpper(HashContent([]byte("content"))), false},
+		{"sha256-not*base64", false},
+	}
+	for _, tt := range tests {
+		if got := hashFormatProblem(tt.value) == ""; got != tt.valid {
+			t.Errorf("hashFormatProblem(%q) valid = %v, want %v", tt.value, got, tt.valid)
+		}
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	// Bazel 7.1 (version 6) lockfile with fields later versions dropped.
+	v6 := `{
+  "lockFileVersion": 6,
+  "moduleFileHash": "0e3e315145ac7ee7a4e0ac825e1c5e03c068ec1254dd42c3caaecb27e921dc4d",
+  "flags": {"cmdRegistries": ["https://bcr.bazel.build/"]},
+  "localOverrideHashes": {},
+  "registryFileHashes": {
+    "https://bcr.bazel.build/modules/rules_go/0.46.0/MODULE.bazel": "aaa",
+    "https://bcr.bazel.build/modules/missing/1.0.0/MODULE.bazel": null
+  },
+  "selectedYankedVersions": {"protobuf@3.19.0": "CVE"},
+  "moduleDepGraph": {"<root>": {"name": "", "version": ""}},
+  "moduleExtensions": {
+    "@@rules_go~//go:extensions.bzl%go_sdk": {
+      "general": {"bzlTransitiveDigest": "x", "accumulatedFileDigests": {}}
+    }
+  }
+}`
+	old, err := Parse([]byte(v6))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	migrated, err := Migrate(old)
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if migrated.Version != CurrentVersion {
+		t.Errorf("Version = %d, want %d", migrated.Version, CurrentVersion)
+	}
+	if got := migrated.GetRegistryHash("https://bcr.bazel.build/modules/rules_go/0.46.0/MODULE.bazel"); got != "aaa" {
+		t.Errorf("registry hash = %q, want aaa", got)
+	}
+	if !migrated.IsRegistryHashMissing("https://bcr.bazel.build/modules/missing/1.0.0/MODULE.bazel") {
+		t.Error("null registry hash should be preserved")
+	}
+	if migrated.GetYankedVersionReason(ModuleKey{Name: "protobuf", Version: "3.19.0"}) != "CVE" {
+		t.Error("selected yanked version should be preserved")
+	}
+	if len(migrated.ModuleExtensions) != 0 {
+		t.Errorf("ModuleExtensions = %v, want none", migrated.ExtensionIDs())
+	}
+	if old.Version != 6 || len(old.ModuleExtensions) != 1 {
+		t.Error("Migrate() modified its input")
+	}
+
+	data, err := migrated.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dropped := range []string{"moduleFileHash", "moduleDepGraph", "flags"} {
+		if strings.Contains(string(data), dropped) {
+			t.Errorf("migrated lockfile still contains %s:\n%s", dropped, data)
+		}
+	}
+	if !strings.Contains(string(data), `"facts": {}`) {
+		t.Errorf("migrated lockfile has no facts:\n%s", data)
+	}
+}
+
+func TestReadFileWithMigration_ModuleExtensions(t *testing.T) {
+	tests := []struct {
+		file    string
+		version int
+		ext     string
+		factor  string
+		digest  string
+	}{
+		{
+			// Bazel 6 stores extension results without evaluation factors.
+			file:    "bazel-6.6.MODULE.bazel.lock",
+			version: 3,
+			ext:     "@@rules_go~0.41.0//go:extensions.bzl%go_sdk",
+			factor:  "general",
+			digest:  "Wf2NQ4tkOKxq3H1qzuZYdXW1fd2nsqYIrlBOT2QZqYw=",
+		},
+		{
+			file:    "bazel-7.1.MODULE.bazel.lock",
+			version: 6,
+			ext:     "@@rules_jvm_external~//:extensions.bzl%maven",
+			factor:  "os:linux,arch:amd64",
+			digest:  "4ijz6uc3T4E+d+U8LQv4EAt+8OqZNVY/lzvhLx3y1yg=",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join("testdata", tt.file)
+
+			old, err := ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+			if old.Version != tt.version {
+				t.Errorf("Version = %d, want %d", old.Version, tt.version)
+			}
+			if got := old.ModuleExtensions[tt.ext][tt.factor].BzlTransitiveDigest; got != tt.digest {
+				t.Errorf("ModuleExtensions[%s][%s].BzlTransitiveDigest = %q, want %q", tt.ext, tt.factor, got, tt.digest)
+			}
+
+			lf, err := ReadFileWithMigration(path)
+			if err != nil {
+				t.Fatalf("ReadFileWithMigration() error = %v", err)
+			}
+			if lf.Version != CurrentVersion {
+				t.Errorf("Version = %d, want %d", lf.Version, CurrentVersion)
+			}
+			if len(lf.ModuleExtensions) != 0 {
+				t.Errorf("ModuleExtensions = %v, want none", lf.ExtensionIDs())
+			}
+		})
+	}
+}
+
+func TestParse_CurrentVersionRejectsMalformedExtensions(t *testing.T) {
+	data := fmt.Sprintf(`{"lockFileVersion": %d, "moduleExtensions": {"//:ext.bzl%%ext": {"bzlTransitiveDigest": "x"}}}`, CurrentVersion)
+	if _, err := Parse([]byte(data)); err == nil {
+		t.Error("Parse() of a current lockfile with unkeyed extension data: expected error")
+	}
+}
+
+func TestMigrate_Errors(t *testing.T) {
+	if _, err := Migrate(nil); err == nil {
+		t.Error("Migrate(nil): expected error")
+	}
+	if _, err := Migrate(&Lockfile{Version: CurrentVersion + 2}); err == nil {
+		t.Error("Migrate() of a newer version: expected error")
+	}
+}
+
+func TestReadFileWithMigration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "MODULE.bazel.lock")
+	v3 := `{"lockFileVersion": 3, "moduleFileHash": "abc", "moduleDepGraph": {}, "moduleExtensions": {}}`
+	if err := os.WriteFile(path, []byte(v3), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	lf, err := ReadFileWithMigration(path)
+	if err != nil {
+		t.Fatalf("ReadFileWithMigration() error = %v", err)
+	}
+	if lf.Version != CurrentVersion {
+		t.Errorf("Version = %d, want %d", lf.Version, CurrentVersion)
+	}
+
+	plain, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.Version != 3 {
+		t.Errorf("ReadFile() Version = %d, want 3 (no migration)", plain.Version)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 )
 
 // MergeStrategy defines how to handle conflicts when merging lockfiles.
@@ -141,12 +144,16 @@ func (l *Lockfile) mergeFacts(other *Lockfile, opts MergeOptions) {
 	}
 }
 
-// Diff returns the differences between two lockfiles.
+// Diff returns the differences between two lockfiles, with l as the old
+// lockfile and other as the new one.
 func (l *Lockfile) Diff(other *Lockfile) *LockfileDiff {
 	diff := &LockfileDiff{
 		AddedHashes:   make(map[string]*string),
 		RemovedHashes: make(map[string]*string),
 		ChangedHashes: make(map[string][2]*string),
+		AddedYanked:   make(map[string]string),
+		RemovedYanked: make(map[string]string),
+		ChangedYanked: make(map[string][2]string),
 	}
 
 	// Compare registry hashes
@@ -164,6 +171,35 @@ func (l *Lockfile) Diff(other *Lockfile) *LockfileDiff {
 		}
 	}
 
+	// Compare selected yanked versions
+	for key, reason := range other.SelectedYankedVersions {
+		existing, exists := l.SelectedYankedVersions[key]
+		if !exists {
+			diff.AddedYanked[key] = reason
+		} else if existing != reason {
+			diff.ChangedYanked[key] = [2]string{existing, reason}
+		}
+	}
+	for key, reason := range l.SelectedYankedVersions {
+		if _, exists := other.SelectedYankedVersions[key]; !exists {
+			diff.RemovedYanked[key] = reason
+		}
+	}
+
+	// Compare module extensions by identifier
+	for id := range other.ModuleExtensions {
+		if _, exists := l.ModuleExtensions[id]; !exists {
+			diff.AddedExtensions = append(diff.AddedExtensions, id)
+		}
+	}
+	for id := range l.ModuleExtensions {
+		if _, exists := other.ModuleExtensions[id]; !exists {
+			diff.RemovedExtensions = append(diff.RemovedExtensions, id)
+		}
+	}
+	slices.Sort(diff.AddedExtensions)
+	slices.Sort(diff.RemovedExtensions)
+
 	// Compare version
 	if l.Version != other.Version {
 		diff.VersionChanged = true
@@ -183,6 +219,16 @@ type LockfileDiff struct {
 	AddedHashes   map[string]*string
 	RemovedHashes map[string]*string
 	ChangedHashes map[string][2]*string // [old, new]
+
+	// Selected yanked versions, keyed by "name@version", with their reasons.
+	AddedYanked   map[string]string
+	RemovedYanked map[string]string
+	ChangedYanked map[string][2]string // [old, new] reason
+
+	// Module extension identifiers, sorted. Changes to an extension's
+	// evaluation results are not compared.
+	AddedExtensions   []string
+	RemovedExtensions []string
 }
 
 // IsEmpty returns true if there are no differences.
@@ -190,7 +236,67 @@ func (d *LockfileDiff) IsEmpty() bool {
 	return !d.VersionChanged &&
 		len(d.AddedHashes) == 0 &&
 		len(d.RemovedHashes) == 0 &&
-		len(d.ChangedHashes) == 0
+		len(d.ChangedHashes) == 0 &&
+		len(d.AddedYanked) == 0 &&
+		len(d.RemovedYanked) == 0 &&
+		len(d.ChangedYanked) == 0 &&
+		len(d.AddedExtensions) == 0 &&
+		len(d.RemovedExtensions) == 0
+}
+
+// String renders every difference, one per line and grouped by section, for
+// review of a lockfile update. Added entries are prefixed "+", removed ones
+// "-" and changed ones "~". Unlike Summary, which only counts changes, it
+// lists them.
+func (d *LockfileDiff) String() string {
+	if d.IsEmpty() {
+		return "no changes\n"
+	}
+
+	var b strings.Builder
+	if d.VersionChanged {
+		fmt.Fprintf(&b, "lockFileVersion: %d -> %d\n", d.OldVersion, d.NewVersion)
+	}
+
+	if len(d.AddedHashes)+len(d.RemovedHashes)+len(d.ChangedHashes) > 0 {
+		b.WriteString("registryFileHashes:\n")
+		for _, url := range slices.Sorted(maps.Keys(d.AddedHashes)) {
+			fmt.Fprintf(&b, "  + %s %s\n", url, registryHashString(d.AddedHashes[url]))
+		}
+		for _, url := range slices.Sorted(maps.Keys(d.RemovedHashes)) {
+			fmt.Fprintf(&b, "  - %s %s\n", url, registryHashString(d.RemovedHashes[url]))
+		}
+		for _, url := range slices.Sorted(maps.Keys(d.ChangedHashes)) {
+			change := d.ChangedHashes[url]
+			fmt.Fprintf(&b, "  ~ %s %s -> %s\n", url, registryHashString(change[0]), registryHashString(change[1]))
+		}
+	}
+
+	if len(d.AddedYanked)+len(d.RemovedYanked)+len(d.ChangedYanked) > 0 {
+		b.WriteString("selectedYankedVersions:\n")
+		for _, key := range slices.Sorted(maps.Keys(d.AddedYanked)) {
+			fmt.Fprintf(&b, "  + %s: %s\n", key, d.AddedYanked[key])
+		}
+		for _, key := range slices.Sorted(maps.Keys(d.RemovedYanked)) {
+			fmt.Fprintf(&b, "  - %s: %s\n", key, d.RemovedYanked[key])
+		}
+		for _, key := range slices.Sorted(maps.Keys(d.ChangedYanked)) {
+			change := d.ChangedYanked[key]
+			fmt.Fprintf(&b, "  ~ %s: %s -> %s\n", key, change[0], change[1])
+		}
+	}
+
+	if len(d.AddedExtensions)+len(d.RemovedExtensions) > 0 {
+		b.WriteString("moduleExtensions:\n")
+		for _, id := range d.AddedExtensions {
+			fmt.Fprintf(&b, "  + %s\n", id)
+		}
+		for _, id := range d.RemovedExtensions {
+			fmt.Fprintf(&b, "  - %s\n", id)
+		}
+	}
+
+	return b.String()
 }
 
 // Summary returns a human-readable summary of the differences.
@@ -212,6 +318,12 @@ func (d *LockfileDiff) Summary() string {
 	if len(d.ChangedHashes) > 0 {
 		result += fmt.Sprintf("changed: %d registry hashes\n", len(d.ChangedHashes))
 	}
+	if n := len(d.AddedYanked) + len(d.RemovedYanked) + len(d.ChangedYanked); n > 0 {
+		result += fmt.Sprintf("yanked: %d selected yanked versions changed\n", n)
+	}
+	if n := len(d.AddedExtensions) + len(d.RemovedExtensions); n > 0 {
+		result += fmt.Sprintf("extensions: %d module extensions added or removed\n", n)
+	}
 	return result
 }
 
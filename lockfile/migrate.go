@@ -0,0 +1,58 @@
+package lockfile
+
+import (
+	"fmt"
+	"maps"
+)
+
+// Migrate upgrades a lockfile read from an older Bazel release to the
+// CurrentVersion schema. The input is not modified.
+//
+// Registry file hashes (including null "not found" entries), selected yanked
+// versions and facts carry over unchanged. Module extension results are
+// dropped: their format differs between lockfile versions, and Bazel
+// re-evaluates extensions that have no lockfile entry. Fields that no longer
+// exist, such as moduleFileHash and moduleDepGraph in versions 3 and 6, are
+// already discarded by Parse.
+//
+// A lockfile that already has CurrentVersion is returned as a copy. Versions
+// newer than CurrentVersion cannot be migrated and return an error.
+func Migrate(lf *Lockfile) (*Lockfile, error) {
+	if lf == nil {
+		return nil, fmt.Errorf("migrate lockfile: lockfile is nil")
+	}
+	if lf.Version < 1 {
+		return nil, fmt.Errorf("migrate lockfile: invalid lockfile version %d", lf.Version)
+	}
+	if lf.Version > CurrentVersion {
+		return nil, fmt.Errorf("migrate lockfile: version %d is newer than supported version %d", lf.Version, CurrentVersion)
+	}
+
+	migrated := New()
+	for url, hash := range lf.RegistryFileHashes {
+		migrated.RegistryFileHashes[url] = cloneStringPointer(hash)
+	}
+	maps.Copy(migrated.SelectedYankedVersions, lf.SelectedYankedVersions)
+	maps.Copy(migrated.Facts, lf.Facts)
+
+	if lf.Version == CurrentVersion {
+		for id, entry := range lf.ModuleExtensions {
+			migrated.ModuleExtensions[id] = maps.Clone(entry)
+		}
+		migrated.omitFacts = lf.omitFacts
+	}
+	return migrated, nil
+}
+
+// ReadFileWithMigration reads a lockfile like ReadFile and, if it was written
+// by an older Bazel release, upgrades it to CurrentVersion with Migrate.
+func ReadFileWithMigration(path string) (*Lockfile, error) {
+	lf, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if lf.Version == CurrentVersion {
+		return lf, nil
+	}
+	return Migrate(lf)
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"slices"
+	"strings"
 )
 
 // ModuleResolution represents a resolved module for lockfile generation.
@@ -28,10 +29,37 @@ type ModuleResolution struct {
 	YankReason string
 }
 
+// HashFunc computes the registryFileHashes value for a registry file's content.
+type HashFunc func(content []byte) string
+
+// ResolutionOption configures FromResolution.
+type ResolutionOption func(*resolutionConfig)
+
+type resolutionConfig struct {
+	hash HashFunc
+}
+
+// WithHashFunc sets the function used to hash MODULE.bazel content.
+// The default is the Bazel-compatible raw SHA-256 hex digest. A nil fn keeps
+// the default.
+func WithHashFunc(fn HashFunc) ResolutionOption {
+	return func(c *resolutionConfig) {
+		if fn != nil {
+			c.hash = fn
+		}
+	}
+}
+
 // FromResolution creates a lockfile from a set of resolved modules.
-// This records the SHA256 hashes of all MODULE.bazel files that were fetched,
-// enabling reproducible builds.
-func FromResolution(modules []ModuleResolution) *Lockfile {
+// This records the hashes of all MODULE.bazel files that were fetched,
+// enabling reproducible builds. Hashes are SHA-256 hex digests unless
+// overridden with WithHashFunc.
+func FromResolution(modules []ModuleResolution, opts ...ResolutionOption) *Lockfile {
+	cfg := resolutionConfig{hash: computeSHA256}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	lf := New()
 
 	for _, m := range modules {
@@ -42,9 +70,7 @@ func FromResolution(modules []ModuleResolution) *Lockfile {
 		// Build the registry URL for this module's MODULE.bazel
 		url := buildModuleFileURL(m.RegistryURL, m.Name, m.Version)
 
-		// Compute SHA256 hash of the content
-		hash := computeSHA256(m.ModuleFileContent)
-		lf.SetRegistryHash(url, hash)
+		lf.SetRegistryHash(url, cfg.hash(m.ModuleFileContent))
 
 		// Record yanked versions that were explicitly selected
 		if m.IsYanked {
@@ -75,10 +101,8 @@ func buildModuleFileURL(registryURL, moduleName, version string) string {
 	if registryURL == "" {
 		registryURL = "https://bcr.bazel.build"
 	}
-	// Strip trailing slash if present (and URL is non-empty after default assignment)
-	if len(registryURL) > 0 && registryURL[len(registryURL)-1] == '/' {
-		registryURL = registryURL[:len(registryURL)-1]
-	}
+	// Strip trailing slashes so exactly one separates the segments
+	registryURL = strings.TrimRight(registryURL, "/")
 
 	return registryURL + "/modules/" + moduleName + "/" + version + "/MODULE.bazel"
 }
@@ -2,6 +2,8 @@ package lockfile
 
 import (
 	"encoding/json"
+	"reflect"
+	"slices"
 )
 
 // CurrentVersion is the lockfile format version this package targets.
@@ -31,22 +33,75 @@ type Lockfile struct {
 
 	// Facts contains additional facts about module extensions.
 	Facts map[string]json.RawMessage `json:"facts"`
+
+	// omitFacts is set by Parse when the file has no "facts" key, as in
+	// lockfiles written before Bazel 8, so Marshal leaves it out while Facts
+	// is empty.
+	omitFacts bool
 }
 
-// ModuleExtensionEntry contains evaluation results for a module extension.
-// The map key is the evaluation factors (OS, arch, etc.).
+// ModuleExtensionEntry contains evaluation results for a module extension,
+// keyed by the evaluation factors: "general" for results that do not depend on
+// the host, or e.g. "os:linux,arch:amd64" for extensions that declared an
+// os/arch dependency.
 type ModuleExtensionEntry map[string]ModuleExtensionData
 
-// ModuleExtensionData contains the cached data for a module extension evaluation.
+// ModuleExtensionData contains the cached data for one module extension
+// evaluation.
+//
+// Only the commonly inspected fields are modeled. A value read by Parse keeps
+// its original JSON, which Marshal writes back unchanged (including fields not
+// listed here and Bazel's key order) as long as the modeled fields are not
+// modified.
 type ModuleExtensionData struct {
-	// General contains the main extension evaluation results.
+	// BzlTransitiveDigest is a hash of the extension's transitive .bzl files.
+	BzlTransitiveDigest string `json:"bzlTransitiveDigest,omitempty"`
+
+	// UsagesDigest is a hash of how the extension is used.
+	UsagesDigest string `json:"usagesDigest,omitempty"`
+
+	// RecordedFileInputs maps files read during evaluation to their hashes.
+	RecordedFileInputs map[string]string `json:"recordedFileInputs,omitempty"`
+
+	// RecordedDirentsInputs maps directories listed during evaluation to
+	// hashes of their entries.
+	RecordedDirentsInputs map[string]string `json:"recordedDirentsInputs,omitempty"`
+
+	// EnvVariables maps environment variables read during evaluation to their
+	// values. A nil value means the variable was unset.
+	EnvVariables map[string]*string `json:"envVariables,omitempty"`
+
+	// GeneratedRepoSpecs contains the repositories generated by the extension.
+	GeneratedRepoSpecs map[string]RepoSpec `json:"generatedRepoSpecs,omitempty"`
+
+	// RecordedRepoMappingEntries lists the repo mapping lookups made during
+	// evaluation, each as [source repo, apparent name, canonical name].
+	RecordedRepoMappingEntries [][]string `json:"recordedRepoMappingEntries,omitempty"`
+
+	// General holds evaluation data nested under a "general" key, as earlier
+	// versions of this package wrote it.
+	//
+	// Deprecated: Bazel stores the evaluation data directly in the entry for
+	// the "general" evaluation factors, so General is nil for lockfiles
+	// written by Bazel. Use the fields of ModuleExtensionData instead.
 	General *ExtensionGeneral `json:"general,omitempty"`
 
 	// RecordedInputs lists inputs that were read during evaluation.
+	//
+	// Deprecated: Bazel does not write recordedInputs. Use
+	// RecordedFileInputs, RecordedDirentsInputs and EnvVariables instead.
 	RecordedInputs []string `json:"recordedInputs,omitempty"`
+
+	// raw is the JSON this value was parsed from, if any.
+	raw json.RawMessage
 }
 
 // ExtensionGeneral contains general extension evaluation data.
+//
+// Deprecated: Bazel does not nest evaluation data in lockfiles; the same
+// fields are in ModuleExtensionData, whose RecordedRepoMappingEntries holds
+// each entry as [source repo, apparent name, canonical name] instead of a
+// string.
 type ExtensionGeneral struct {
 	// BzlTransitiveDigest is a hash of the extension's transitive .bzl files.
 	BzlTransitiveDigest string `json:"bzlTransitiveDigest,omitempty"`
@@ -64,10 +119,45 @@ type ExtensionGeneral struct {
 	RecordedRepoMappingEntries []string `json:"recordedRepoMappingEntries,omitempty"`
 }
 
+// moduleExtensionDataFields has the fields of ModuleExtensionData without its
+// JSON methods.
+type moduleExtensionDataFields ModuleExtensionData
+
+// UnmarshalJSON decodes the modeled fields and keeps data for MarshalJSON.
+func (d *ModuleExtensionData) UnmarshalJSON(data []byte) error {
+	var fields moduleExtensionDataFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*d = ModuleExtensionData(fields)
+	d.raw = slices.Clone(data)
+	return nil
+}
+
+// MarshalJSON writes the JSON d was parsed from if the modeled fields still
+// match it, and the modeled fields otherwise.
+func (d ModuleExtensionData) MarshalJSON() ([]byte, error) {
+	current := moduleExtensionDataFields(d)
+	current.raw = nil
+	if d.raw != nil {
+		var parsed moduleExtensionDataFields
+		if err := json.Unmarshal(d.raw, &parsed); err == nil && reflect.DeepEqual(parsed, current) {
+			return d.raw, nil
+		}
+	}
+	return json.Marshal(current)
+}
+
 // RepoSpec describes a repository generated by a module extension.
 type RepoSpec struct {
 	// RepoRuleID identifies the repo rule used (e.g., "@@bazel_tools//tools/build_defs/repo:http.bzl%http_archive").
-	RepoRuleID string `json:"repoRuleId"`
+	// Written by Bazel 8 and later.
+	RepoRuleID string `json:"repoRuleId,omitempty"`
+
+	// BzlFile and RuleClassName identify the repo rule in lockfiles written by
+	// Bazel 7, which predate RepoRuleID.
+	BzlFile       string `json:"bzlFile,omitempty"`
+	RuleClassName string `json:"ruleClassName,omitempty"`
 
 	// Attributes are the arguments passed to the repo rule.
 	Attributes map[string]any `json:"attributes"`
@@ -0,0 +1,87 @@
+package lockfile
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// HashFormatError reports a registryFileHashes value that is not a well-formed hash.
+type HashFormatError struct {
+	// URL is the registry file URL the hash belongs to.
+	URL string
+
+	// Value is the malformed hash value as it appears in the lockfile.
+	Value string
+
+	// Reason describes what is wrong with Value.
+	Reason string
+}
+
+func (e *HashFormatError) Error() string {
+	return fmt.Sprintf("malformed hash for %s: %q: %s", e.URL, e.Value, e.Reason)
+}
+
+// sriDigestSizes maps the SRI algorithms accepted by Bazel to their digest size in bytes.
+var sriDigestSizes = map[string]int{
+	"sha256": 32,
+	"sha384": 48,
+	"sha512": 64,
+}
+
+// ValidateHashes checks that every registryFileHashes value is a well-formed
+// hash, without fetching anything. Bazel writes raw SHA-256 hex digests, so
+// those are expected; SRI strings ("sha256-<base64>", also sha384 and sha512)
+// are accepted as well. Null values record files that were probed but not
+// found and are valid.
+//
+// Errors are sorted by URL. Returns nil if all hashes are well-formed.
+func (l *Lockfile) ValidateHashes() []HashFormatError {
+	var errs []HashFormatError
+	for _, url := range slices.Sorted(maps.Keys(l.RegistryFileHashes)) {
+		hash := l.RegistryFileHashes[url]
+		if hash == nil {
+			continue
+		}
+		if reason := hashFormatProblem(*hash); reason != "" {
+			errs = append(errs, HashFormatError{URL: url, Value: *hash, Reason: reason})
+		}
+	}
+	return errs
+}
+
+// hashFormatProblem returns why value is not a valid hash, or "" if it is.
+func hashFormatProblem(value string) string {
+	if value == "" {
+		return "empty hash"
+	}
+
+	if algo, encoded, ok := strings.Cut(value, "-"); ok {
+		size, known := sriDigestSizes[algo]
+		if !known {
+			return fmt.Sprintf("unsupported SRI algorithm %q", algo)
+		}
+		digest, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return "invalid base64 digest"
+		}
+		if len(digest) != size {
+			return fmt.Sprintf("%s digest is %d bytes, want %d", algo, len(digest), size)
+		}
+		return ""
+	}
+
+	if _, err := hex.DecodeString(value); err != nil {
+		return "invalid hex digest"
+	}
+	if len(value) != 2*sriDigestSizes["sha256"] {
+		return fmt.Sprintf("sha256 hex digest is %d characters, want 64", len(value))
+	}
+	if strings.ToLower(value) != value {
+		return "hex digest must be lowercase"
+	}
+	return ""
+}
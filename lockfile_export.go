@@ -1,6 +1,12 @@
 package gobzlmod
 
-import lockpkg "github.com/albertocavalcante/go-bzlmod/lockfile"
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	lockpkg "github.com/albertocavalcante/go-bzlmod/lockfile"
+)
 
 // ToLockfile converts a resolution result into a lockfile-compatible snapshot.
 // It preserves Bazel-style registryFileHashes entries, including explicit nil
@@ -16,7 +22,7 @@ func (r *ResolutionList) ToLockfile() *lockpkg.Lockfile {
 
 	lf = lockpkg.FromRegistryFileHashes(r.RegistryFileHashes)
 	for _, module := range r.Modules {
-		if !module.Yanked {
+		if !module.Yanked && !module.YankAllowed {
 			continue
 		}
 		lf.AllowYankedVersion(lockpkg.ModuleKey{Name: module.Name, Version: module.Version}, module.YankReason)
@@ -24,3 +30,119 @@ func (r *ResolutionList) ToLockfile() *lockpkg.Lockfile {
 
 	return lf
 }
+
+// VerifyReport describes how a lockfile differs from a resolution result.
+// Modules are "name@version" keys, sorted.
+type VerifyReport struct {
+	// MissingFromLock lists resolved modules with no MODULE.bazel hash in the
+	// lockfile. Modules with a git, local_path or archive override have no
+	// registry file and are not checked.
+	MissingFromLock []string
+
+	// NotResolved lists modules whose MODULE.bazel hash is in the lockfile
+	// but which resolution never discovered: leftovers of an earlier
+	// resolution. Versions discovered but passed over by MVS are not listed,
+	// since Bazel locks every MODULE.bazel it fetches.
+	NotResolved []string
+
+	// StaleHashes lists registry files whose locked hash differs from the
+	// hash resolution computed. Only checked when resolving with
+	// WithRegistryTrace, which records those hashes.
+	StaleHashes []lockpkg.HashChange
+}
+
+// OK reports whether the lockfile is consistent with the resolution.
+func (v *VerifyReport) OK() bool {
+	return len(v.MissingFromLock) == 0 && len(v.NotResolved) == 0 && len(v.StaleHashes) == 0
+}
+
+// VerifyLockfile checks a committed lockfile against this resolution result,
+// like `go mod verify` does for go.sum: every resolved module must have a
+// MODULE.bazel hash in the lockfile, the lockfile must not lock modules that
+// resolution never discovered, and hashes recorded by a registry trace must match the
+// locked ones. Registry files are matched by their /modules/<name>/<version>/
+// path, so a module locked from any registry counts.
+//
+// No network requests are made.
+func (r *ResolutionList) VerifyLockfile(lf *lockpkg.Lockfile) *VerifyReport {
+	report := &VerifyReport{}
+
+	locked := make(map[string]bool)
+	for url, hash := range lf.RegistryFileHashes {
+		if key, ok := lockedModuleKey(url); ok && hash != nil {
+			locked[key] = true
+		}
+	}
+
+	nonRegistry := make(map[string]bool)
+	if r.root != nil {
+		for _, override := range r.root.Overrides {
+			if isNonRegistryOverride(override) {
+				nonRegistry[override.ModuleName] = true
+			}
+		}
+	}
+
+	discovered := make(map[string]bool, len(r.UnprunedModules))
+	for _, m := range r.UnprunedModules {
+		discovered[m.Key()] = true
+	}
+	for _, m := range r.Modules {
+		key := m.Key()
+		discovered[key] = true
+		if !nonRegistry[m.Name] && !locked[key] {
+			report.MissingFromLock = append(report.MissingFromLock, key)
+		}
+	}
+	for key := range locked {
+		if !discovered[key] {
+			report.NotResolved = append(report.NotResolved, key)
+		}
+	}
+
+	for url, hash := range r.RegistryFileHashes {
+		lockedHash, ok := lf.GetRegistryHashValue(url)
+		if ok && !sameHash(lockedHash, hash) {
+			report.StaleHashes = append(report.StaleHashes, lockpkg.HashChange{URL: url, OldHash: lockedHash, NewHash: hash})
+		}
+	}
+
+	slices.Sort(report.MissingFromLock)
+	slices.Sort(report.NotResolved)
+	slices.SortFunc(report.StaleHashes, func(a, b lockpkg.HashChange) int {
+		return cmp.Compare(a.URL, b.URL)
+	})
+	return report
+}
+
+// lockedModuleKey returns the "name@version" a registry MODULE.bazel URL is
+// for, or false if url is not a module file.
+func lockedModuleKey(url string) (string, bool) {
+	key, file, ok := registryModuleFile(url)
+	if !ok || file != "MODULE.bazel" {
+		return "", false
+	}
+	return key, true
+}
+
+// registryModuleFile splits a registry URL of the form
+// .../modules/<name>/<version>/<file> into the "name@version" it is for and
+// the file name, or returns false if url has another form.
+func registryModuleFile(url string) (key, file string, ok bool) {
+	_, path, ok := strings.Cut(url, "/modules/")
+	if !ok {
+		return "", "", false
+	}
+	parts := strings.Split(path, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[0] + "@" + parts[1], parts[2], true
+}
+
+func sameHash(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
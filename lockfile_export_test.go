@@ -1,6 +1,11 @@
 package gobzlmod
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"testing"
 
 	lockpkg "github.com/albertocavalcante/go-bzlmod/lockfile"
@@ -37,3 +42,94 @@ func TestResolutionList_ToLockfile(t *testing.T) {
 		t.Fatal("yanked module should be recorded in lockfile")
 	}
 }
+
+func TestResolutionList_VerifyLockfile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/dep_a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "dep_a", version = "1.0.0")
+bazel_dep(name = "dep_b", version = "1.0.0")`)
+		case "/modules/dep_b/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "dep_b", version = "1.0.0")`)
+		case "/modules/dep_a/1.0.0/source.json", "/modules/dep_b/1.0.0/source.json":
+			fmt.Fprint(w, `{"url":"https://example.com/archive.tar.gz","integrity":"sha256-aaa"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "dep_a", version = "1.0.0")
+bazel_dep(name = "local_dep", version = "1.0.0")
+local_path_override(module_name = "local_dep", path = "../local_dep")`
+
+	result, err := Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL), WithRegistryTrace())
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	if report := result.VerifyLockfile(result.ToLockfile()); !report.OK() {
+		t.Errorf("VerifyLockfile(ToLockfile()) = %+v, want OK", report)
+	}
+
+	// A lockfile from an earlier resolution: dep_b unlocked, an old module
+	// still locked, and dep_a's MODULE.bazel locked with a different hash.
+	depAURL := server.URL + "/modules/dep_a/1.0.0/MODULE.bazel"
+	lf := lockpkg.New()
+	lf.SetRegistryHash(depAURL, "0000")
+	lf.SetRegistryHash(server.URL+"/modules/old_dep/2.0.0/MODULE.bazel", "1111")
+	lf.SetRegistryHash(server.URL+"/modules/old_dep/2.0.0/source.json", "2222")
+
+	report := result.VerifyLockfile(lf)
+	if want := []string{"dep_b@1.0.0"}; !slices.Equal(report.MissingFromLock, want) {
+		t.Errorf("MissingFromLock = %v, want %v", report.MissingFromLock, want)
+	}
+	if want := []string{"old_dep@2.0.0"}; !slices.Equal(report.NotResolved, want) {
+		t.Errorf("NotResolved = %v, want %v", report.NotResolved, want)
+	}
+	if len(report.StaleHashes) != 1 || report.StaleHashes[0].URL != depAURL || *report.StaleHashes[0].OldHash != "0000" {
+		t.Errorf("StaleHashes = %+v, want dep_a's MODULE.bazel", report.StaleHashes)
+	}
+	if report.OK() {
+		t.Error("OK() = true for an inconsistent lockfile")
+	}
+}
+
+func TestResolutionList_VerifyLockfile_MVSBump(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/dep_a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "dep_a", version = "1.0.0")
+bazel_dep(name = "dep_c", version = "1.1.0")`)
+		case "/modules/dep_c/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "dep_c", version = "1.0.0")`)
+		case "/modules/dep_c/1.1.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "dep_c", version = "1.1.0")`)
+		case "/modules/dep_a/1.0.0/source.json", "/modules/dep_c/1.1.0/source.json":
+			fmt.Fprint(w, `{"url":"https://example.com/archive.tar.gz","integrity":"sha256-aaa"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// MVS bumps the root's dep_c@1.0.0 to 1.1.0, but both MODULE.bazel files
+	// were fetched and are locked.
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "dep_a", version = "1.0.0")
+bazel_dep(name = "dep_c", version = "1.0.0")`
+
+	result, err := Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL), WithRegistryTrace())
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	lf := result.ToLockfile()
+	if _, ok := lf.GetRegistryHashValue(server.URL + "/modules/dep_c/1.0.0/MODULE.bazel"); !ok {
+		t.Fatal("ToLockfile() did not lock the MODULE.bazel of the version MVS passed over")
+	}
+	if report := result.VerifyLockfile(lf); !report.OK() {
+		t.Errorf("VerifyLockfile(ToLockfile()) = %+v, want OK", report)
+	}
+}
@@ -5,12 +5,13 @@ import (
 	"sync"
 )
 
-// checkModuleMetadata fetches metadata for all modules and marks yanked/deprecated status.
+// checkModuleMetadata fetches metadata for all modules, marks yanked/deprecated
+// status and records declared licenses.
 // Follows Bazel's fail-open pattern: if metadata.json fetch fails, resolution continues.
 //
 // This function concurrently fetches metadata for all modules in the resolution list and
-// updates their Yanked, YankReason, IsDeprecated, and DeprecationReason fields based on
-// the metadata retrieved from the registry.
+// updates their Yanked, YankReason, IsDeprecated, DeprecationReason, and License fields
+// based on the metadata retrieved from the registry.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout control
@@ -22,6 +23,8 @@ import (
 //   - If "all" is in the list, no modules are marked as yanked
 //   - If "module@version" is in the list, that specific version is not marked as yanked
 //
+// Allowed yanked versions are marked YankAllowed, with their YankReason, instead.
+//
 // Error handling follows Bazel's fail-open pattern: if metadata cannot be fetched for a
 // module, that module is silently skipped and resolution continues. This matches
 // YankedVersionsFunction.java behavior.
@@ -29,12 +32,16 @@ func checkModuleMetadata(ctx context.Context, registry Registry, opts Resolution
 	// Build allowed yanked versions set for quick lookup
 	allowedYanked := buildAllowedYankedSet(opts.AllowYankedVersions)
 
+	// Metadata may be fetched only for licenses; status checks stay opt-in.
+	checkStatus := opts.CheckYanked || opts.WarnDeprecated
+
 	type result struct {
 		idx               int
 		yanked            bool
 		yankReason        string
 		deprecated        bool
 		deprecationReason string
+		license           string
 	}
 
 	results := make(chan result, len(list.Modules))
@@ -56,18 +63,22 @@ func checkModuleMetadata(ctx context.Context, registry Registry, opts Resolution
 			res := result{idx: idx}
 
 			// Check yanked status
-			if metadata.IsYanked(module.Version) {
+			if checkStatus && metadata.IsYanked(module.Version) {
 				res.yanked = true
 				res.yankReason = metadata.YankReason(module.Version)
 			}
 
 			// Check deprecated status
-			if metadata.IsDeprecated() {
+			if checkStatus && metadata.IsDeprecated() {
 				res.deprecated = true
 				res.deprecationReason = metadata.Deprecated
 			}
 
-			if res.yanked || res.deprecated {
+			if opts.FetchLicenses {
+				res.license = metadata.License
+			}
+
+			if res.yanked || res.deprecated || res.license != "" {
 				results <- res
 			}
 		}(i)
@@ -82,15 +93,18 @@ func checkModuleMetadata(ctx context.Context, registry Registry, opts Resolution
 		if res.yanked {
 			// Check if this specific module@version is allowed
 			moduleKey := list.Modules[res.idx].Name + "@" + list.Modules[res.idx].Version
-			if !allowedYanked["all"] && !allowedYanked[moduleKey] {
+			if allowedYanked["all"] || allowedYanked[moduleKey] {
+				list.Modules[res.idx].YankAllowed = true
+			} else {
 				list.Modules[res.idx].Yanked = true
-				list.Modules[res.idx].YankReason = res.yankReason
 			}
+			list.Modules[res.idx].YankReason = res.yankReason
 		}
 		if res.deprecated {
 			list.Modules[res.idx].IsDeprecated = true
 			list.Modules[res.idx].DeprecationReason = res.deprecationReason
 		}
+		list.Modules[res.idx].License = res.license
 	}
 }
 
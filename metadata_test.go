@@ -243,6 +243,98 @@ func TestCheckModuleMetadata(t *testing.T) {
 	})
 }
 
+func TestResolve_Licenses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/licensed/1.0.0/MODULE.bazel":
+			w.Write([]byte(`module(name = "licensed", version = "1.0.0")
+bazel_dep(name = "unlicensed", version = "1.0.0")`))
+		case "/modules/unlicensed/1.0.0/MODULE.bazel":
+			w.Write([]byte(`module(name = "unlicensed", version = "1.0.0")`))
+		case "/modules/licensed/metadata.json":
+			json.NewEncoder(w).Encode(map[string]any{
+				"versions":        []string{"1.0.0"},
+				"license":         "Apache-2.0",
+				"yanked_versions": map[string]string{"1.0.0": "ignored without yanked check"},
+			})
+		case "/modules/unlicensed/metadata.json":
+			json.NewEncoder(w).Encode(map[string]any{"versions": []string{"1.0.0"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "licensed", version = "1.0.0")`
+
+	list, err := Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL), WithLicenses())
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if got := list.Module("licensed").License; got != "Apache-2.0" {
+		t.Errorf("licensed License = %q, want Apache-2.0", got)
+	}
+	if got := list.Module("unlicensed").License; got != "" {
+		t.Errorf("unlicensed License = %q, want empty", got)
+	}
+	if list.Module("licensed").Yanked {
+		t.Error("licensed marked yanked, but yanked check was not enabled")
+	}
+
+	// Without the option, metadata is not consulted for licenses.
+	list, err = Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if got := list.Module("licensed").License; got != "" {
+		t.Errorf("License without WithLicenses = %q, want empty", got)
+	}
+}
+
+func TestResolve_DeprecationWarningsInSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/old_rules/1.0.0/MODULE.bazel":
+			w.Write([]byte(`module(name = "old_rules", version = "1.0.0")`))
+		case "/modules/fresh_rules/1.0.0/MODULE.bazel":
+			w.Write([]byte(`module(name = "fresh_rules", version = "1.0.0")`))
+		case "/modules/old_rules/metadata.json":
+			json.NewEncoder(w).Encode(map[string]any{
+				"versions":   []string{"1.0.0"},
+				"deprecated": "use fresh_rules instead",
+			})
+		case "/modules/fresh_rules/metadata.json":
+			// No deprecated field at all.
+			json.NewEncoder(w).Encode(map[string]any{"versions": []string{"1.0.0"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "old_rules", version = "1.0.0")
+bazel_dep(name = "fresh_rules", version = "1.0.0")`
+
+	list, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithDeprecatedWarnings(true))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	want := "module old_rules is deprecated: use fresh_rules instead"
+	if len(list.Summary.DeprecationWarnings) != 1 || list.Summary.DeprecationWarnings[0] != want {
+		t.Errorf("Summary.DeprecationWarnings = %v, want [%q]", list.Summary.DeprecationWarnings, want)
+	}
+	if list.Summary.DeprecatedModules != 1 {
+		t.Errorf("Summary.DeprecatedModules = %d, want 1", list.Summary.DeprecatedModules)
+	}
+	if list.Module("fresh_rules").IsDeprecated {
+		t.Error("fresh_rules marked deprecated, want not deprecated")
+	}
+}
+
 func TestBuildAllowedYankedSet(t *testing.T) {
 	t.Run("empty list returns nil", func(t *testing.T) {
 		result := buildAllowedYankedSet(nil)
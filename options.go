@@ -3,8 +3,11 @@ package gobzlmod
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"maps"
 	"net/http"
+	"path"
 	"time"
 )
 
@@ -18,6 +21,8 @@ type resolverConfig struct {
 	checkYanked            bool
 	allowYankedVersions    []string
 	warnDeprecated         bool
+	warnPreOneMinorBumps   bool
+	fetchLicenses          bool
 	traceRegistryFiles     bool
 	directDepsMode         DirectDepsCheckMode
 	substituteYanked       bool
@@ -28,7 +33,22 @@ type resolverConfig struct {
 	lockfileMode           LockfileMode
 	lockfilePath           string
 	timeout                time.Duration
+	softTimeBudget         time.Duration
+	totalTimeout           time.Duration
+	retryAttempts          int
+	retryBaseDelay         time.Duration
+	maxConcurrentFetches   int
+	maxDependencyDepth     int
+	deterministic          bool
+	followIncludes         bool
+	excludeModules         []string
+	seedModules            map[string]*ModuleInfo
+	moduleAliases          map[string]string
+	versionPins            map[string]string
+	moduleRegistries       map[string]string
+	tracer                 Tracer
 	onProgress             func(ProgressEvent)
+	onDependencyDiscovered func(name, version, requester string) (bool, error)
 	httpClient             *http.Client
 	cache                  ModuleCache
 
@@ -94,6 +114,24 @@ func WithDeprecatedWarnings(warn bool) Option {
 	}
 }
 
+// WithPreOneMinorBumpWarnings enables advisory warnings when MVS bumps a
+// requested 0.x version across a minor boundary, e.g. 0.1.0 to 0.2.0.
+func WithPreOneMinorBumpWarnings(warn bool) Option {
+	return func(c *resolverConfig) error {
+		c.warnPreOneMinorBumps = warn
+		return nil
+	}
+}
+
+// WithLicenses populates each resolved module's License from its registry
+// metadata, for license-compliance tooling.
+func WithLicenses() Option {
+	return func(c *resolverConfig) error {
+		c.fetchLicenses = true
+		return nil
+	}
+}
+
 // WithRegistryTrace enables Bazel-style registry tracing.
 //
 // When enabled, resolution records the canonical registry URLs for MODULE.bazel
@@ -151,6 +189,28 @@ func WithRegistries(urls ...string) Option {
 	}
 }
 
+// WithModuleRegistries pins modules to a single registry, e.g.
+// {"corp_*": "https://registry.corp.example.com"}. A pinned module that the
+// registry lacks is an error rather than a fallback to the next registry.
+// See ResolutionOptions.ModuleRegistries.
+func WithModuleRegistries(pins map[string]string) Option {
+	return func(c *resolverConfig) error {
+		for pattern, url := range pins {
+			if pattern == "" || url == "" {
+				return fmt.Errorf("module registry %q -> %q: pattern and URL must not be empty", pattern, url)
+			}
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("module registry pattern %q: %w", pattern, err)
+			}
+		}
+		if c.moduleRegistries == nil {
+			c.moduleRegistries = make(map[string]string, len(pins))
+		}
+		maps.Copy(c.moduleRegistries, pins)
+		return nil
+	}
+}
+
 // WithVendorDir sets the local vendor directory for modules.
 func WithVendorDir(dir string) Option {
 	return func(c *resolverConfig) error {
@@ -190,6 +250,153 @@ func WithTimeout(d time.Duration) Option {
 	}
 }
 
+// WithSoftTimeBudget stops starting new module fetches once d has elapsed and
+// returns a partial resolution instead of failing. See ResolutionOptions.SoftTimeBudget.
+func WithSoftTimeBudget(d time.Duration) Option {
+	return func(c *resolverConfig) error {
+		c.softTimeBudget = d
+		return nil
+	}
+}
+
+// WithTotalTimeout fails resolution with a ResolutionTimeoutError if it takes
+// longer than d in total. See ResolutionOptions.TotalTimeout.
+func WithTotalTimeout(d time.Duration) Option {
+	return func(c *resolverConfig) error {
+		c.totalTimeout = d
+		return nil
+	}
+}
+
+// WithRetry retries registry requests that fail with a 5xx response or a
+// network error, making up to maxAttempts attempts in total with exponential
+// backoff from baseDelay. See ResolutionOptions.RetryAttempts.
+func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return func(c *resolverConfig) error {
+		if baseDelay < 0 {
+			return errors.New("retry base delay must not be negative")
+		}
+		c.retryAttempts = maxAttempts
+		c.retryBaseDelay = baseDelay
+		return nil
+	}
+}
+
+// WithMaxConcurrentFetches caps concurrent MODULE.bazel fetches at n.
+// See ResolutionOptions.MaxConcurrentFetches.
+func WithMaxConcurrentFetches(n int) Option {
+	return func(c *resolverConfig) error {
+		c.maxConcurrentFetches = n
+		return nil
+	}
+}
+
+// WithMaxDependencyDepth sets the longest dependency chain discovery follows.
+// See ResolutionOptions.MaxDependencyDepth.
+func WithMaxDependencyDepth(n int) Option {
+	return func(c *resolverConfig) error {
+		c.maxDependencyDepth = n
+		return nil
+	}
+}
+
+// WithDeterministic makes repeated resolutions of the same inputs produce
+// identical results, for snapshot testing. See ResolutionOptions.Deterministic.
+func WithDeterministic() Option {
+	return func(c *resolverConfig) error {
+		c.deterministic = true
+		return nil
+	}
+}
+
+// WithFollowIncludes merges the files the root MODULE.bazel loads with
+// include() into it. See ResolutionOptions.FollowIncludes.
+func WithFollowIncludes() Option {
+	return func(c *resolverConfig) error {
+		c.followIncludes = true
+		return nil
+	}
+}
+
+// WithExcludeModules resolves as if the named modules did not exist.
+// See ResolutionOptions.ExcludeModules.
+func WithExcludeModules(names ...string) Option {
+	return func(c *resolverConfig) error {
+		c.excludeModules = append(c.excludeModules, names...)
+		return nil
+	}
+}
+
+// WithTracer emits resolution spans to t, e.g. an adapted OpenTelemetry
+// tracer. See ResolutionOptions.Tracer.
+func WithTracer(t Tracer) Option {
+	return func(c *resolverConfig) error {
+		c.tracer = t
+		return nil
+	}
+}
+
+// WithModuleAliases rewrites bazel_deps on an old module name to a new one
+// before fetching, e.g. {"com_google_protobuf": "protobuf"} for a module a
+// registry renamed. See ResolutionOptions.ModuleAliases.
+//
+// Names must be non-empty, and a new name cannot itself be an old name.
+func WithModuleAliases(aliases map[string]string) Option {
+	return func(c *resolverConfig) error {
+		if c.moduleAliases == nil {
+			c.moduleAliases = make(map[string]string, len(aliases))
+		}
+		maps.Copy(c.moduleAliases, aliases)
+		for oldName, newName := range c.moduleAliases {
+			if oldName == "" || newName == "" {
+				return fmt.Errorf("module alias %q -> %q: names must not be empty", oldName, newName)
+			}
+			if oldName == newName {
+				return fmt.Errorf("module alias %q maps to itself", oldName)
+			}
+			if _, chained := c.moduleAliases[newName]; chained {
+				return fmt.Errorf("module alias %q -> %q: %q is itself aliased", oldName, newName, newName)
+			}
+		}
+		return nil
+	}
+}
+
+// WithVersionPins resolves as if the root MODULE.bazel declared a bazel_dep
+// on each name at the given version, e.g. {"rules_go": "0.51.0"} to preview
+// a bump. See ResolutionOptions.VersionPins.
+func WithVersionPins(pins map[string]string) Option {
+	return func(c *resolverConfig) error {
+		for name, version := range pins {
+			if name == "" || version == "" {
+				return fmt.Errorf("version pin %q -> %q: name and version must not be empty", name, version)
+			}
+		}
+		if c.versionPins == nil {
+			c.versionPins = make(map[string]string, len(pins))
+		}
+		maps.Copy(c.versionPins, pins)
+		return nil
+	}
+}
+
+// WithSeedModules supplies already-parsed module infos, keyed by
+// "name@version", that are used instead of fetching those MODULE.bazel files
+// from the registry. See ResolutionOptions.SeedModules.
+func WithSeedModules(modules map[string]*ModuleInfo) Option {
+	return func(c *resolverConfig) error {
+		seeds, err := normalizeSeedModules(modules)
+		if err != nil {
+			return err
+		}
+		if c.seedModules == nil {
+			c.seedModules = make(map[string]*ModuleInfo, len(seeds))
+		}
+		maps.Copy(c.seedModules, seeds)
+		return nil
+	}
+}
+
 // WithProgress sets a callback for resolution progress events.
 func WithProgress(fn func(ProgressEvent)) Option {
 	return func(c *resolverConfig) error {
@@ -198,6 +405,15 @@ func WithProgress(fn func(ProgressEvent)) Option {
 	}
 }
 
+// WithDependencyApproval sets a callback that approves or rejects each module
+// version as it is discovered. See ResolutionOptions.OnDependencyDiscovered.
+func WithDependencyApproval(fn func(name, version, requester string) (bool, error)) Option {
+	return func(c *resolverConfig) error {
+		c.onDependencyDiscovered = fn
+		return nil
+	}
+}
+
 // WithHTTPClient sets a custom HTTP client for registry requests.
 func WithHTTPClient(client *http.Client) Option {
 	return func(c *resolverConfig) error {
@@ -289,17 +505,34 @@ func (c *resolverConfig) toResolutionOptions() ResolutionOptions {
 		CheckYanked:            c.checkYanked,
 		AllowYankedVersions:    c.allowYankedVersions,
 		WarnDeprecated:         c.warnDeprecated,
+		WarnPreOneMinorBumps:   c.warnPreOneMinorBumps,
+		FetchLicenses:          c.fetchLicenses,
 		TraceRegistryFiles:     c.traceRegistryFiles,
 		DirectDepsMode:         c.directDepsMode,
 		SubstituteYanked:       c.substituteYanked,
 		BazelCompatibilityMode: c.bazelCompatibilityMode,
 		BazelVersion:           c.bazelVersion,
 		Registries:             c.registries,
+		ModuleRegistries:       c.moduleRegistries,
 		VendorDir:              c.vendorDir,
 		LockfileMode:           c.lockfileMode,
 		LockfilePath:           c.lockfilePath,
 		Timeout:                c.timeout,
+		SoftTimeBudget:         c.softTimeBudget,
+		TotalTimeout:           c.totalTimeout,
+		RetryAttempts:          c.retryAttempts,
+		RetryBaseDelay:         c.retryBaseDelay,
+		MaxConcurrentFetches:   c.maxConcurrentFetches,
+		MaxDependencyDepth:     c.maxDependencyDepth,
+		Deterministic:          c.deterministic,
+		FollowIncludes:         c.followIncludes,
+		ExcludeModules:         c.excludeModules,
+		SeedModules:            c.seedModules,
+		ModuleAliases:          c.moduleAliases,
+		VersionPins:            c.versionPins,
+		Tracer:                 c.tracer,
 		OnProgress:             c.onProgress,
+		OnDependencyDiscovered: c.onDependencyDiscovered,
 		HTTPClient:             c.httpClient,
 		Cache:                  c.cache,
 		Logger:                 c.logger,
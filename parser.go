@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"slices"
 
 	"github.com/albertocavalcante/go-bzlmod/internal/buildutil"
 	"github.com/albertocavalcante/go-bzlmod/third_party/buildtools/build"
@@ -52,21 +53,38 @@ func parseModule(filename string, content []byte) (*ModuleInfo, error) {
 	if err != nil {
 		return nil, fmt.Errorf("parse %s: %w", filename, err)
 	}
-	info, err := extractModuleInfo(f)
+	info, err := extractModuleInfo(f, false)
 	if err != nil {
 		return nil, err
 	}
+	info.UnprovidedUseRepos = findUnprovidedUseRepos(f)
+	return info, nil
+}
+
+// parseModuleSegment parses a MODULE.bazel segment loaded with include().
+// Segments hold the same directives as MODULE.bazel except module().
+func parseModuleSegment(filename string, content []byte) (*ModuleInfo, error) {
+	f, err := build.ParseModule(filename, content)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filename, err)
+	}
+	info, err := extractModuleInfo(f, true)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	info.UnprovidedUseRepos = findUnprovidedUseRepos(f)
 	return info, nil
 }
 
 // extractModuleInfo extracts module information from parsed BUILD file.
 //
 // This function processes the AST to extract module metadata and dependencies,
-// implementing validation rules from Bazel's ModuleFileGlobals.
+// implementing validation rules from Bazel's ModuleFileGlobals. A segment, a
+// file loaded with include(), must not call module().
 //
 // Reference: ModuleFileGlobals.java
 // See: https://github.com/bazelbuild/bazel/blob/master/src/main/java/com/google/devtools/build/lib/bazel/bzlmod/ModuleFileGlobals.java
-func extractModuleInfo(f *build.File) (*ModuleInfo, error) {
+func extractModuleInfo(f *build.File, segment bool) (*ModuleInfo, error) {
 	info := &ModuleInfo{
 		Dependencies:      []Dependency{},
 		NodepDependencies: []Dependency{},
@@ -78,18 +96,38 @@ func extractModuleInfo(f *build.File) (*ModuleInfo, error) {
 	// Reference: ModuleFileGlobals.java lines 169-171
 	seenOtherDirective := false
 
+	// devProxies records extension proxies created with dev_dependency = True,
+	// so use_repo calls on them can be classified.
+	devProxies := make(map[string]bool)
+
 	for _, stmt := range f.Stmt {
+		if assign, ok := stmt.(*build.AssignExpr); ok {
+			lhs, isIdent := assign.LHS.(*build.Ident)
+			rhs, isCall := assign.RHS.(*build.CallExpr)
+			if isCall {
+				info.Directives = appendDirective(info.Directives, buildutil.FuncName(rhs))
+			}
+			if isIdent && isCall && buildutil.FuncName(rhs) == "use_extension" {
+				devProxies[lhs.Name] = buildutil.Bool(rhs, "dev_dependency")
+			}
+			continue
+		}
+
 		call, ok := stmt.(*build.CallExpr)
 		if !ok {
 			continue
 		}
 
 		funcName := buildutil.FuncName(call)
+		info.Directives = appendDirective(info.Directives, funcName)
 
 		switch funcName {
 		// Reference: ModuleFileGlobals.module() - lines 152-217
 		// See: https://github.com/bazelbuild/bazel/blob/master/src/main/java/com/google/devtools/build/lib/bazel/bzlmod/ModuleFileGlobals.java
 		case "module":
+			if segment {
+				return nil, fmt.Errorf("module() can only be called in the root MODULE.bazel, not in an included file")
+			}
 			// Validation: module() can only be called once
 			// Reference: ModuleFileGlobals.java lines 166-168
 			if foundModule {
@@ -200,11 +238,42 @@ func extractModuleInfo(f *build.File) (*ModuleInfo, error) {
 			override := Override{
 				Type:       "archive",
 				ModuleName: buildutil.String(call, "module_name"),
+				URLs:       buildutil.StringList(call, "urls"),
+			}
+			// urls also accepts a single string.
+			if override.URLs == nil {
+				if url := buildutil.String(call, "urls"); url != "" {
+					override.URLs = []string{url}
+				}
 			}
 			if override.ModuleName != "" {
 				info.Overrides = append(info.Overrides, override)
 			}
 
+		// Reference: ModuleFileGlobals.useRepo()
+		// See: https://github.com/bazelbuild/bazel/blob/master/src/main/java/com/google/devtools/build/lib/bazel/bzlmod/ModuleFileGlobals.java
+		case "use_repo":
+			seenOtherDirective = true
+			if len(call.List) == 0 {
+				continue
+			}
+			repos := useRepoNames(call)
+			if proxy, ok := call.List[0].(*build.Ident); ok && devProxies[proxy.Name] {
+				info.DevExtensionRepos = append(info.DevExtensionRepos, repos...)
+			} else {
+				info.ExtensionRepos = append(info.ExtensionRepos, repos...)
+			}
+
+		// Reference: ModuleFileGlobals.include()
+		// See: https://github.com/bazelbuild/bazel/blob/master/src/main/java/com/google/devtools/build/lib/bazel/bzlmod/ModuleFileGlobals.java
+		case "include":
+			seenOtherDirective = true
+			if lbl := buildutil.String(call, ""); lbl != "" {
+				info.Includes = append(info.Includes, lbl)
+			} else if lbl := buildutil.String(call, "label"); lbl != "" {
+				info.Includes = append(info.Includes, lbl)
+			}
+
 		default:
 			// Other function calls (use_repo_rule, use_extension, etc.) also count
 			// as "other directives" for the module() ordering check
@@ -212,9 +281,36 @@ func extractModuleInfo(f *build.File) (*ModuleInfo, error) {
 		}
 	}
 
-	if !foundModule {
+	if !foundModule && !segment {
 		return nil, fmt.Errorf("no module() declaration found")
 	}
 
 	return info, nil
 }
+
+// appendDirective adds name to directives unless it is empty, as for a call
+// of a method on an extension proxy, or already listed.
+func appendDirective(directives []string, name string) []string {
+	if name == "" || slices.Contains(directives, name) {
+		return directives
+	}
+	return append(directives, name)
+}
+
+// useRepoNames returns the repo names a use_repo call makes visible: each
+// positional string after the extension proxy, and the key of each keyword
+// argument (use_repo(ext, local = "exported") imports "exported" as "local").
+func useRepoNames(call *build.CallExpr) []string {
+	var repos []string
+	for _, arg := range call.List[1:] {
+		switch arg := arg.(type) {
+		case *build.StringExpr:
+			repos = append(repos, arg.Value)
+		case *build.AssignExpr:
+			if lhs, ok := arg.LHS.(*build.Ident); ok {
+				repos = append(repos, lhs.Name)
+			}
+		}
+	}
+	return repos
+}
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -102,6 +104,26 @@ func TestParseModuleContent(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "archive override mirrors",
+			content: `module(name = "test_module", version = "1.0.0")
+			archive_override(
+				module_name = "mirrored",
+				urls = ["https://mirror.example.com/a.zip", "https://example.com/a.zip"],
+			)
+			archive_override(module_name = "single", urls = "https://example.com/b.zip")`,
+			want: &ModuleInfo{
+				Name:              "test_module",
+				Version:           "1.0.0",
+				Dependencies:      []Dependency{},
+				NodepDependencies: []Dependency{},
+				Overrides: []Override{
+					{Type: "archive", ModuleName: "mirrored", URLs: []string{"https://mirror.example.com/a.zip", "https://example.com/a.zip"}},
+					{Type: "archive", ModuleName: "single", URLs: []string{"https://example.com/b.zip"}},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "complex module",
 			content: `module(
@@ -707,3 +729,49 @@ func containsAt(s, substr string) bool {
 	}
 	return false
 }
+
+func TestParseModuleContent_AttributeOrderIndependent(t *testing.T) {
+	// Each ordering is a rotation of the previous one, so every attribute
+	// appears in every position, including ahead of name.
+	moduleAttrs := []string{
+		`name = "my_module"`,
+		`version = "1.2.3"`,
+		`compatibility_level = 2`,
+		`repo_name = "custom_repo"`,
+		`bazel_compatibility = [">=7.0.0"]`,
+	}
+	depAttrs := []string{
+		`name = "rules_go"`,
+		`version = "0.50.1"`,
+		`max_compatibility_level = 1`,
+		`repo_name = "io_bazel_rules_go"`,
+		`dev_dependency = True`,
+	}
+
+	var want *ModuleInfo
+	for i := range moduleAttrs {
+		for j := range depAttrs {
+			content := "module(" + strings.Join(rotate(moduleAttrs, i), ", ") + ")\n" +
+				"bazel_dep(" + strings.Join(rotate(depAttrs, j), ", ") + ")\n"
+			got, err := ParseModuleContent(content)
+			if err != nil {
+				t.Fatalf("ParseModuleContent(%s) error = %v", content, err)
+			}
+			if want == nil {
+				want = got
+				continue
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ParseModuleContent(%s) = %+v, want %+v", content, got, want)
+			}
+		}
+	}
+	if want.Name != "my_module" || len(want.Dependencies) != 1 || !want.Dependencies[0].DevDependency {
+		t.Errorf("ParseModuleContent() = %+v, want module my_module with one dev dependency", want)
+	}
+}
+
+// rotate returns items rotated left by n.
+func rotate(items []string, n int) []string {
+	return append(slices.Clone(items[n:]), items[:n]...)
+}
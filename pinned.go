@@ -0,0 +1,176 @@
+package gobzlmod
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// PinnedModule is the per-module entry emitted by ToPinnedJSON.
+type PinnedModule struct {
+	// Version is the resolved version. Empty for non-registry overrides.
+	Version string `json:"version"`
+
+	// URL is the source archive download URL.
+	URL string `json:"url,omitempty"`
+
+	// SHA256 is the hex-encoded SHA-256 of the archive, as Nix expects it.
+	// Only set when the registry integrity is a sha256 SRI hash.
+	SHA256 string `json:"sha256,omitempty"`
+
+	// Integrity is the original SRI hash when it cannot be expressed as SHA256.
+	Integrity string `json:"integrity,omitempty"`
+
+	// StripPrefix is the directory prefix to strip from the source.
+	StripPrefix string `json:"strip_prefix,omitempty"`
+
+	// Remote is the repository URL for git sources.
+	Remote string `json:"remote,omitempty"`
+
+	// Commit is the pinned commit for git sources.
+	Commit string `json:"commit,omitempty"`
+
+	// Path is the filesystem path for local_path sources.
+	Path string `json:"path,omitempty"`
+}
+
+// ToPinnedJSON emits a flat JSON object mapping each resolved module name to
+// its version, source location and hash, for consumption by Nix-style tooling:
+//
+//	{"rules_go": {"version": "0.50.1", "url": "https://...", "sha256": "..."}}
+//
+// Sources already attached by WithRegistryTrace are reused; for other registry
+// modules source.json is fetched from the module's Registry with bounded
+// concurrency. client, which may be nil, is used for the registries it serves,
+// so its HTTP settings and caches apply; other registries are queried with a
+// default client. Modules without a registry (non-registry overrides) are
+// emitted with their version only, unless a source was attached.
+//
+// The format has one entry per module name, so a list selecting a module at
+// several versions (multiple_version_override) is an error.
+func (r *ResolutionList) ToPinnedJSON(ctx context.Context, client Registry) ([]byte, error) {
+	sources := make([]*SourceInfo, len(r.Modules))
+	registries := make(map[string]Registry)
+	versions := make(map[string]string, len(r.Modules))
+	for _, module := range r.Modules {
+		if v, ok := versions[module.Name]; ok {
+			return nil, fmt.Errorf("pinned JSON has one entry per module, but %s is selected at both %s and %s",
+				module.Name, v, module.Version)
+		}
+		versions[module.Name] = module.Version
+		if module.Source == nil && module.Registry != "" && registries[module.Registry] == nil {
+			registries[module.Registry] = registryServing(client, module.Registry)
+		}
+	}
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, defaultMaxConcurrency)
+
+	for i, module := range r.Modules {
+		if module.Source != nil {
+			sources[i] = module.Source
+			continue
+		}
+		if module.Registry == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, module ModuleToResolve, reg Registry) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errOnce.Do(func() { firstErr = ctx.Err() })
+				return
+			}
+
+			source, err := reg.GetModuleSource(ctx, module.Name, module.Version)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = fmt.Errorf("fetch source for %s@%s: %w", module.Name, module.Version, err)
+				})
+				return
+			}
+			sources[i] = sourceInfoFromRegistry(source)
+		}(i, module, registries[module.Registry])
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	pinned := make(map[string]PinnedModule, len(r.Modules))
+	for i, module := range r.Modules {
+		pinned[module.Name] = pinnedModule(module.Version, sources[i])
+	}
+	return json.MarshalIndent(pinned, "", "  ")
+}
+
+// registryServing returns the registry of reg whose base URL is url, or a
+// new registry for url if reg has none.
+func registryServing(reg Registry, url string) Registry {
+	switch r := reg.(type) {
+	case nil:
+	case *registryChain:
+		for _, client := range r.clients {
+			if client.BaseURL() == url {
+				return client
+			}
+		}
+	default:
+		if r.BaseURL() == url {
+			return r
+		}
+	}
+	return registryWithAllOptions(nil, nil, 0, nil, url)
+}
+
+func pinnedModule(version string, source *SourceInfo) PinnedModule {
+	entry := PinnedModule{Version: version}
+	if source == nil {
+		return entry
+	}
+
+	entry.StripPrefix = source.StripPrefix
+	switch source.Type {
+	case "git_repository":
+		entry.Remote = source.Remote
+		entry.Commit = source.Commit
+	case "local_path":
+		entry.Path = source.Path
+	default:
+		entry.URL = source.URL
+		if hash, ok := sriToHexSHA256(source.Integrity); ok {
+			entry.SHA256 = hash
+		} else {
+			entry.Integrity = source.Integrity
+		}
+	}
+	return entry
+}
+
+// sriToHexSHA256 converts a "sha256-<base64>" SRI hash to hex.
+// Returns false for other algorithms or malformed input.
+func sriToHexSHA256(integrity string) (string, bool) {
+	encoded, ok := strings.CutPrefix(integrity, "sha256-")
+	if !ok {
+		return "", false
+	}
+	digest, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(digest) != 32 {
+		return "", false
+	}
+	return hex.EncodeToString(digest), true
+}
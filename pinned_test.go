@@ -0,0 +1,163 @@
+package gobzlmod
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestResolutionList_ToPinnedJSON(t *testing.T) {
+	digest := sha256.Sum256([]byte("archive"))
+	sri := "sha256-" + base64.StdEncoding.EncodeToString(digest[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/rules_go/0.50.1/source.json":
+			fmt.Fprintf(w, `{"url": "https://example.com/rules_go.zip", "integrity": %q, "strip_prefix": "rules_go-0.50.1"}`, sri)
+		case "/modules/protobuf/29.0/source.json":
+			fmt.Fprint(w, `{"url": "https://example.com/protobuf.zip", "integrity": "sha512-AAAA"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// gazelle comes from a second registry that client does not serve.
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/modules/gazelle/0.40.0/source.json" {
+			fmt.Fprint(w, `{"type": "git_repository", "remote": "https://github.com/bazelbuild/bazel-gazelle.git", "commit": "abc123"}`)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer other.Close()
+
+	list := &ResolutionList{
+		Modules: []ModuleToResolve{
+			{Name: "gazelle", Version: "0.40.0", Registry: other.URL},
+			{Name: "local_dep", Version: "", Source: &SourceInfo{Type: "local_path", Path: "third_party/local_dep"}},
+			{Name: "protobuf", Version: "29.0", Registry: server.URL},
+			{Name: "rules_go", Version: "0.50.1", Registry: server.URL},
+		},
+	}
+
+	data, err := list.ToPinnedJSON(context.Background(), newRegistryClient(server.URL))
+	if err != nil {
+		t.Fatalf("ToPinnedJSON() error = %v", err)
+	}
+
+	var got map[string]map[string]string
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal pinned JSON: %v\n%s", err, data)
+	}
+
+	want := map[string]map[string]string{
+		"gazelle": {
+			"version": "0.40.0",
+			"remote":  "https://github.com/bazelbuild/bazel-gazelle.git",
+			"commit":  "abc123",
+		},
+		"local_dep": {
+			"version": "",
+			"path":    "third_party/local_dep",
+		},
+		"protobuf": {
+			"version":   "29.0",
+			"url":       "https://example.com/protobuf.zip",
+			"integrity": "sha512-AAAA",
+		},
+		"rules_go": {
+			"version":      "0.50.1",
+			"url":          "https://example.com/rules_go.zip",
+			"sha256":       hex.EncodeToString(digest[:]),
+			"strip_prefix": "rules_go-0.50.1",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToPinnedJSON() =\n%s\nwant %v", data, want)
+	}
+
+	// Without a client, every module's own registry is queried.
+	withoutClient, err := list.ToPinnedJSON(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ToPinnedJSON(nil) error = %v", err)
+	}
+	if string(withoutClient) != string(data) {
+		t.Errorf("ToPinnedJSON(nil) =\n%s\nwant\n%s", withoutClient, data)
+	}
+}
+
+func TestResolutionList_ToPinnedJSON_FetchError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	list := &ResolutionList{
+		Modules: []ModuleToResolve{{Name: "a", Version: "1.0.0", Registry: server.URL}},
+	}
+	if _, err := list.ToPinnedJSON(context.Background(), newRegistryClient(server.URL)); err == nil {
+		t.Error("ToPinnedJSON() expected error when source.json cannot be fetched")
+	}
+}
+
+func TestResolutionList_ToPinnedJSON_MultipleVersions(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		fmt.Fprint(w, `{"url": "https://example.com/lib.zip", "integrity": "sha512-AAAA"}`)
+	}))
+	defer server.Close()
+
+	// A multiple_version_override selects lib twice; one entry would
+	// silently overwrite the other.
+	list := &ResolutionList{
+		Modules: []ModuleToResolve{
+			{Name: "lib", Version: "1.0.0", Registry: server.URL},
+			{Name: "lib", Version: "2.0.0", Registry: server.URL},
+		},
+	}
+	_, err := list.ToPinnedJSON(context.Background(), newRegistryClient(server.URL))
+	if err == nil {
+		t.Fatal("ToPinnedJSON() expected error for a module selected at two versions")
+	}
+	if !strings.Contains(err.Error(), "lib is selected at both 1.0.0 and 2.0.0") {
+		t.Errorf("ToPinnedJSON() error = %v, want it to name lib and both versions", err)
+	}
+	if got := fetches.Load(); got != 0 {
+		t.Errorf("source.json fetched %d times, want 0", got)
+	}
+}
+
+func TestSRIToHexSHA256(t *testing.T) {
+	digest := sha256.Sum256([]byte("x"))
+	tests := []struct {
+		name      string
+		integrity string
+		want      string
+		wantOK    bool
+	}{
+		{"sha256", "sha256-" + base64.StdEncoding.EncodeToString(digest[:]), hex.EncodeToString(digest[:]), true},
+		{"sha512", "sha512-AAAA", "", false},
+		{"bad base64", "sha256-!!!", "", false},
+		{"wrong length", "sha256-AAAA", "", false},
+		{"empty", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := sriToHexSHA256(tt.integrity)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("sriToHexSHA256(%q) = %q, %v; want %q, %v", tt.integrity, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
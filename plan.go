@@ -0,0 +1,212 @@
+package gobzlmod
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/albertocavalcante/go-bzlmod/lockfile"
+)
+
+// Kinds of registry request reported in PlannedRequest.Kind.
+const (
+	// PlannedRegistryConfig is a fetch of a registry's bazel_registry.json.
+	PlannedRegistryConfig = "registry_config"
+
+	// PlannedModuleFile is a fetch of a module version's MODULE.bazel.
+	PlannedModuleFile = "module_file"
+
+	// PlannedMetadata is a fetch of a module's metadata.json, made when
+	// yanked or deprecated checks or license collection are enabled.
+	PlannedMetadata = "metadata"
+)
+
+// planTransitiveNote is attached to every ResolvePlan.
+const planTransitiveNote = "transitive dependencies are only known after fetching the listed " +
+	"MODULE.bazel files; a full resolution makes at least these requests, plus one module file " +
+	"(and metadata file, if enabled) per transitive module version"
+
+// PlannedRequest is a registry request that resolution is known to need.
+type PlannedRequest struct {
+	// Kind is one of the Planned* constants.
+	Kind string `json:"kind"`
+
+	// Module is the module the request is for. Empty for registry config.
+	Module string `json:"module,omitempty"`
+
+	// Version is the module version. Empty for registry config and metadata.
+	Version string `json:"version,omitempty"`
+
+	// URL is the URL that would be requested first. Registries later in the
+	// chain and mirrors are only tried if it fails.
+	URL string `json:"url"`
+
+	// Offline names what satisfies the request without network access:
+	// "vendor", "cache" or "local" (a file:// registry). Empty if the request
+	// would go over the network.
+	Offline string `json:"offline,omitempty"`
+
+	// Locked is true if the lockfile records a hash for URL, i.e. a previous
+	// resolution already fetched it.
+	Locked bool `json:"locked,omitempty"`
+}
+
+// ResolvePlan is a static estimate of the registry requests a resolution makes.
+type ResolvePlan struct {
+	// Requests lists the requests known from the root module alone: registry
+	// configuration, and the module files and metadata of direct dependencies.
+	Requests []PlannedRequest `json:"requests"`
+
+	// Note explains what the plan cannot know without network access.
+	Note string `json:"note"`
+}
+
+// NetworkRequests returns the requests that are not satisfied offline.
+func (p *ResolvePlan) NetworkRequests() []PlannedRequest {
+	var network []PlannedRequest
+	for _, req := range p.Requests {
+		if req.Offline == "" {
+			network = append(network, req)
+		}
+	}
+	return network
+}
+
+// PlanResolve reports, without making any network requests, which registry
+// requests Resolve would make for the given MODULE.bazel content.
+//
+// Only the root module's direct dependencies can be planned, since transitive
+// dependencies are declared in MODULE.bazel files that are not yet fetched, so
+// the plan is a lower bound. It takes the same options as Resolve: dev
+// dependencies, Bazel's MODULE.tools dependencies, single_version_override
+// pins and registries are applied as resolution would. Requests satisfied by
+// WithVendorDir or WithCache are marked Offline, and when WithLockfilePath
+// names an existing lockfile, requests it records are marked Locked.
+//
+// This is useful for estimating what an offline cache needs to be prefetched.
+func PlanResolve(content string, opts ...Option) (*ResolvePlan, error) {
+	cfg, err := newResolverConfig(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+	resOpts := cfg.toResolutionOptions()
+
+	root, err := ParseModuleContent(content)
+	if err != nil {
+		return nil, fmt.Errorf("parse module content: %w", err)
+	}
+	if resOpts.BazelVersion != "" {
+		injectBazelToolsDeps(root, resOpts.BazelVersion)
+	}
+
+	var lock *lockfile.Lockfile
+	if resOpts.LockfilePath != "" && lockfile.Exists(resOpts.LockfilePath) {
+		lock, err = lockfile.ReadFile(resOpts.LockfilePath)
+		if err != nil {
+			return nil, fmt.Errorf("read lockfile: %w", err)
+		}
+	}
+
+	registries := resOpts.Registries
+	if len(registries) == 0 {
+		registries = DefaultRegistries
+	}
+	overrides := indexOverrides(root.Overrides)
+	fetchMetadata := resOpts.CheckYanked || resOpts.WarnDeprecated || resOpts.FetchLicenses
+
+	// The plan is computed without a real context; the cache is the only
+	// collaborator that receives one.
+	ctx := context.Background()
+
+	plan := &ResolvePlan{Note: planTransitiveNote}
+	configPlanned := make(map[string]bool)
+	seen := make(map[string]bool)
+
+	add := func(req PlannedRequest) {
+		if lock != nil {
+			req.Locked = lock.GetRegistryHash(req.URL) != ""
+		}
+		plan.Requests = append(plan.Requests, req)
+	}
+
+	for _, dep := range root.Dependencies {
+		if dep.DevDependency && !resOpts.IncludeDevDeps {
+			continue
+		}
+
+		version := dep.Version
+		registryURL := strings.TrimRight(registries[0], "/")
+		if override, ok := overrides[dep.Name]; ok {
+			if isNonRegistryOverride(override) {
+				continue
+			}
+			if override.Version != "" {
+				version = override.Version
+			}
+			if override.Registry != "" {
+				registryURL = strings.TrimRight(override.Registry, "/")
+			}
+		}
+		if version == "" || seen[dep.Name] {
+			continue
+		}
+		seen[dep.Name] = true
+
+		offline := ""
+		switch {
+		case isFileURL(registryURL):
+			offline = "local"
+		case resOpts.VendorDir != "" && vendoredModuleExists(resOpts.VendorDir, dep.Name, version):
+			offline = "vendor"
+		case resOpts.Cache != nil && cacheHas(ctx, resOpts.Cache, dep.Name, version):
+			offline = "cache"
+		}
+
+		if offline == "" && !configPlanned[registryURL] {
+			configPlanned[registryURL] = true
+			add(PlannedRequest{
+				Kind: PlannedRegistryConfig,
+				URL:  registryURL + "/bazel_registry.json",
+			})
+		}
+		add(PlannedRequest{
+			Kind:    PlannedModuleFile,
+			Module:  dep.Name,
+			Version: version,
+			URL:     fmt.Sprintf("%s/modules/%s/%s/MODULE.bazel", registryURL, dep.Name, version),
+			Offline: offline,
+		})
+		if fetchMetadata {
+			metadataOffline := ""
+			if isFileURL(registryURL) {
+				metadataOffline = "local"
+			} else if offline == "vendor" {
+				metadataOffline = "vendor"
+			}
+			add(PlannedRequest{
+				Kind:    PlannedMetadata,
+				Module:  dep.Name,
+				URL:     fmt.Sprintf("%s/modules/%s/metadata.json", registryURL, dep.Name),
+				Offline: metadataOffline,
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// vendoredModuleExists reports whether a vendor directory holds the
+// MODULE.bazel of name@version.
+func vendoredModuleExists(vendorDir, name, version string) bool {
+	_, err := os.Stat(filepath.Join(vendorDir, "modules", name, version, "MODULE.bazel"))
+	return err == nil
+}
+
+// cacheHas reports whether cache holds name@version. Cache errors count as
+// misses, as they do during resolution.
+func cacheHas(ctx context.Context, cache ModuleCache, name, version string) bool {
+	_, found, err := cache.Get(ctx, name, version)
+	return err == nil && found
+}
@@ -0,0 +1,96 @@
+package gobzlmod
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPlanResolve(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "rules_go", version = "0.50.1")
+bazel_dep(name = "gazelle", version = "0.38.0")
+bazel_dep(name = "local_lib", version = "1.0.0")
+bazel_dep(name = "testing_lib", version = "2.0.0", dev_dependency = True)
+
+single_version_override(module_name = "gazelle", version = "0.39.0")
+local_path_override(module_name = "local_lib", path = "../local_lib")`
+
+	cache := NewMemoryCache()
+	if err := cache.Put(context.Background(), "rules_go", "0.50.1", []byte(`module(name = "rules_go")`)); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := PlanResolve(content, WithRegistries(server.URL), WithCache(cache))
+	if err != nil {
+		t.Fatalf("PlanResolve() error = %v", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("PlanResolve() made %d requests, want 0", n)
+	}
+
+	want := []PlannedRequest{
+		{Kind: PlannedModuleFile, Module: "rules_go", Version: "0.50.1",
+			URL: server.URL + "/modules/rules_go/0.50.1/MODULE.bazel", Offline: "cache"},
+		{Kind: PlannedRegistryConfig, URL: server.URL + "/bazel_registry.json"},
+		{Kind: PlannedModuleFile, Module: "gazelle", Version: "0.39.0",
+			URL: server.URL + "/modules/gazelle/0.39.0/MODULE.bazel"},
+	}
+	if len(plan.Requests) != len(want) {
+		t.Fatalf("Requests = %+v, want %+v", plan.Requests, want)
+	}
+	for i := range want {
+		if plan.Requests[i] != want[i] {
+			t.Errorf("Requests[%d] = %+v, want %+v", i, plan.Requests[i], want[i])
+		}
+	}
+	if got := plan.NetworkRequests(); len(got) != 2 {
+		t.Errorf("NetworkRequests() = %+v, want registry config and gazelle", got)
+	}
+	if !strings.Contains(plan.Note, "transitive") {
+		t.Errorf("Note = %q, want mention of transitive expansion", plan.Note)
+	}
+}
+
+func TestPlanResolve_DevDepsAndMetadata(t *testing.T) {
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "testing_lib", version = "2.0.0", dev_dependency = True)`
+
+	plan, err := PlanResolve(content,
+		WithRegistries("https://registry.example.com/"),
+		WithDevDeps(),
+		WithYankedCheck(true),
+	)
+	if err != nil {
+		t.Fatalf("PlanResolve() error = %v", err)
+	}
+
+	var urls []string
+	for _, req := range plan.Requests {
+		urls = append(urls, req.URL)
+	}
+	want := []string{
+		"https://registry.example.com/bazel_registry.json",
+		"https://registry.example.com/modules/testing_lib/2.0.0/MODULE.bazel",
+		"https://registry.example.com/modules/testing_lib/metadata.json",
+	}
+	if strings.Join(urls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("URLs = %v, want %v", urls, want)
+	}
+}
+
+func TestPlanResolve_InvalidContent(t *testing.T) {
+	if _, err := PlanResolve(`bazel_dep(name = `); err == nil {
+		t.Error("PlanResolve() with invalid content: expected error")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math/rand/v2"
 	"net/http"
 	"strings"
 	"sync"
@@ -49,16 +50,26 @@ type RegistryError struct {
 	Version    string
 	URL        string
 	Retryable  bool
+
+	// Retries is how many times the request was retried before giving up.
+	// Always 0 unless ResolutionOptions.RetryAttempts is set.
+	Retries int
 }
 
 func (e *RegistryError) Error() string {
-	if e.ModuleName != "" && e.Version != "" {
-		return fmt.Sprintf("registry returned status %d for module %s@%s", e.StatusCode, e.ModuleName, e.Version)
+	var msg string
+	switch {
+	case e.ModuleName != "" && e.Version != "":
+		msg = fmt.Sprintf("registry returned status %d for module %s@%s", e.StatusCode, e.ModuleName, e.Version)
+	case e.URL != "":
+		msg = fmt.Sprintf("registry returned status %d for %s", e.StatusCode, e.URL)
+	default:
+		msg = fmt.Sprintf("registry returned status %d", e.StatusCode)
 	}
-	if e.URL != "" {
-		return fmt.Sprintf("registry returned status %d for %s", e.StatusCode, e.URL)
+	if e.Retries > 0 {
+		msg += fmt.Sprintf(" (after %d retries)", e.Retries)
 	}
-	return fmt.Sprintf("registry returned status %d", e.StatusCode)
+	return msg
 }
 
 // Is implements errors.Is by mapping HTTP status codes to sentinel errors.
@@ -96,6 +107,7 @@ type registryClient struct {
 	externalCache ModuleCache // optional external cache for persistence across resolutions
 	logger        *slog.Logger
 	trace         *registryFileTrace
+	retry         retryPolicy
 
 	// Mirror configuration (fetched lazily from bazel_registry.json)
 	mirrors        []string
@@ -171,7 +183,7 @@ func (r *registryClient) loadMirrors(ctx context.Context) {
 
 		r.trace.record(url, data)
 
-		moduleBasePath := config.ModuleBasePath
+		moduleBasePath := strings.Trim(config.ModuleBasePath, "/")
 		if moduleBasePath == "" {
 			moduleBasePath = "modules"
 		}
@@ -185,6 +197,29 @@ func (r *registryClient) loadMirrors(ctx context.Context) {
 	})
 }
 
+// probe requests the registry's bazel_registry.json. Any answer other than
+// a server error counts as reachable, since the file is optional.
+func (r *registryClient) probe(ctx context.Context) error {
+	url := r.baseURL + "/bazel_registry.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return &RegistryError{
+			StatusCode: resp.StatusCode,
+			URL:        url,
+			Retryable:  resp.StatusCode == 503 || resp.StatusCode == 504,
+		}
+	}
+	return nil
+}
+
 // getModuleBasePath returns the module base path (defaults to "modules").
 func (r *registryClient) getModuleBasePath(ctx context.Context) string {
 	r.loadMirrors(ctx)
@@ -218,7 +253,7 @@ func (r *registryClient) fetchWithMirrors(ctx context.Context, path, moduleName,
 	// Build list of URLs to try: primary first, then mirrors
 	urls := []string{fmt.Sprintf("%s/%s", r.baseURL, path)}
 	for _, mirror := range r.getMirrors(ctx) {
-		urls = append(urls, fmt.Sprintf("%s/%s", strings.TrimSuffix(mirror, "/"), path))
+		urls = append(urls, fmt.Sprintf("%s/%s", strings.TrimRight(mirror, "/"), path))
 	}
 
 	var lastErr error
@@ -229,16 +264,14 @@ func (r *registryClient) fetchWithMirrors(ctx context.Context, path, moduleName,
 			logger.Debug("fetching from registry", "url", url)
 		}
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+		resp, retries, err := r.getWithRetry(ctx, url)
 		if err != nil {
-			lastErr = err
-			continue
-		}
-
-		resp, err := r.client.Do(req)
-		if err != nil {
-			logger.Debug("request failed", "url", url, "error", err)
-			lastErr = fmt.Errorf("fetch %s@%s from %s: %w", moduleName, version, url, err)
+			logger.Debug("request failed", "url", url, "error", err, "retries", retries)
+			if retries > 0 {
+				lastErr = fmt.Errorf("fetch %s@%s from %s after %d retries: %w", moduleName, version, url, retries, err)
+			} else {
+				lastErr = fmt.Errorf("fetch %s@%s from %s: %w", moduleName, version, url, err)
+			}
 			continue
 		}
 
@@ -251,6 +284,7 @@ func (r *registryClient) fetchWithMirrors(ctx context.Context, path, moduleName,
 				Version:    version,
 				URL:        url,
 				Retryable:  resp.StatusCode == 429 || resp.StatusCode == 503 || resp.StatusCode == 504,
+				Retries:    retries,
 			}
 			// Don't try mirrors for 404 - the module doesn't exist
 			if resp.StatusCode == 404 {
@@ -275,6 +309,73 @@ func (r *registryClient) fetchWithMirrors(ctx context.Context, path, moduleName,
 	return nil, lastErr
 }
 
+// retryPolicy configures how registryClient retries transient failures.
+// The zero value disables retries.
+type retryPolicy struct {
+	attempts  int
+	baseDelay time.Duration
+}
+
+// delay returns how long to wait after the given failed attempt: exponential
+// backoff from the base delay, with the upper half jittered so concurrent
+// fetches do not retry in lockstep.
+func (p retryPolicy) delay(attempt int) time.Duration {
+	delay := p.baseDelay << min(attempt-1, 30)
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// withRetry sets the retry policy of every registryClient in reg and returns
+// reg. Other registries, such as local ones, are left as they are.
+func withRetry(reg Registry, attempts int, baseDelay time.Duration) Registry {
+	switch r := reg.(type) {
+	case *registryClient:
+		r.retry = retryPolicy{attempts: attempts, baseDelay: baseDelay}
+	case *registryChain:
+		for _, client := range r.clients {
+			withRetry(client, attempts, baseDelay)
+		}
+	}
+	return reg
+}
+
+// getWithRetry performs a GET of url, retrying 5xx responses and network
+// errors according to r.retry. It returns the final response, whose body the
+// caller must close, and how many retries were performed.
+func (r *registryClient) getWithRetry(ctx context.Context, url string) (*http.Response, int, error) {
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+		if err != nil {
+			return nil, attempt - 1, err
+		}
+		resp, err := r.client.Do(req)
+
+		transient := ctx.Err() == nil && (err != nil || resp.StatusCode >= http.StatusInternalServerError)
+		if !transient || attempt >= r.retry.attempts {
+			return resp, attempt - 1, err
+		}
+		delay := r.retry.delay(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, attempt - 1, err
+		}
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+
+		r.log().Debug("retrying registry request", "url", url, "attempt", attempt, "delay", delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, attempt - 1, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
 // RegistryOption configures a Registry.
 type RegistryOption func(*registryConfig)
 
@@ -520,7 +621,7 @@ func newRegistryClientWithAllOptionsAndTrace(baseURL string, client *http.Client
 	}
 
 	return &registryClient{
-		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		baseURL:       strings.TrimRight(baseURL, "/"),
 		client:        client,
 		externalCache: cache,
 		logger:        logger,
@@ -541,6 +642,7 @@ func (r *registryClient) GetModuleFile(ctx context.Context, moduleName, version
 	// 1. Check in-memory cache first (fastest)
 	if cached, ok := r.cache.Load(cacheKey); ok {
 		logger.Debug("module cache hit (memory)", "name", moduleName, "version", version)
+		noteCacheHit(ctx)
 		return cached.(*ModuleInfo), nil
 	}
 
@@ -559,6 +661,7 @@ func (r *registryClient) GetModuleFile(ctx context.Context, moduleName, version
 			moduleInfo, err := ParseModuleContent(string(data))
 			if err == nil {
 				logger.Debug("module cache hit (external)", "name", moduleName, "version", version)
+				noteCacheHit(ctx)
 				// Store in in-memory cache for faster subsequent access
 				r.cache.Store(cacheKey, moduleInfo)
 				r.trace.record(url, data)
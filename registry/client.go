@@ -1,14 +1,25 @@
 package registry
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"math/rand/v2"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/albertocavalcante/go-bzlmod/label"
 )
 
 // Client configuration defaults.
@@ -17,6 +28,7 @@ const (
 	DefaultMaxIdleConnsPerHost = 20
 	DefaultIdleConnTimeout     = 90 * time.Second
 	DefaultRequestTimeout      = 15 * time.Second
+	DefaultPrefetchConcurrency = 8
 )
 
 // Client fetches and validates data from a Bazel module registry.
@@ -28,11 +40,31 @@ type Client struct {
 	// Cache for metadata and source files
 	metadataCache sync.Map // map[string]*Metadata keyed by module name
 	sourceCache   sync.Map // map[string]*Source keyed by "name@version"
+	attestCache   sync.Map // map[string]*Attestations keyed by "name@version"
+	moduleCache   sync.Map // map[string][]byte keyed by "name@version"
 
 	// Options
-	validateResponses bool
+	validateResponses   bool
+	prefetchConcurrency int
+	retryAttempts       int
+	retryBaseDelay      time.Duration
+
+	// offlineDir, if set, is a local registry mirror read instead of baseURL.
+	offlineDir string
 }
 
+// ErrOffline is returned in offline mode when a registry file is not present
+// in the local mirror. Use errors.Is to detect it.
+var ErrOffline = errors.New("registry file not available offline")
+
+// ErrNoAttestations is returned by GetAttestations when the registry has no
+// attestations.json for a module version. Use errors.Is to detect it.
+var ErrNoAttestations = errors.New("no attestations published")
+
+// ErrListingUnsupported is returned by ListModules when the registry does not
+// serve an index of its modules. Use errors.Is to detect it.
+var ErrListingUnsupported = errors.New("registry does not support module listing")
+
 // ClientOption configures a Client.
 type ClientOption func(*Client)
 
@@ -63,6 +95,44 @@ func WithTimeout(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithOfflineMode makes the client read registry files exclusively from dir,
+// a local directory with the registry layout (see the package documentation),
+// instead of the network. A file missing from dir fails with an error wrapping
+// ErrOffline, so hermetic builds fail fast rather than reaching the registry.
+// Responses are parsed, validated and cached exactly as fetched ones are.
+func WithOfflineMode(dir string) ClientOption {
+	return func(c *Client) {
+		c.offlineDir = dir
+	}
+}
+
+// WithRetry retries registry requests that fail with a 5xx response or a
+// network error, making up to maxAttempts attempts in total. The wait after
+// the n-th failed attempt is baseDelay * 2^(n-1), jittered, and retrying stops
+// early when the context would expire before the next attempt. 4xx responses
+// are never retried. An error returned after retrying wraps a *RetryError
+// with the number of retries performed.
+//
+// maxAttempts below 2 disables retries (the default).
+func WithRetry(maxAttempts int, baseDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.retryAttempts = max(maxAttempts, 1)
+		c.retryBaseDelay = max(baseDelay, 0)
+	}
+}
+
+// WithPrefetchConcurrency sets how many files PrefetchModule fetches at once.
+// Zero or negative values fall back to DefaultPrefetchConcurrency.
+func WithPrefetchConcurrency(n int) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.prefetchConcurrency = n
+		} else {
+			c.prefetchConcurrency = DefaultPrefetchConcurrency
+		}
+	}
+}
+
 // NewClient creates a client for the given registry URL.
 //
 // By default, responses are validated against BCR JSON schemas.
@@ -76,13 +146,15 @@ func NewClient(baseURL string, opts ...ClientOption) *Client {
 	}
 
 	c := &Client{
-		baseURL: strings.TrimSuffix(baseURL, "/"),
+		baseURL: strings.TrimRight(baseURL, "/"),
 		client: &http.Client{
 			Timeout:   DefaultRequestTimeout,
 			Transport: transport,
 		},
-		validator:         NewValidator(),
-		validateResponses: true,
+		validator:           NewValidator(),
+		validateResponses:   true,
+		prefetchConcurrency: DefaultPrefetchConcurrency,
+		retryAttempts:       1,
 	}
 
 	for _, opt := range opts {
@@ -104,8 +176,7 @@ func (c *Client) GetMetadata(ctx context.Context, moduleName string) (*Metadata,
 		return cached.(*Metadata), nil
 	}
 
-	url := fmt.Sprintf("%s/modules/%s/metadata.json", c.baseURL, moduleName)
-	data, err := c.fetch(ctx, url)
+	data, err := c.get(ctx, fmt.Sprintf("modules/%s/metadata.json", moduleName))
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch metadata for %s: %w", moduleName, err)
 	}
@@ -133,8 +204,7 @@ func (c *Client) GetSource(ctx context.Context, moduleName, version string) (*So
 		return cached.(*Source), nil
 	}
 
-	url := fmt.Sprintf("%s/modules/%s/%s/source.json", c.baseURL, moduleName, version)
-	data, err := c.fetch(ctx, url)
+	data, err := c.get(ctx, fmt.Sprintf("modules/%s/%s/source.json", moduleName, version))
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch source for %s@%s: %w", moduleName, version, err)
 	}
@@ -154,20 +224,120 @@ func (c *Client) GetSource(ctx context.Context, moduleName, version string) (*So
 	return &source, nil
 }
 
+// GetAttestations fetches and parses a module version's attestations.json.
+// Results are cached by "name@version".
+//
+// Many modules do not publish attestations yet; for those the returned error
+// wraps ErrNoAttestations.
+func (c *Client) GetAttestations(ctx context.Context, moduleName, version string) (*Attestations, error) {
+	cacheKey := moduleName + "@" + version
+	if cached, ok := c.attestCache.Load(cacheKey); ok {
+		return cached.(*Attestations), nil
+	}
+
+	data, err := c.get(ctx, fmt.Sprintf("modules/%s/%s/attestations.json", moduleName, version))
+	if err != nil {
+		var status *statusError
+		if (errors.As(err, &status) && status.code == http.StatusNotFound) || errors.Is(err, ErrOffline) {
+			return nil, fmt.Errorf("%w for %s@%s: %w", ErrNoAttestations, moduleName, version, err)
+		}
+		return nil, fmt.Errorf("failed to fetch attestations for %s@%s: %w", moduleName, version, err)
+	}
+
+	if c.validateResponses {
+		if err := c.validator.ValidateAttestations(data); err != nil {
+			return nil, fmt.Errorf("attestations validation failed for %s@%s: %w", moduleName, version, err)
+		}
+	}
+
+	var attestations Attestations
+	if err := json.Unmarshal(data, &attestations); err != nil {
+		return nil, fmt.Errorf("failed to parse attestations for %s@%s: %w", moduleName, version, err)
+	}
+
+	c.attestCache.Store(cacheKey, &attestations)
+	return &attestations, nil
+}
+
 // GetModuleFile fetches the raw MODULE.bazel content for a module version.
+// Results are cached by "name@version"; each call returns its own copy.
 func (c *Client) GetModuleFile(ctx context.Context, moduleName, version string) ([]byte, error) {
-	url := fmt.Sprintf("%s/modules/%s/%s/MODULE.bazel", c.baseURL, moduleName, version)
-	return c.fetch(ctx, url)
+	cacheKey := moduleName + "@" + version
+	if cached, ok := c.moduleCache.Load(cacheKey); ok {
+		return bytes.Clone(cached.([]byte)), nil
+	}
+
+	data, err := c.get(ctx, fmt.Sprintf("modules/%s/%s/MODULE.bazel", moduleName, version))
+	if err != nil {
+		return nil, err
+	}
+
+	c.moduleCache.Store(cacheKey, data)
+	return bytes.Clone(data), nil
+}
+
+// PrefetchModule concurrently fetches the MODULE.bazel and source.json of the
+// given versions of a module into the client's caches, so later GetModuleFile
+// and GetSource calls for them return without a network round trip. At most
+// WithPrefetchConcurrency files are fetched at once.
+//
+// Every file is attempted until ctx is done; the returned error joins the
+// failures in version order, with MODULE.bazel before source.json. Files that
+// were fetched successfully stay cached either way.
+func (c *Client) PrefetchModule(ctx context.Context, name string, versions []string) error {
+	errs := make([]error, 2*len(versions))
+	sem := make(chan struct{}, max(c.prefetchConcurrency, 1))
+	var wg sync.WaitGroup
+	var ctxErr error
+
+launch:
+	for i, version := range versions {
+		tasks := []func() error{
+			func() error {
+				_, err := c.GetModuleFile(ctx, name, version)
+				if err != nil {
+					return fmt.Errorf("failed to fetch MODULE.bazel for %s@%s: %w", name, version, err)
+				}
+				return nil
+			},
+			func() error {
+				_, err := c.GetSource(ctx, name, version)
+				return err
+			},
+		}
+		for j, task := range tasks {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				ctxErr = ctx.Err()
+				break launch
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				errs[2*i+j] = task()
+			}()
+		}
+	}
+
+	wg.Wait()
+	return errors.Join(append(errs, ctxErr)...)
 }
 
 // GetRegistryConfig fetches the registry's bazel_registry.json configuration.
 func (c *Client) GetRegistryConfig(ctx context.Context) (*RegistryConfig, error) {
-	url := fmt.Sprintf("%s/bazel_registry.json", c.baseURL)
-	data, err := c.fetch(ctx, url)
+	data, err := c.get(ctx, "bazel_registry.json")
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch registry config: %w", err)
 	}
 
+	if c.validateResponses {
+		if err := c.validator.ValidateRegistryConfig(data); err != nil {
+			return nil, fmt.Errorf("registry config validation failed: %w", err)
+		}
+	}
+
 	var config RegistryConfig
 	if err := json.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse registry config: %w", err)
@@ -176,14 +346,145 @@ func (c *Client) GetRegistryConfig(ctx context.Context) (*RegistryConfig, error)
 	return &config, nil
 }
 
+// ListModules returns the names of the modules the registry offers, sorted.
+// Registries have no standard listing, so this relies on the modules
+// directory serving an index: either a JSON array of names or an HTML
+// directory listing, as file servers generate. In offline mode the modules
+// directory of the mirror is read. A registry without a usable index fails
+// with an error wrapping ErrListingUnsupported.
+func (c *Client) ListModules(ctx context.Context) ([]string, error) {
+	if c.offlineDir != "" {
+		return c.listOfflineModules(ctx)
+	}
+
+	data, err := c.fetch(ctx, c.baseURL+"/modules/")
+	if err != nil {
+		var status *statusError
+		if errors.As(err, &status) && status.code == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %w", ErrListingUnsupported, err)
+		}
+		return nil, fmt.Errorf("failed to list modules: %w", err)
+	}
+
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		names = directoryListingNames(data)
+	}
+	names = validModuleNames(names)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: %s/modules/ is not a module index", ErrListingUnsupported, c.baseURL)
+	}
+	return names, nil
+}
+
+// listOfflineModules lists the module directories of the offline mirror.
+func (c *Client) listOfflineModules(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(c.offlineDir, "modules"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %w: %s", ErrListingUnsupported, ErrOffline, filepath.Join(c.offlineDir, "modules"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list modules: %w", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return validModuleNames(names), nil
+}
+
+// hrefPattern matches the relative links to subdirectories in an HTML
+// directory listing, such as <a href="rules_go/">.
+var hrefPattern = regexp.MustCompile(`href="([^"/?#]+)/"`)
+
+// directoryListingNames returns the subdirectories linked from an HTML
+// directory listing.
+func directoryListingNames(data []byte) []string {
+	var names []string
+	for _, match := range hrefPattern.FindAllSubmatch(data, -1) {
+		if name, err := url.PathUnescape(string(match[1])); err == nil {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// validModuleNames returns the valid module names among names, sorted and
+// without duplicates. Other entries, such as a parent directory link, are
+// dropped.
+func validModuleNames(names []string) []string {
+	names = slices.DeleteFunc(names, func(name string) bool {
+		_, err := label.NewModule(name)
+		return err != nil
+	})
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
 // ClearCache removes all cached data.
 func (c *Client) ClearCache() {
 	c.metadataCache = sync.Map{}
 	c.sourceCache = sync.Map{}
+	c.attestCache = sync.Map{}
+	c.moduleCache = sync.Map{}
 }
 
-// fetch performs an HTTP GET and returns the response body.
+// get returns the registry file at path, relative to the registry root, from
+// the offline mirror if one is configured and from the network otherwise.
+func (c *Client) get(ctx context.Context, path string) ([]byte, error) {
+	if c.offlineDir == "" {
+		return c.fetch(ctx, c.baseURL+"/"+path)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// Module names and versions come from MODULE.bazel files; refuse any that
+	// would escape the mirror directory.
+	if !filepath.IsLocal(filepath.FromSlash(path)) {
+		return nil, fmt.Errorf("%w: %s: path escapes %s", ErrOffline, path, c.offlineDir)
+	}
+	file := filepath.Join(c.offlineDir, filepath.FromSlash(path))
+	data, err := os.ReadFile(file) // #nosec G304 -- path is confined to the offline mirror
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrOffline, file)
+	}
+	return data, err
+}
+
+// fetch performs an HTTP GET and returns the response body, retrying
+// transient failures as configured by WithRetry.
 func (c *Client) fetch(ctx context.Context, url string) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		data, err := c.fetchOnce(ctx, url)
+		if err == nil {
+			return data, nil
+		}
+		if attempt >= c.retryAttempts || !retryable(ctx, err) {
+			return nil, withRetries(err, attempt-1)
+		}
+
+		delay := c.retryDelay(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return nil, withRetries(err, attempt-1)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, withRetries(err, attempt-1)
+		case <-timer.C:
+		}
+	}
+}
+
+// fetchOnce performs a single HTTP GET and returns the response body.
+func (c *Client) fetchOnce(ctx context.Context, url string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
 	if err != nil {
 		return nil, err
@@ -196,12 +497,68 @@ func (c *Client) fetch(ctx context.Context, url string) ([]byte, error) {
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, url)
+		return nil, &statusError{code: resp.StatusCode, url: url}
 	}
 
 	return io.ReadAll(resp.Body)
 }
 
+// retryDelay returns how long to wait after the given failed attempt:
+// exponential backoff from the base delay, with the upper half jittered so
+// concurrent clients do not retry in lockstep.
+func (c *Client) retryDelay(attempt int) time.Duration {
+	delay := c.retryBaseDelay << min(attempt-1, 30)
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// retryable reports whether a failed request is transient: a 5xx response or
+// a network error while ctx is still live. 4xx responses are final.
+func retryable(ctx context.Context, err error) bool {
+	var status *statusError
+	if errors.As(err, &status) {
+		return status.code >= http.StatusInternalServerError
+	}
+	return ctx.Err() == nil
+}
+
+// withRetries wraps err in a *RetryError recording the number of retries
+// performed, if any.
+func withRetries(err error, retries int) error {
+	if retries == 0 {
+		return err
+	}
+	return &RetryError{Err: err, Retries: retries}
+}
+
+// RetryError is returned when a request configured by WithRetry still failed
+// after being retried. Use errors.As to get the retry count.
+type RetryError struct {
+	Err     error // Error of the last attempt
+	Retries int   // Retries performed after the first attempt
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%v (after %d retries)", e.Err, e.Retries)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// statusError reports a registry response with a status other than 200 OK.
+type statusError struct {
+	code int
+	url  string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.code, e.url)
+}
+
 // ModuleVersionInfo combines metadata and source for a specific version.
 type ModuleVersionInfo struct {
 	Name     string
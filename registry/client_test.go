@@ -2,9 +2,13 @@ package registry
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -24,7 +28,7 @@ func TestNewClient_BaseURL(t *testing.T) {
 	}{
 		{"https://bcr.bazel.build", "https://bcr.bazel.build"},
 		{"https://bcr.bazel.build/", "https://bcr.bazel.build"},
-		{"https://bcr.bazel.build//", "https://bcr.bazel.build/"},
+		{"https://bcr.bazel.build//", "https://bcr.bazel.build"},
 		{"http://localhost:8080", "http://localhost:8080"},
 		{"http://localhost:8080/", "http://localhost:8080"},
 	}
@@ -37,6 +41,81 @@ func TestNewClient_BaseURL(t *testing.T) {
 	}
 }
 
+// TestClient_URLJoining checks that any number of trailing slashes on the
+// base URL yields exactly one slash between path segments for every endpoint.
+func TestClient_URLJoining(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		switch {
+		case strings.HasSuffix(r.URL.Path, "metadata.json"):
+			fmt.Fprint(w, `{"versions": ["1.0.0"]}`)
+		case strings.HasSuffix(r.URL.Path, "source.json"):
+			fmt.Fprint(w, `{"url": "https://example.com/a.zip"}`)
+		default:
+			fmt.Fprint(w, `module(name = "a", version = "1.0.0")`)
+		}
+	}))
+	defer server.Close()
+
+	endpoints := []struct {
+		name     string
+		call     func(ctx context.Context, c *Client) error
+		wantPath string
+	}{
+		{
+			name: "metadata",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetMetadata(ctx, "a")
+				return err
+			},
+			wantPath: "/modules/a/metadata.json",
+		},
+		{
+			name: "source",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetSource(ctx, "a", "1.0.0")
+				return err
+			},
+			wantPath: "/modules/a/1.0.0/source.json",
+		},
+		{
+			name: "module",
+			call: func(ctx context.Context, c *Client) error {
+				_, err := c.GetModuleFile(ctx, "a", "1.0.0")
+				return err
+			},
+			wantPath: "/modules/a/1.0.0/MODULE.bazel",
+		},
+	}
+
+	for _, suffix := range []string{"", "/", "//"} {
+		for _, ep := range endpoints {
+			t.Run(fmt.Sprintf("%s/%d_slashes", ep.name, len(suffix)), func(t *testing.T) {
+				mu.Lock()
+				paths = nil
+				mu.Unlock()
+
+				c := NewClient(server.URL+suffix, WithValidation(false))
+				if err := ep.call(context.Background(), c); err != nil {
+					t.Fatalf("request failed: %v", err)
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				if len(paths) != 1 || paths[0] != ep.wantPath {
+					t.Errorf("requested paths = %v, want [%s]", paths, ep.wantPath)
+				}
+			})
+		}
+	}
+}
+
 // TestNewClient_EmptyURL tests empty URL handling
 func TestNewClient_EmptyURL(t *testing.T) {
 	c := NewClient("")
@@ -348,6 +427,165 @@ func TestGetSource_DifferentVersionsNotCached(t *testing.T) {
 	}
 }
 
+// TestGetAttestations_Success tests fetching, validating and caching attestations
+func TestGetAttestations_Success(t *testing.T) {
+	callCount := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&callCount, 1)
+		if r.URL.Path == "/modules/test_module/1.0.0/attestations.json" {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{
+				"mediaType": "application/vnd.build.bazel.registry.attestation+json;version=1.0.0",
+				"attestations": {
+					"source.json": {
+						"url": "https://example.com/source.json.intoto.jsonl",
+						"integrity": "sha256-AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
+					},
+					"MODULE.bazel": {
+						"url": "https://example.com/MODULE.bazel.intoto.jsonl",
+						"integrity": "sha256-BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB="
+					}
+				}
+			}`)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	ctx := context.Background()
+
+	attestations, err := c.GetAttestations(ctx, "test_module", "1.0.0")
+	if err != nil {
+		t.Fatalf("GetAttestations failed: %v", err)
+	}
+	if !strings.HasPrefix(attestations.MediaType, "application/vnd.build.bazel.registry.attestation+json") {
+		t.Errorf("MediaType = %q", attestations.MediaType)
+	}
+	if len(attestations.Attestations) != 2 {
+		t.Fatalf("Attestations = %v, want 2 entries", attestations.Attestations)
+	}
+	if got := attestations.Attestations["source.json"].URL; got != "https://example.com/source.json.intoto.jsonl" {
+		t.Errorf("source.json URL = %q", got)
+	}
+
+	// Second call (should use cache)
+	if _, err := c.GetAttestations(ctx, "test_module", "1.0.0"); err != nil {
+		t.Fatalf("GetAttestations (cached) failed: %v", err)
+	}
+	if atomic.LoadInt32(&callCount) != 1 {
+		t.Errorf("Expected 1 HTTP call (cached), got %d", callCount)
+	}
+}
+
+// TestGetAttestations_NotPublished tests that a missing file wraps ErrNoAttestations
+func TestGetAttestations_NotPublished(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/broken/1.0.0/attestations.json":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	ctx := context.Background()
+
+	if _, err := c.GetAttestations(ctx, "test_module", "1.0.0"); !errors.Is(err, ErrNoAttestations) {
+		t.Errorf("GetAttestations error = %v, want ErrNoAttestations", err)
+	}
+	// Server errors are not mistaken for a missing file.
+	if _, err := c.GetAttestations(ctx, "broken", "1.0.0"); err == nil || errors.Is(err, ErrNoAttestations) {
+		t.Errorf("GetAttestations error = %v, want a fetch error", err)
+	}
+
+	offline := NewClient(server.URL, WithOfflineMode(t.TempDir()))
+	if _, err := offline.GetAttestations(ctx, "test_module", "1.0.0"); !errors.Is(err, ErrNoAttestations) || !errors.Is(err, ErrOffline) {
+		t.Errorf("offline GetAttestations error = %v, want ErrNoAttestations and ErrOffline", err)
+	}
+}
+
+// TestListModules tests listing modules from JSON and HTML indexes
+func TestListModules(t *testing.T) {
+	tests := []struct {
+		name    string
+		index   string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:  "json",
+			index: `["rules_go", "bazel_skylib", "rules_go"]`,
+			want:  []string{"bazel_skylib", "rules_go"},
+		},
+		{
+			name: "html directory listing",
+			index: `<html><body><h1>Index of /modules/</h1>
+<a href="../">../</a>
+<a href="abseil-cpp/">abseil-cpp/</a>
+<a href="rules_cc/">rules_cc/</a>
+<a href="README.md">README.md</a>
+</body></html>`,
+			want: []string{"abseil-cpp", "rules_cc"},
+		},
+		{
+			name:    "not an index",
+			index:   `<html><body>Welcome</body></html>`,
+			wantErr: ErrListingUnsupported,
+		},
+		{
+			name:    "no index",
+			wantErr: ErrListingUnsupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/modules/" || tt.index == "" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				fmt.Fprint(w, tt.index)
+			}))
+			defer server.Close()
+
+			got, err := NewClient(server.URL).ListModules(context.Background())
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr != nil) != (err != nil) {
+				t.Fatalf("ListModules() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ListModules() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestListModules_Offline tests listing the modules of an offline mirror
+func TestListModules_Offline(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"rules_go", "bazel_skylib"} {
+		if err := os.MkdirAll(filepath.Join(dir, "modules", name, "1.0.0"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := NewClient("https://unused.example.com", WithOfflineMode(dir)).ListModules(context.Background())
+	if err != nil {
+		t.Fatalf("ListModules() error = %v", err)
+	}
+	if want := []string{"bazel_skylib", "rules_go"}; !slices.Equal(got, want) {
+		t.Errorf("ListModules() = %v, want %v", got, want)
+	}
+
+	_, err = NewClient("https://unused.example.com", WithOfflineMode(t.TempDir())).ListModules(context.Background())
+	if !errors.Is(err, ErrListingUnsupported) || !errors.Is(err, ErrOffline) {
+		t.Errorf("ListModules() of an empty mirror error = %v, want ErrListingUnsupported and ErrOffline", err)
+	}
+}
+
 // TestGetModuleFile_Success tests fetching MODULE.bazel content
 func TestGetModuleFile_Success(t *testing.T) {
 	expectedContent := `module(name = "test", version = "1.0.0")`
@@ -916,3 +1154,253 @@ func TestTimeout_OptionsOrder(t *testing.T) {
 		t.Errorf("Last option (WithTimeout) should win: got %v", c2.client.Timeout)
 	}
 }
+
+func TestWithOfflineMode(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"modules/rules_go/metadata.json":       `{"versions": ["0.50.1"]}`,
+		"modules/rules_go/0.50.1/source.json":  `{"url": "https://example.com/rules_go.zip", "integrity": "sha256-AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="}`,
+		"modules/rules_go/0.50.1/MODULE.bazel": `module(name = "rules_go", version = "0.50.1")`,
+		"modules/gazelle/0.38.0/MODULE.bazel":  `module(name = "gazelle", version = "0.38.0")`,
+	}
+	for path, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := NewClient(server.URL, WithOfflineMode(dir), WithValidation(false))
+	ctx := context.Background()
+
+	metadata, err := c.GetMetadata(ctx, "rules_go")
+	if err != nil {
+		t.Fatalf("GetMetadata() error = %v", err)
+	}
+	if !metadata.HasVersion("0.50.1") {
+		t.Errorf("metadata versions = %v, want 0.50.1", metadata.Versions)
+	}
+	source, err := c.GetSource(ctx, "rules_go", "0.50.1")
+	if err != nil {
+		t.Fatalf("GetSource() error = %v", err)
+	}
+	if source.URL != "https://example.com/rules_go.zip" {
+		t.Errorf("source URL = %q", source.URL)
+	}
+	moduleFile, err := c.GetModuleFile(ctx, "rules_go", "0.50.1")
+	if err != nil {
+		t.Fatalf("GetModuleFile() error = %v", err)
+	}
+	if string(moduleFile) != files["modules/rules_go/0.50.1/MODULE.bazel"] {
+		t.Errorf("GetModuleFile() = %q", moduleFile)
+	}
+
+	// Files missing from the mirror fail with ErrOffline.
+	if _, err := c.GetMetadata(ctx, "gazelle"); !errors.Is(err, ErrOffline) {
+		t.Errorf("GetMetadata(gazelle) error = %v, want ErrOffline", err)
+	}
+	if _, err := c.GetSource(ctx, "gazelle", "0.38.0"); !errors.Is(err, ErrOffline) {
+		t.Errorf("GetSource(gazelle) error = %v, want ErrOffline", err)
+	}
+	if _, err := c.GetModuleFile(ctx, "gazelle", "1.0.0"); !errors.Is(err, ErrOffline) {
+		t.Errorf("GetModuleFile(gazelle@1.0.0) error = %v, want ErrOffline", err)
+	}
+	if _, err := c.GetModuleFile(ctx, "..", ".."); !errors.Is(err, ErrOffline) {
+		t.Errorf("GetModuleFile(../..) error = %v, want ErrOffline", err)
+	}
+
+	if n := requests.Load(); n != 0 {
+		t.Errorf("offline client made %d network requests, want 0", n)
+	}
+}
+
+func TestPrefetchModule(t *testing.T) {
+	var requests, inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		switch {
+		case strings.Contains(r.URL.Path, "/9.9.9/"):
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasSuffix(r.URL.Path, "source.json"):
+			fmt.Fprint(w, `{"url": "https://example.com/a.zip", "integrity": "sha256-abc"}`)
+		default:
+			fmt.Fprint(w, `module(name = "a")`)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL, WithValidation(false), WithPrefetchConcurrency(2))
+	ctx := context.Background()
+	versions := []string{"1.0.0", "1.1.0", "1.2.0", "2.0.0"}
+
+	if err := c.PrefetchModule(ctx, "a", versions); err != nil {
+		t.Fatalf("PrefetchModule failed: %v", err)
+	}
+	if got := requests.Load(); got != 8 {
+		t.Errorf("PrefetchModule made %d requests, want 8", got)
+	}
+	if got := maxInFlight.Load(); got > 2 {
+		t.Errorf("max concurrent requests = %d, want at most 2", got)
+	}
+
+	// Prefetched files are served from the cache.
+	for _, v := range versions {
+		if _, err := c.GetModuleFile(ctx, "a", v); err != nil {
+			t.Errorf("GetModuleFile(%s) failed: %v", v, err)
+		}
+		if _, err := c.GetSource(ctx, "a", v); err != nil {
+			t.Errorf("GetSource(%s) failed: %v", v, err)
+		}
+	}
+	if got := requests.Load(); got != 8 {
+		t.Errorf("requests after cached reads = %d, want 8", got)
+	}
+
+	// Failures are reported without stopping the other fetches.
+	err := c.PrefetchModule(ctx, "a", []string{"9.9.9", "3.0.0"})
+	if err == nil || !strings.Contains(err.Error(), "a@9.9.9") {
+		t.Errorf("PrefetchModule error = %v, want failures for a@9.9.9", err)
+	}
+	if _, err := c.GetModuleFile(ctx, "a", "3.0.0"); err != nil {
+		t.Errorf("GetModuleFile(3.0.0) failed: %v", err)
+	}
+	if got := requests.Load(); got != 12 {
+		t.Errorf("requests after partial prefetch = %d, want 12", got)
+	}
+}
+
+func TestWithPrefetchConcurrency_Default(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		c := NewClient("https://example.com", WithPrefetchConcurrency(n))
+		if c.prefetchConcurrency != DefaultPrefetchConcurrency {
+			t.Errorf("WithPrefetchConcurrency(%d) = %d, want %d", n, c.prefetchConcurrency, DefaultPrefetchConcurrency)
+		}
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		switch r.URL.Path {
+		case "/modules/flaky/metadata.json":
+			if n < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, `{"versions": ["1.0.0"]}`)
+		case "/modules/down/metadata.json", "/modules/down/1.0.0/source.json":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	newClient := func() *Client {
+		requests.Store(0)
+		return NewClient(server.URL, WithValidation(false), WithRetry(4, time.Millisecond))
+	}
+
+	t.Run("5xx is retried", func(t *testing.T) {
+		c := newClient()
+		if _, err := c.GetMetadata(ctx, "flaky"); err != nil {
+			t.Fatalf("GetMetadata failed: %v", err)
+		}
+		if got := requests.Load(); got != 3 {
+			t.Errorf("requests = %d, want 3", got)
+		}
+	})
+
+	t.Run("retries are reported", func(t *testing.T) {
+		c := newClient()
+		_, err := c.GetMetadata(ctx, "down")
+		var status *statusError
+		if !errors.As(err, &status) || status.code != http.StatusBadGateway || !strings.Contains(err.Error(), "after 3 retries") {
+			t.Errorf("GetMetadata error = %v, want status 502 after 3 retries", err)
+		}
+		var retryErr *RetryError
+		if !errors.As(err, &retryErr) || retryErr.Retries != 3 {
+			t.Errorf("GetMetadata error = %v, want a *RetryError with 3 retries", err)
+		}
+		if got := requests.Load(); got != 4 {
+			t.Errorf("requests = %d, want 4", got)
+		}
+	})
+
+	t.Run("retry count is available from GetSource", func(t *testing.T) {
+		c := newClient()
+		_, err := c.GetSource(ctx, "down", "1.0.0")
+		var retryErr *RetryError
+		if !errors.As(err, &retryErr) || retryErr.Retries != 3 {
+			t.Errorf("GetSource error = %v, want a *RetryError with 3 retries", err)
+		}
+	})
+
+	t.Run("4xx is not retried", func(t *testing.T) {
+		c := newClient()
+		_, err := c.GetSource(ctx, "missing", "1.0.0")
+		var status *statusError
+		if !errors.As(err, &status) || status.code != http.StatusNotFound || strings.Contains(err.Error(), "retries") {
+			t.Errorf("GetSource error = %v, want a 404 without retries", err)
+		}
+		var retryErr *RetryError
+		if errors.As(err, &retryErr) {
+			t.Errorf("GetSource error = %v, want no *RetryError", err)
+		}
+		if got := requests.Load(); got != 1 {
+			t.Errorf("requests = %d, want 1", got)
+		}
+	})
+
+	t.Run("network errors are retried", func(t *testing.T) {
+		c := NewClient("http://127.0.0.1:1", WithValidation(false), WithRetry(2, time.Millisecond))
+		_, err := c.GetMetadata(ctx, "any")
+		var status *statusError
+		if err == nil || errors.As(err, &status) || !strings.Contains(err.Error(), "after 1 retries") {
+			t.Errorf("GetMetadata error = %v, want a network error after 1 retry", err)
+		}
+		var retryErr *RetryError
+		if !errors.As(err, &retryErr) || retryErr.Retries != 1 {
+			t.Errorf("GetMetadata error = %v, want a *RetryError with 1 retry", err)
+		}
+	})
+
+	t.Run("context deadline stops retries", func(t *testing.T) {
+		requests.Store(0)
+		c := NewClient(server.URL, WithValidation(false), WithRetry(10, time.Hour))
+		deadlineCtx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		start := time.Now()
+		if _, err := c.GetMetadata(deadlineCtx, "down"); err == nil {
+			t.Fatal("GetMetadata expected error")
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("GetMetadata took %v, want an early return", elapsed)
+		}
+		if got := requests.Load(); got != 1 {
+			t.Errorf("requests = %d, want 1", got)
+		}
+	})
+}
@@ -17,8 +17,9 @@
 //	    └── {name}/
 //	        ├── metadata.json     # Module metadata (versions, maintainers)
 //	        └── {version}/
-//	            ├── MODULE.bazel  # Module file
-//	            └── source.json   # Source location (archive/git)
+//	            ├── MODULE.bazel       # Module file
+//	            ├── source.json        # Source location (archive/git)
+//	            └── attestations.json  # Provenance attestations (optional)
 //
 // # Usage
 //
@@ -30,6 +31,22 @@
 //	    // Handle validation or network errors
 //	}
 //
+// Read from a vendored registry snapshot without network access:
+//
+//	client := registry.NewClient("https://bcr.bazel.build",
+//	    registry.WithOfflineMode("third_party/bcr"))
+//	metadata, err := client.GetMetadata(ctx, "rules_go")
+//	if errors.Is(err, registry.ErrOffline) {
+//	    // rules_go is not in the snapshot
+//	}
+//
+// List the modules of a registry whose modules directory serves an index:
+//
+//	names, err := client.ListModules(ctx)
+//	if errors.Is(err, registry.ErrListingUnsupported) {
+//	    // The registry has no module index
+//	}
+//
 // Validate arbitrary JSON against BCR schemas:
 //
 //	validator := registry.NewValidator()
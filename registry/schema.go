@@ -1,9 +1,13 @@
 package registry
 
 import (
-	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
 )
 
 // Validator validates registry JSON data against BCR schema rules.
@@ -11,27 +15,57 @@ import (
 // as the official BCR JSON schemas.
 type Validator struct{}
 
-// NewValidator creates a validator for BCR metadata and source files.
+// NewValidator creates a validator for BCR metadata, source and attestation files.
 func NewValidator() *Validator {
 	return &Validator{}
 }
 
 // ValidateMetadata validates JSON data against metadata.json schema rules.
+//
+// All violations are reported, not just the first: unknown fields and values
+// of the wrong JSON type are collected together with the schema rule failures
+// into one *ValidationErrors, whose FieldError.Pointer locates each of them.
+// Only malformed JSON stops validation early.
 func (v *Validator) ValidateMetadata(data []byte) error {
 	var m Metadata
-	if err := unmarshalStrict(data, &m); err != nil {
-		return fmt.Errorf("invalid JSON: %w", err)
+	errs, err := decodeJSON(data, &m)
+	if err != nil {
+		return err
 	}
-	return m.Validate()
+	return errs.merge(m.Validate())
 }
 
 // ValidateSource validates JSON data against source.json schema rules.
+// Like ValidateMetadata, it reports every violation found.
 func (v *Validator) ValidateSource(data []byte) error {
 	var s Source
-	if err := unmarshalStrict(data, &s); err != nil {
-		return fmt.Errorf("invalid JSON: %w", err)
+	errs, err := decodeJSON(data, &s)
+	if err != nil {
+		return err
 	}
-	return s.Validate()
+	return errs.merge(s.Validate())
+}
+
+// ValidateAttestations validates JSON data against attestations.json rules.
+// Like ValidateMetadata, it reports every violation found.
+func (v *Validator) ValidateAttestations(data []byte) error {
+	var a Attestations
+	errs, err := decodeJSON(data, &a)
+	if err != nil {
+		return err
+	}
+	return errs.merge(a.Validate())
+}
+
+// ValidateRegistryConfig validates JSON data against bazel_registry.json
+// rules. Like ValidateMetadata, it reports every violation found.
+func (v *Validator) ValidateRegistryConfig(data []byte) error {
+	var c RegistryConfig
+	errs, err := decodeJSON(data, &c)
+	if err != nil {
+		return err
+	}
+	return errs.merge(c.Validate())
 }
 
 // ValidateMetadataStruct validates a Metadata struct.
@@ -44,9 +78,131 @@ func (v *Validator) ValidateSourceStruct(s *Source) error {
 	return s.Validate()
 }
 
-// unmarshalStrict unmarshals JSON with strict settings (disallow unknown fields).
-func unmarshalStrict(data []byte, v any) error {
-	dec := json.NewDecoder(bytes.NewReader(data))
-	dec.DisallowUnknownFields()
-	return dec.Decode(v)
+// ValidateAttestationsStruct validates an Attestations struct.
+func (v *Validator) ValidateAttestationsStruct(a *Attestations) error {
+	return a.Validate()
+}
+
+// ValidateRegistryConfigStruct validates a RegistryConfig struct.
+func (v *Validator) ValidateRegistryConfigStruct(c *RegistryConfig) error {
+	return c.Validate()
+}
+
+// decodeJSON unmarshals data into v, a pointer to one of the registry file
+// types. Unknown fields and values of the wrong JSON type do not stop
+// decoding: they are returned as field errors and the rest of the document is
+// still decoded. The error is non-nil only for malformed JSON.
+func decodeJSON(data []byte, v any) (*ValidationErrors, error) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var errs ValidationErrors
+	checkShape(&errs, "", doc, reflect.TypeOf(v).Elem())
+
+	// Mistyped values were reported above; json.Unmarshal skips them and
+	// decodes everything else.
+	var typeErr *json.UnmarshalTypeError
+	if err := json.Unmarshal(data, v); err != nil && !errors.As(err, &typeErr) {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return &errs, nil
+}
+
+// checkShape reports every place where the decoded JSON value doc, found at
+// field path path, does not fit the Go type t.
+func checkShape(errs *ValidationErrors, path string, doc any, t reflect.Type) {
+	if doc == nil {
+		return // null decodes to the zero value
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := doc.(map[string]any)
+		if !ok {
+			errs.Add(path, "expected object, got "+jsonKind(doc))
+			return
+		}
+		fields := jsonFields(t)
+		for _, key := range slices.Sorted(maps.Keys(obj)) {
+			field := key
+			if path != "" {
+				field = path + "." + key
+			}
+			ft, ok := fields[key]
+			if !ok {
+				errs.Add(field, "unknown field")
+				continue
+			}
+			checkShape(errs, field, obj[key], ft)
+		}
+	case reflect.Map:
+		obj, ok := doc.(map[string]any)
+		if !ok {
+			errs.Add(path, "expected object, got "+jsonKind(doc))
+			return
+		}
+		for _, key := range slices.Sorted(maps.Keys(obj)) {
+			checkShape(errs, fmt.Sprintf("%s[%q]", path, key), obj[key], t.Elem())
+		}
+	case reflect.Slice:
+		arr, ok := doc.([]any)
+		if !ok {
+			errs.Add(path, "expected array, got "+jsonKind(doc))
+			return
+		}
+		for i, elem := range arr {
+			checkShape(errs, fmt.Sprintf("%s[%d]", path, i), elem, t.Elem())
+		}
+	case reflect.String:
+		if _, ok := doc.(string); !ok {
+			errs.Add(path, "expected string, got "+jsonKind(doc))
+		}
+	case reflect.Bool:
+		if _, ok := doc.(bool); !ok {
+			errs.Add(path, "expected boolean, got "+jsonKind(doc))
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := doc.(float64)
+		if !ok {
+			errs.Add(path, "expected integer, got "+jsonKind(doc))
+		} else if n != float64(int64(n)) {
+			errs.Add(path, "expected integer, got fractional number")
+		}
+	}
+}
+
+// jsonFields maps the JSON names of struct type t's fields to their types.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// jsonKind names the JSON type of a value decoded into an any.
+func jsonKind(doc any) string {
+	switch doc.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
 }
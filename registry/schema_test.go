@@ -1,6 +1,8 @@
 package registry
 
 import (
+	"errors"
+	"slices"
 	"testing"
 )
 
@@ -42,22 +44,22 @@ func TestValidator_ValidateMetadata(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "missing required homepage",
+			name: "without optional homepage",
 			json: `{
 				"maintainers": [{"github": "user1"}],
 				"repository": ["github:example/repo"],
 				"versions": ["1.0.0"]
 			}`,
-			wantErr: true,
+			wantErr: false,
 		},
 		{
-			name: "missing required maintainers",
+			name: "without optional maintainers",
 			json: `{
 				"homepage": "https://example.com",
 				"repository": ["github:example/repo"],
 				"versions": ["1.0.0"]
 			}`,
-			wantErr: true,
+			wantErr: false,
 		},
 		{
 			name: "empty maintainers array",
@@ -67,6 +69,24 @@ func TestValidator_ValidateMetadata(t *testing.T) {
 				"repository": ["github:example/repo"],
 				"versions": ["1.0.0"]
 			}`,
+			wantErr: false,
+		},
+		{
+			name:    "versions only",
+			json:    `{"versions": ["1.0.0"]}`,
+			wantErr: false,
+		},
+		{
+			name: "invalid maintainer",
+			json: `{
+				"maintainers": [{"github": "bad user"}],
+				"versions": ["1.0.0"]
+			}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing required versions",
+			json:    `{"homepage": "https://example.com", "maintainers": [{"github": "user1"}]}`,
 			wantErr: true,
 		},
 		{
@@ -173,6 +193,157 @@ func TestValidator_ValidateSource(t *testing.T) {
 	}
 }
 
+func TestValidator_ValidateAttestations(t *testing.T) {
+	v := NewValidator()
+
+	tests := []struct {
+		name    string
+		json    string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			json: `{
+				"mediaType": "application/vnd.build.bazel.registry.attestation+json;version=1.0.0",
+				"attestations": {
+					"source.json": {"url": "https://example.com/source.json.intoto.jsonl", "integrity": "sha256-abc123"}
+				}
+			}`,
+			wantErr: false,
+		},
+		{
+			name:    "missing media type",
+			json:    `{"attestations": {"source.json": {"url": "https://example.com/a", "integrity": "sha256-abc"}}}`,
+			wantErr: true,
+		},
+		{
+			name:    "no attestations",
+			json:    `{"mediaType": "application/vnd.build.bazel.registry.attestation+json;version=1.0.0", "attestations": {}}`,
+			wantErr: true,
+		},
+		{
+			name: "invalid integrity",
+			json: `{
+				"mediaType": "application/vnd.build.bazel.registry.attestation+json;version=1.0.0",
+				"attestations": {"source.json": {"url": "https://example.com/a", "integrity": "md5-abc"}}
+			}`,
+			wantErr: true,
+		},
+		{
+			name: "unknown field",
+			json: `{
+				"mediaType": "application/vnd.build.bazel.registry.attestation+json;version=1.0.0",
+				"attestations": {"source.json": {"url": "https://example.com/a", "integrity": "sha256-abc"}},
+				"extra": true
+			}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateAttestations([]byte(tt.json))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAttestations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateMetadata_ReportsAllViolations(t *testing.T) {
+	data := `{
+		"homepage": "https://example.com",
+		"maintainers": [{"github": "bad user", "gh": "x"}],
+		"repository": "github:example/repo",
+		"versions": ["1.0.0"],
+		"yanked_versions": {"2.0.0": "broken"},
+		"owner": "nobody"
+	}`
+
+	err := NewValidator().ValidateMetadata([]byte(data))
+	var verrs *ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("ValidateMetadata() error = %v, want *ValidationErrors", err)
+	}
+
+	var pointers []string
+	for _, ferr := range verrs.Errors {
+		pointers = append(pointers, ferr.Pointer())
+	}
+	want := []string{
+		"/maintainers/0/gh",
+		"/owner",
+		"/repository",
+		"/maintainers/0/github",
+		"/yanked_versions/2.0.0",
+	}
+	if !slices.Equal(pointers, want) {
+		t.Errorf("pointers = %v, want %v", pointers, want)
+	}
+	if len(verrs.Unwrap()) != len(want) {
+		t.Errorf("Unwrap() returned %d errors, want %d", len(verrs.Unwrap()), len(want))
+	}
+}
+
+func TestValidator_ValidateRegistryConfig(t *testing.T) {
+	v := NewValidator()
+
+	tests := []struct {
+		name      string
+		json      string
+		wantCount int
+	}{
+		{name: "empty", json: `{}`},
+		{
+			name: "valid",
+			json: `{"mirrors": ["https://mirror.bazel.build/"], "module_base_path": "modules"}`,
+		},
+		{
+			name: "bad mirrors and base path",
+			json: `{
+				"mirrors": ["ftp://mirror.example.com", "mirror.example.com", "https://ok.example.com"],
+				"module_base_path": "../outside"
+			}`,
+			wantCount: 3,
+		},
+		{name: "wrong type", json: `{"mirrors": "https://mirror.bazel.build/"}`, wantCount: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateRegistryConfig([]byte(tt.json))
+			if tt.wantCount == 0 {
+				if err != nil {
+					t.Errorf("ValidateRegistryConfig() error = %v", err)
+				}
+				return
+			}
+			var verrs *ValidationErrors
+			if !errors.As(err, &verrs) || len(verrs.Errors) != tt.wantCount {
+				t.Errorf("ValidateRegistryConfig() error = %v, want %d violations", err, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestFieldError_Pointer(t *testing.T) {
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"", ""},
+		{"homepage", "/homepage"},
+		{"maintainers[0].github", "/maintainers/0/github"},
+		{`yanked_versions["1.0.0"]`, "/yanked_versions/1.0.0"},
+		{`attestations["a/b~c.json"].url`, "/attestations/a~1b~0c.json/url"},
+	}
+	for _, tt := range tests {
+		if got := (&FieldError{Field: tt.field}).Pointer(); got != tt.want {
+			t.Errorf("Pointer() of %q = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
+
 func TestValidator_ValidateMetadataStruct(t *testing.T) {
 	v := NewValidator()
 
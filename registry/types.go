@@ -5,13 +5,16 @@ import "github.com/albertocavalcante/go-bzlmod/internal/compat"
 // Metadata represents the metadata.json file for a module in the registry.
 // This matches the BCR metadata.schema.json specification.
 type Metadata struct {
-	// Homepage is the URL to the project's homepage.
+	// Homepage is the URL to the project's homepage. Empty if the registry
+	// does not declare one.
 	Homepage string `json:"homepage"`
 
 	// Maintainers lists individuals who can be notified about the module.
+	// Empty if the registry does not declare any.
 	Maintainers []Maintainer `json:"maintainers"`
 
-	// Repository is an allowlist of source URLs.
+	// Repository is an allowlist of source URLs. Empty if the registry does
+	// not declare one.
 	// Format: "github:org/repo" or a URL prefix like "https://example.com/".
 	Repository []string `json:"repository"`
 
@@ -25,6 +28,11 @@ type Metadata struct {
 	// Deprecated explains why the module should not be used.
 	// If set, the latest version may be yanked.
 	Deprecated string `json:"deprecated,omitempty"`
+
+	// License is the module's license as an SPDX identifier or expression
+	// (e.g. "Apache-2.0"). Not part of the BCR schema, but published by some
+	// registries. Empty if the registry does not declare one.
+	License string `json:"license,omitempty"`
 }
 
 // Maintainer represents a module maintainer in metadata.json.
@@ -117,6 +125,30 @@ type Source struct {
 	DocsURL string `json:"docs_url,omitempty"`
 }
 
+// Attestations represents the attestations.json file of a module version,
+// which points to the supply-chain provenance published for its artifacts.
+// Many modules have no attestations; see GetAttestations.
+//
+// Reference: https://github.com/bazel-contrib/publish-to-bcr/blob/main/docs/attestations.md
+type Attestations struct {
+	// MediaType identifies the file format and its version, e.g.
+	// "application/vnd.build.bazel.registry.attestation+json;version=1.0.0".
+	MediaType string `json:"mediaType"`
+
+	// Attestations maps an artifact (e.g. "source.json", "MODULE.bazel" or
+	// the source archive's file name) to its attestation.
+	Attestations map[string]Attestation `json:"attestations"`
+}
+
+// Attestation locates the attestation of one artifact.
+type Attestation struct {
+	// URL is where the attestation (typically an in-toto bundle) is published.
+	URL string `json:"url"`
+
+	// Integrity is the SRI hash (e.g., "sha256-...") of the attestation file.
+	Integrity string `json:"integrity"`
+}
+
 // RegistryConfig represents the bazel_registry.json file at the registry root.
 type RegistryConfig struct {
 	// Mirrors lists alternative URLs to try when the primary URL fails.
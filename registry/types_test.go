@@ -135,6 +135,7 @@ func TestMetadata_JSONRoundTrip(t *testing.T) {
 		YankedVersions: map[string]string{
 			"0.9.0": "deprecated",
 		},
+		License: "Apache-2.0",
 	}
 
 	data, err := json.Marshal(original)
@@ -156,6 +157,9 @@ func TestMetadata_JSONRoundTrip(t *testing.T) {
 	if restored.Maintainers[0].GitHub != original.Maintainers[0].GitHub {
 		t.Errorf("Maintainer.GitHub = %q, want %q", restored.Maintainers[0].GitHub, original.Maintainers[0].GitHub)
 	}
+	if restored.License != original.License {
+		t.Errorf("License = %q, want %q", restored.License, original.License)
+	}
 }
 
 func TestSource_JSONRoundTrip(t *testing.T) {
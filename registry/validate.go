@@ -3,7 +3,12 @@ package registry
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"net/url"
+	"path"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -20,6 +25,49 @@ func (e *FieldError) Error() string {
 	return e.Message
 }
 
+// Pointer returns the RFC 6901 JSON pointer of the field, e.g.
+// "/maintainers/0/github" for "maintainers[0].github". An error about the
+// whole document has the empty pointer.
+func (e *FieldError) Pointer() string {
+	var b strings.Builder
+	rest := e.Field
+	for rest != "" {
+		var token string
+		switch {
+		case rest[0] == '.':
+			rest = rest[1:]
+			continue
+		case strings.HasPrefix(rest, `["`):
+			// Map key, quoted by %q.
+			quoted, err := strconv.QuotedPrefix(rest[1:])
+			if err != nil {
+				return "/" + pointerEscaper.Replace(e.Field)
+			}
+			token, _ = strconv.Unquote(quoted)
+			rest = strings.TrimPrefix(rest[1+len(quoted):], "]")
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				end = len(rest)
+			}
+			token = rest[1:end]
+			rest = rest[min(end+1, len(rest)):]
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			token, rest = rest[:end], rest[end:]
+		}
+		b.WriteByte('/')
+		b.WriteString(pointerEscaper.Replace(token))
+	}
+	return b.String()
+}
+
+// pointerEscaper escapes a JSON pointer reference token.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
 // ValidationErrors collects multiple validation errors.
 type ValidationErrors struct {
 	Errors []*FieldError
@@ -72,6 +120,35 @@ func (e *ValidationErrors) ToError() error {
 	return e
 }
 
+// merge adds the field errors of err, the result of a Validate method, to e
+// and returns e.ToError(). Errors about a field that e already reports, or
+// about something inside it, are dropped: a value of the wrong JSON type is
+// not also reported as missing.
+func (e *ValidationErrors) merge(err error) error {
+	var verrs *ValidationErrors
+	if errors.As(err, &verrs) {
+		for _, ferr := range verrs.Errors {
+			if !e.covers(ferr.Field) {
+				e.AddError(ferr)
+			}
+		}
+	}
+	return e.ToError()
+}
+
+// covers reports whether e has an error about field or one of its parents.
+func (e *ValidationErrors) covers(field string) bool {
+	for _, ferr := range e.Errors {
+		if ferr.Field == field {
+			return true
+		}
+		if rest, ok := strings.CutPrefix(field, ferr.Field); ok && (ferr.Field == "" || rest[0] == '.' || rest[0] == '[') {
+			return true
+		}
+	}
+	return false
+}
+
 // Precompiled regex patterns for validation.
 var (
 	// GitHub username: alphanumeric and hyphens
@@ -89,31 +166,23 @@ var (
 
 // Validate checks that the Metadata conforms to BCR schema requirements.
 // Returns nil if valid, or ValidationErrors containing all issues found.
+//
+// Homepage, Maintainers and Repository are optional, since registries other
+// than the BCR often omit them; the maintainers that are listed are still
+// validated.
 func (m *Metadata) Validate() error {
 	var errs ValidationErrors
 
-	// Required fields
-	if m.Homepage == "" {
-		errs.Add("homepage", "required field is missing")
-	}
-
-	if len(m.Maintainers) == 0 {
-		errs.Add("maintainers", "required field is missing or empty")
-	} else {
-		for i := range m.Maintainers {
-			if err := m.Maintainers[i].validate(fmt.Sprintf("maintainers[%d]", i)); err != nil {
-				var ferr *FieldError
-				if errors.As(err, &ferr) {
-					errs.AddError(ferr)
-				}
+	for i := range m.Maintainers {
+		if err := m.Maintainers[i].validate(fmt.Sprintf("maintainers[%d]", i)); err != nil {
+			var ferr *FieldError
+			if errors.As(err, &ferr) {
+				errs.AddError(ferr)
 			}
 		}
 	}
 
-	if len(m.Repository) == 0 {
-		errs.Add("repository", "required field is missing or empty")
-	}
-
+	// Required fields
 	if len(m.Versions) == 0 {
 		errs.Add("versions", "required field is missing or empty")
 	}
@@ -236,14 +305,67 @@ func (s *Source) validateGitRepository(errs *ValidationErrors) {
 	}
 }
 
+// Validate checks that the Attestations are well-formed: a media type and at
+// least one attestation, each with a URL and an SRI integrity hash.
+// Returns nil if valid, or ValidationErrors containing all issues found.
+func (a *Attestations) Validate() error {
+	var errs ValidationErrors
+
+	if a.MediaType == "" {
+		errs.Add("mediaType", "required field is missing")
+	}
+	if len(a.Attestations) == 0 {
+		errs.Add("attestations", "required field is missing or empty")
+	}
+
+	for _, artifact := range slices.Sorted(maps.Keys(a.Attestations)) {
+		att := a.Attestations[artifact]
+		field := fmt.Sprintf("attestations[%q]", artifact)
+		if att.URL == "" {
+			errs.Add(field+".url", "required field is missing")
+		}
+		if att.Integrity == "" {
+			errs.Add(field+".integrity", "required field is missing")
+		} else if !sriPattern.MatchString(att.Integrity) {
+			errs.Add(field+".integrity", "must be a valid SRI hash (e.g., 'sha256-...')")
+		}
+	}
+
+	return errs.ToError()
+}
+
+// Validate checks that the RegistryConfig is usable: every mirror is an
+// absolute http(s) URL and module_base_path, if set, is a relative path that
+// stays inside the registry.
+// Returns nil if valid, or ValidationErrors containing all issues found.
+func (c *RegistryConfig) Validate() error {
+	var errs ValidationErrors
+
+	for i, mirror := range c.Mirrors {
+		u, err := url.Parse(mirror)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs.Add(fmt.Sprintf("mirrors[%d]", i), fmt.Sprintf("must be an absolute http(s) URL, got %q", mirror))
+		}
+	}
+
+	if p := c.ModuleBasePath; p != "" {
+		if clean := path.Clean(p); path.IsAbs(p) || clean == ".." || strings.HasPrefix(clean, "../") {
+			errs.Add("module_base_path", fmt.Sprintf("must be a relative path inside the registry, got %q", p))
+		}
+	}
+
+	return errs.ToError()
+}
+
 // ValidateMetadataJSON validates raw JSON bytes as Metadata.
 // This is a convenience function that unmarshals and validates in one step.
 func ValidateMetadataJSON(data []byte) (*Metadata, error) {
 	var m Metadata
-	if err := unmarshalStrict(data, &m); err != nil {
-		return nil, &FieldError{Message: fmt.Sprintf("invalid JSON: %v", err)}
+	errs, err := decodeJSON(data, &m)
+	if err != nil {
+		return nil, &FieldError{Message: err.Error()}
 	}
-	if err := m.Validate(); err != nil {
+	if err := errs.merge(m.Validate()); err != nil {
 		return nil, err
 	}
 	return &m, nil
@@ -253,10 +375,11 @@ func ValidateMetadataJSON(data []byte) (*Metadata, error) {
 // This is a convenience function that unmarshals and validates in one step.
 func ValidateSourceJSON(data []byte) (*Source, error) {
 	var s Source
-	if err := unmarshalStrict(data, &s); err != nil {
-		return nil, &FieldError{Message: fmt.Sprintf("invalid JSON: %v", err)}
+	errs, err := decodeJSON(data, &s)
+	if err != nil {
+		return nil, &FieldError{Message: err.Error()}
 	}
-	if err := s.Validate(); err != nil {
+	if err := errs.merge(s.Validate()); err != nil {
 		return nil, err
 	}
 	return &s, nil
@@ -1,11 +1,14 @@
 package gobzlmod
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"path"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -50,6 +53,17 @@ type registryChain struct {
 	// Once a module is found in a registry, all versions come from that registry
 	moduleRegistry   map[string]int // module name -> registry index
 	moduleRegistryMu sync.RWMutex
+
+	// pins route module names matching a pattern to a single registry,
+	// most specific pattern first. See withModuleRegistries.
+	pins []registryPin
+}
+
+// registryPin routes the modules whose names match pattern to the registry
+// with base URL url.
+type registryPin struct {
+	pattern string
+	url     string
 }
 
 // newRegistryChain creates a chain of registries from URLs.
@@ -125,10 +139,82 @@ func newRegistryChainWithAllOptionsAndTrace(registryURLs []string, httpClient *h
 	}, nil
 }
 
+// withModuleRegistries pins the modules matching each pattern of pins, a
+// path.Match glob over module names, to the registry with the given URL.
+// A single registry is wrapped in a chain of one so a pin to a registry
+// outside it still fails. It returns reg unchanged if pins is empty.
+func withModuleRegistries(reg Registry, pins map[string]string) Registry {
+	if len(pins) == 0 {
+		return reg
+	}
+	chain, ok := reg.(*registryChain)
+	if !ok {
+		chain = &registryChain{
+			clients:        []Registry{reg},
+			trace:          sharedRegistryFileTrace(reg),
+			moduleRegistry: make(map[string]int),
+		}
+	}
+	chain.pins = make([]registryPin, 0, len(pins))
+	for pattern, url := range pins {
+		chain.pins = append(chain.pins, registryPin{pattern: pattern, url: strings.TrimSuffix(url, "/")})
+	}
+	// The longest pattern is taken as the most specific, so an exact name
+	// wins over a glob that also matches it.
+	slices.SortFunc(chain.pins, func(a, b registryPin) int {
+		if c := cmp.Compare(len(b.pattern), len(a.pattern)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.pattern, b.pattern)
+	})
+	return chain
+}
+
+// pinnedClient returns the index of the registry moduleName is pinned to.
+// pinned is false if no pin matches, in which case the chain falls back as
+// usual. It is an error for the pinned registry not to be in the chain.
+func (rc *registryChain) pinnedClient(moduleName string) (idx int, pinned bool, err error) {
+	for _, pin := range rc.pins {
+		if ok, _ := path.Match(pin.pattern, moduleName); !ok {
+			continue
+		}
+		for i, client := range rc.clients {
+			if strings.TrimSuffix(client.BaseURL(), "/") == pin.url {
+				return i, true, nil
+			}
+		}
+		return 0, true, fmt.Errorf("module %s is pinned to registry %s, which is not in the registry chain", moduleName, pin.url)
+	}
+	return 0, false, nil
+}
+
+// rememberRegistry records that the registry at idx provides moduleName,
+// unless another registry was recorded first.
+func (rc *registryChain) rememberRegistry(moduleName string, idx int) {
+	rc.moduleRegistryMu.Lock()
+	if _, exists := rc.moduleRegistry[moduleName]; !exists {
+		rc.moduleRegistry[moduleName] = idx
+	}
+	rc.moduleRegistryMu.Unlock()
+}
+
 // GetModuleFile fetches a MODULE.bazel file using the registry chain.
 // It tries registries in order for the first request for a module name,
-// then caches which registry provides that module.
+// then caches which registry provides that module. A module pinned to a
+// registry is fetched from that registry only; a miss there is an error.
 func (rc *registryChain) GetModuleFile(ctx context.Context, moduleName, version string) (*ModuleInfo, error) {
+	if idx, pinned, err := rc.pinnedClient(moduleName); pinned {
+		if err != nil {
+			return nil, err
+		}
+		moduleInfo, err := rc.clients[idx].GetModuleFile(ctx, moduleName, version)
+		if err != nil {
+			return nil, &PinnedRegistryError{Module: moduleName, Version: version, File: "MODULE.bazel", Registry: rc.clients[idx].BaseURL(), Err: err}
+		}
+		rc.rememberRegistry(moduleName, idx)
+		return servedBy(moduleInfo, idx), nil
+	}
+
 	// Check if we've already determined which registry provides this module
 	rc.moduleRegistryMu.RLock()
 	registryIdx, found := rc.moduleRegistry[moduleName]
@@ -138,7 +224,7 @@ func (rc *registryChain) GetModuleFile(ctx context.Context, moduleName, version
 		// Fast path: try the cached registry first.
 		moduleInfo, err := rc.clients[registryIdx].GetModuleFile(ctx, moduleName, version)
 		if err == nil {
-			return moduleInfo, nil
+			return servedBy(moduleInfo, registryIdx), nil
 		}
 
 		// If the cached registry can't serve this version, fallback to others.
@@ -150,7 +236,7 @@ func (rc *registryChain) GetModuleFile(ctx context.Context, moduleName, version
 			}
 			moduleInfo, err := client.GetModuleFile(ctx, moduleName, version)
 			if err == nil {
-				return moduleInfo, nil
+				return servedBy(moduleInfo, i), nil
 			}
 			notFoundErrors = append(notFoundErrors, fmt.Sprintf("%s: %v", client.BaseURL(), err))
 		}
@@ -173,7 +259,7 @@ func (rc *registryChain) GetModuleFile(ctx context.Context, moduleName, version
 				rc.moduleRegistry[moduleName] = i
 			}
 			rc.moduleRegistryMu.Unlock()
-			return moduleInfo, nil
+			return servedBy(moduleInfo, i), nil
 		}
 
 		// Check if it's a 404 (module not found in this registry)
@@ -202,8 +288,28 @@ func (rc *registryChain) GetModuleFile(ctx context.Context, moduleName, version
 		moduleName, version, strings.Join(notFoundErrors, "\n  - "))
 }
 
+// servedBy returns a copy of info recording that the registry at index idx
+// of the chain served it. The client's cached ModuleInfo is left untouched.
+func servedBy(info *ModuleInfo, idx int) *ModuleInfo {
+	served := *info
+	served.RegistryIndex = idx
+	return &served
+}
+
 // GetModuleMetadata fetches metadata using the registry that provides this module.
 func (rc *registryChain) GetModuleMetadata(ctx context.Context, moduleName string) (*registry.Metadata, error) {
+	if idx, pinned, err := rc.pinnedClient(moduleName); pinned {
+		if err != nil {
+			return nil, err
+		}
+		metadata, err := rc.clients[idx].GetModuleMetadata(ctx, moduleName)
+		if err != nil {
+			return nil, &PinnedRegistryError{Module: moduleName, File: "metadata.json", Registry: rc.clients[idx].BaseURL(), Err: err}
+		}
+		rc.rememberRegistry(moduleName, idx)
+		return metadata, nil
+	}
+
 	// Check if we've already determined which registry provides this module
 	rc.moduleRegistryMu.RLock()
 	registryIdx, found := rc.moduleRegistry[moduleName]
@@ -288,6 +394,53 @@ func (rc *registryChain) GetRegistryForModule(moduleName string) string {
 	return ""
 }
 
+// RegistryStatus reports whether a registry of a chain could be reached.
+type RegistryStatus struct {
+	// URL is the registry's base URL.
+	URL string
+
+	// Index is the registry's position in the chain.
+	Index int
+
+	// Reachable is true if the registry answered the probe.
+	Reachable bool
+
+	// Err is the reason the registry is unreachable, or nil.
+	Err error
+}
+
+// registryProber is implemented by registries that can check they are
+// reachable without fetching a module.
+type registryProber interface {
+	probe(ctx context.Context) error
+}
+
+// Probe checks every registry of the chain, in order, and reports which are
+// reachable. Registries are probed concurrently. A registry that cannot be
+// probed, such as a custom Registry implementation, is reported reachable,
+// since the chain will try it anyway.
+func (rc *registryChain) Probe(ctx context.Context) []RegistryStatus {
+	statuses := make([]RegistryStatus, len(rc.clients))
+	var wg sync.WaitGroup
+	for i, client := range rc.clients {
+		statuses[i] = RegistryStatus{URL: client.BaseURL(), Index: i, Reachable: true}
+		prober, ok := client.(registryProber)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := prober.probe(ctx); err != nil {
+				statuses[i].Reachable = false
+				statuses[i].Err = err
+			}
+		}()
+	}
+	wg.Wait()
+	return statuses
+}
+
 func (rc *registryChain) registryFileHashesSnapshot() map[string]*string {
 	if rc.trace != nil {
 		return rc.trace.snapshot()
@@ -310,6 +463,17 @@ func (rc *registryChain) registryFileTrace() *registryFileTrace {
 
 // GetModuleSource fetches source.json using the registry that provides this module.
 func (rc *registryChain) GetModuleSource(ctx context.Context, moduleName, version string) (*registry.Source, error) {
+	if idx, pinned, err := rc.pinnedClient(moduleName); pinned {
+		if err != nil {
+			return nil, err
+		}
+		source, err := rc.clients[idx].GetModuleSource(ctx, moduleName, version)
+		if err != nil {
+			return nil, &PinnedRegistryError{Module: moduleName, Version: version, File: "source.json", Registry: rc.clients[idx].BaseURL(), Err: err}
+		}
+		return source, nil
+	}
+
 	// Check if we've already determined which registry provides this module
 	rc.moduleRegistryMu.RLock()
 	registryIdx, found := rc.moduleRegistry[moduleName]
@@ -2,9 +2,12 @@ package gobzlmod
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -524,3 +527,135 @@ func TestRegistryChain_CachedRegistrySourceMissFallsBack(t *testing.T) {
 		t.Fatalf("GetModuleSource().URL = %q, want %q", source.URL, "https://example.com/module_src-1.0.0.tar.gz")
 	}
 }
+
+func TestRegistryChain_Probe(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/module_a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "module_a", version = "1.0.0")`)
+		default:
+			// A registry without bazel_registry.json is still reachable.
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer healthy.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+	localDir := filepath.Join(t.TempDir(), "registry")
+	if err := os.Mkdir(localDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	chain, err := newRegistryChain([]string{broken.URL, "file://" + filepath.ToSlash(localDir), healthy.URL})
+	if err != nil {
+		t.Fatalf("newRegistryChain() error = %v", err)
+	}
+	// The local registry goes away after the chain was created.
+	if err := os.Remove(localDir); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses := chain.Probe(context.Background())
+	if len(statuses) != 3 {
+		t.Fatalf("Probe() returned %d statuses, want 3", len(statuses))
+	}
+	for i, want := range []bool{false, false, true} {
+		s := statuses[i]
+		if s.Index != i || s.Reachable != want || (s.Err == nil) != want {
+			t.Errorf("statuses[%d] = %+v, want Reachable = %v", i, s, want)
+		}
+	}
+	if statuses[0].URL != broken.URL {
+		t.Errorf("statuses[0].URL = %q, want %q", statuses[0].URL, broken.URL)
+	}
+
+	info, err := chain.GetModuleFile(context.Background(), "module_a", "1.0.0")
+	if err != nil {
+		t.Fatalf("GetModuleFile() error = %v", err)
+	}
+	if info.RegistryIndex != 2 {
+		t.Errorf("RegistryIndex = %d, want 2", info.RegistryIndex)
+	}
+}
+
+func TestResolve_ModuleRegistries(t *testing.T) {
+	// moduleServer serves each module at 1.0.0 with the given bazel_deps.
+	moduleServer := func(modules map[string][]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for name, deps := range modules {
+				if r.URL.Path == "/modules/"+name+"/1.0.0/MODULE.bazel" {
+					fmt.Fprintf(w, "module(name = %q, version = \"1.0.0\")\n", name)
+					for _, dep := range deps {
+						fmt.Fprintf(w, "bazel_dep(name = %q, version = \"1.0.0\")\n", dep)
+					}
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+	}
+	private := moduleServer(map[string][]string{"corp_lib": nil})
+	defer private.Close()
+	// The public registry also has corp names, as an attacker could publish.
+	public := moduleServer(map[string][]string{
+		"corp_lib":  nil,
+		"corp_tool": nil,
+		"rules_cc":  nil,
+		"wrapper":   {"corp_tool"},
+	})
+	defer public.Close()
+
+	resolve := func(deps ...string) (*ResolutionList, error) {
+		content := `module(name = "root", version = "1.0.0")` + "\n"
+		for _, dep := range deps {
+			content += fmt.Sprintf("bazel_dep(name = %q, version = \"1.0.0\")\n", dep)
+		}
+		return Resolve(context.Background(), ContentSource(content),
+			WithRegistries(public.URL, private.URL),
+			WithModuleRegistries(map[string]string{"corp_*": private.URL + "/"}))
+	}
+
+	list, err := resolve("corp_lib", "rules_cc")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	for _, m := range list.Modules {
+		want := public.URL
+		if m.Name == "corp_lib" {
+			want = private.URL
+		}
+		if m.Registry != want {
+			t.Errorf("%s registry = %q, want %q", m.Name, m.Registry, want)
+		}
+	}
+
+	// A pinned miss fails resolution even for a transitive dependency, which
+	// would otherwise be dropped as not found.
+	for _, dep := range []string{"corp_tool", "wrapper"} {
+		_, err := resolve(dep)
+		var pinnedErr *PinnedRegistryError
+		if !errors.As(err, &pinnedErr) {
+			t.Errorf("Resolve(%s) error = %v, want *PinnedRegistryError", dep, err)
+			continue
+		}
+		if pinnedErr.Module != "corp_tool" || pinnedErr.Registry != private.URL {
+			t.Errorf("Resolve(%s) PinnedRegistryError = %+v, want corp_tool pinned to %s", dep, pinnedErr, private.URL)
+		}
+	}
+
+	// The pins also apply to the root module of a registry resolution.
+	_, err = Resolve(context.Background(), RegistrySource{Name: "corp_tool", Version: "1.0.0"},
+		WithRegistries(public.URL, private.URL),
+		WithModuleRegistries(map[string]string{"corp_*": private.URL}))
+	var pinnedErr *PinnedRegistryError
+	if !errors.As(err, &pinnedErr) {
+		t.Errorf("Resolve(RegistrySource) of a pinned module missing from its registry error = %v, want *PinnedRegistryError", err)
+	}
+
+	if _, err := Resolve(context.Background(), ContentSource(`module(name = "root")`),
+		WithModuleRegistries(map[string]string{"corp_[": private.URL})); err == nil {
+		t.Error("WithModuleRegistries() with a malformed pattern succeeded, want error")
+	}
+}
@@ -100,6 +100,18 @@ func (r *localRegistry) BaseURL() string {
 	return "file://" + urlPath
 }
 
+// probe checks that the registry's root directory exists.
+func (r *localRegistry) probe(ctx context.Context) error {
+	info, err := os.Stat(r.rootPath)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", r.rootPath)
+	}
+	return nil
+}
+
 // GetModuleFile reads a MODULE.bazel file from the local registry.
 func (r *localRegistry) GetModuleFile(ctx context.Context, moduleName, version string) (*ModuleInfo, error) {
 	cacheKey := moduleName + "@" + version
@@ -2,11 +2,14 @@ package gobzlmod
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -32,6 +35,11 @@ func Test_newRegistryClient(t *testing.T) {
 			registryURL: "https://bcr.bazel.build/",
 			wantURL:     "https://bcr.bazel.build",
 		},
+		{
+			name:        "URL with two trailing slashes",
+			registryURL: "https://bcr.bazel.build//",
+			wantURL:     "https://bcr.bazel.build",
+		},
 	}
 
 	for _, tt := range tests {
@@ -330,6 +338,90 @@ func TestGetModuleFile_MirrorNotUsedFor404(t *testing.T) {
 	}
 }
 
+func TestResolve_Retry(t *testing.T) {
+	var flaky, down, missing atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/flaky/1.0.0/MODULE.bazel":
+			if flaky.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, `module(name = "flaky", version = "1.0.0")`)
+		case "/modules/down/1.0.0/MODULE.bazel":
+			down.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		case "/modules/missing/1.0.0/MODULE.bazel":
+			missing.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolve := func(dep string, opts ...Option) (*ResolutionList, error) {
+		content := fmt.Sprintf("module(name = \"root\", version = \"1.0.0\")\nbazel_dep(name = %q, version = \"1.0.0\")", dep)
+		return Resolve(context.Background(), ContentSource(content), append(opts, WithRegistries(server.URL))...)
+	}
+
+	// 5xx responses are retried until they succeed.
+	if _, err := resolve("flaky", WithRetry(4, time.Millisecond)); err != nil {
+		t.Fatalf("Resolve() with retries error = %v", err)
+	}
+	if got := flaky.Load(); got != 3 {
+		t.Errorf("flaky requests = %d, want 3", got)
+	}
+
+	// Without WithRetry, a 5xx fails immediately.
+	if _, err := resolve("down"); err == nil {
+		t.Fatal("Resolve() of a failing module succeeded")
+	}
+	if got := down.Swap(0); got != 1 {
+		t.Errorf("requests without retries = %d, want 1", got)
+	}
+
+	// The retries performed are reported once they run out.
+	_, err := resolve("down", WithRetry(3, time.Millisecond))
+	var regErr *RegistryError
+	if !errors.As(err, &regErr) || regErr.StatusCode != http.StatusBadGateway || regErr.Retries != 2 {
+		t.Errorf("Resolve() error = %v, want a 502 RegistryError after 2 retries", err)
+	}
+	if got := down.Load(); got != 3 {
+		t.Errorf("requests with retries = %d, want 3", got)
+	}
+
+	// 4xx responses are never retried.
+	if _, err := resolve("missing", WithRetry(3, time.Millisecond)); err == nil {
+		t.Fatal("Resolve() of a missing root dependency succeeded")
+	}
+	if got := missing.Load(); got != 1 {
+		t.Errorf("404 requests = %d, want 1", got)
+	}
+}
+
+func TestRegistryClient_RetryStopsAtDeadline(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := withRetry(newRegistryClient(server.URL), 10, time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := client.GetModuleFile(ctx, "any", "1.0.0"); err == nil {
+		t.Fatal("GetModuleFile() succeeded against a failing registry")
+	}
+	// bazel_registry.json plus a single MODULE.bazel attempt: the backoff
+	// would outlive the deadline, so no retry is made.
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
 func TestGetModuleFile_ContextTimeout(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Simulate slow response
@@ -470,6 +562,91 @@ func TestURLConstruction(t *testing.T) {
 	}
 }
 
+// TestRegistryClient_URLJoining checks that base URLs with any number of
+// trailing slashes, and a module_base_path with surrounding slashes, produce
+// exactly one slash between path segments for every endpoint.
+func TestRegistryClient_URLJoining(t *testing.T) {
+	for _, basePath := range []string{"", "/modules/"} {
+		var (
+			mu    sync.Mutex
+			paths []string
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			paths = append(paths, r.URL.Path)
+			mu.Unlock()
+			switch {
+			case r.URL.Path == "/bazel_registry.json":
+				fmt.Fprintf(w, `{"module_base_path": %q}`, basePath)
+			case strings.HasSuffix(r.URL.Path, "metadata.json"):
+				fmt.Fprint(w, `{"versions": ["1.0.0"]}`)
+			case strings.HasSuffix(r.URL.Path, "source.json"):
+				fmt.Fprint(w, `{"url": "https://example.com/a.zip", "integrity": "sha256-AAAA"}`)
+			default:
+				fmt.Fprint(w, `module(name = "a", version = "1.0.0")`)
+			}
+		}))
+
+		endpoints := []struct {
+			name     string
+			call     func(ctx context.Context, c *registryClient) error
+			wantPath string
+		}{
+			{
+				name: "metadata",
+				call: func(ctx context.Context, c *registryClient) error {
+					_, err := c.GetModuleMetadata(ctx, "a")
+					return err
+				},
+				wantPath: "/modules/a/metadata.json",
+			},
+			{
+				name: "source",
+				call: func(ctx context.Context, c *registryClient) error {
+					_, err := c.GetModuleSource(ctx, "a", "1.0.0")
+					return err
+				},
+				wantPath: "/modules/a/1.0.0/source.json",
+			},
+			{
+				name: "module",
+				call: func(ctx context.Context, c *registryClient) error {
+					_, err := c.GetModuleFile(ctx, "a", "1.0.0")
+					return err
+				},
+				wantPath: "/modules/a/1.0.0/MODULE.bazel",
+			},
+		}
+
+		for _, suffix := range []string{"", "/", "//"} {
+			for _, ep := range endpoints {
+				t.Run(fmt.Sprintf("base_path_%q/%s/%d_slashes", basePath, ep.name, len(suffix)), func(t *testing.T) {
+					mu.Lock()
+					paths = nil
+					mu.Unlock()
+
+					c := newRegistryClient(server.URL + suffix)
+					if err := ep.call(context.Background(), c); err != nil {
+						t.Fatalf("request failed: %v", err)
+					}
+
+					mu.Lock()
+					defer mu.Unlock()
+					if !slices.Contains(paths, ep.wantPath) {
+						t.Errorf("requested paths = %v, want %s", paths, ep.wantPath)
+					}
+					for _, p := range paths {
+						if strings.Contains(p, "//") {
+							t.Errorf("requested path %q contains a double slash", p)
+						}
+					}
+				})
+			}
+		}
+		server.Close()
+	}
+}
+
 func TestCacheKeyGeneration(t *testing.T) {
 	// Test that different module/version combinations generate different cache keys
 	tests := []struct {
@@ -248,7 +248,32 @@ func enrichResolutionList(ctx context.Context, reg Registry, opts ResolutionOpti
 
 	if hashes := collectRegistryFileHashes(reg); len(hashes) > 0 {
 		list.RegistryFileHashes = hashes
+		attachModuleFileHashes(list)
 	}
 
 	return nil
 }
+
+// attachModuleFileHashes copies each entry of list.RegistryFileHashes for a
+// file under a selected module's /modules/<name>/<version>/ directory onto
+// that module's RegistryFileHashes.
+func attachModuleFileHashes(list *ResolutionList) {
+	byKey := make(map[string]*ModuleToResolve, len(list.Modules))
+	for i := range list.Modules {
+		byKey[list.Modules[i].Key()] = &list.Modules[i]
+	}
+	for url, hash := range list.RegistryFileHashes {
+		key, _, ok := registryModuleFile(url)
+		if !ok {
+			continue
+		}
+		module, ok := byKey[key]
+		if !ok {
+			continue
+		}
+		if module.RegistryFileHashes == nil {
+			module.RegistryFileHashes = make(map[string]*string)
+		}
+		module.RegistryFileHashes[url] = cloneStringPointer(hash)
+	}
+}
@@ -0,0 +1,145 @@
+package gobzlmod
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+)
+
+// Resolver resolves successive revisions of a root module, such as a
+// MODULE.bazel being edited in an IDE, reusing the module files fetched by
+// earlier resolutions. Unlike Resolve, which starts cold every time, only
+// module versions that no earlier resolution discovered are fetched.
+//
+// A Resolver is safe for concurrent use; resolutions are serialized.
+type Resolver struct {
+	mu       sync.Mutex
+	resolver *dependencyResolver
+
+	// modules maps "name@version" -> module file discovered by an earlier
+	// resolution, excluding modules the root overrode at the time.
+	modules map[string]*ModuleInfo
+}
+
+// NewResolver creates a Resolver configured with opts, which are applied to
+// every resolution. The registry clients, and so their caches, are shared by
+// all resolutions. With WithRegistryTrace, each result's trace lists the
+// registry files of that resolution only, as a cold Resolve would.
+func NewResolver(opts ...Option) (*Resolver, error) {
+	cfg, err := newResolverConfig(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	resOpts := cfg.toResolutionOptions()
+	return &Resolver{
+		resolver: newDependencyResolverWithOptions(registryFromOptions(resOpts), resOpts),
+		modules:  make(map[string]*ModuleInfo),
+	}, nil
+}
+
+// Reresolve resolves the dependency graph of newRoot. Module files discovered
+// by earlier calls are reused as seeds (see WithSeedModules), so after a small
+// edit to the root only the newly requested versions are fetched. The first
+// call resolves cold.
+//
+// Modules overridden by newRoot are always resolved afresh, so the result is
+// the same as a cold Resolve of newRoot with the same options. newRoot is used
+// as is: includes are not followed.
+func (r *Resolver) Reresolve(ctx context.Context, newRoot *ModuleInfo) (*ResolutionList, error) {
+	if newRoot == nil {
+		return nil, fmt.Errorf("root module is nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	overridden := make(map[string]bool, len(newRoot.Overrides))
+	for _, o := range newRoot.Overrides {
+		overridden[o.ModuleName] = true
+	}
+	seeds := maps.Clone(r.modules)
+	maps.DeleteFunc(seeds, func(_ string, info *ModuleInfo) bool {
+		return overridden[info.Name]
+	})
+
+	var (
+		discovered map[string]*ModuleInfo
+		missing    []string
+	)
+	r.resolver.overrideMu.Lock()
+	r.resolver.seededModules = seeds
+	r.resolver.moduleInfoSink = func(modules map[string]*ModuleInfo, missingKeys []string) {
+		discovered = modules
+		missing = missingKeys
+	}
+	r.resolver.overrideMu.Unlock()
+
+	result, err := r.resolver.ResolveDependencies(ctx, newRoot)
+	if err != nil {
+		return nil, err
+	}
+	if result.RegistryFileHashes != nil {
+		// The trace is shared with earlier resolutions, so keep only the files
+		// of the module versions this one discovered.
+		keys := make(map[string]bool, len(discovered)+len(missing))
+		for key := range discovered {
+			keys[key] = true
+		}
+		for _, key := range missing {
+			keys[key] = true
+		}
+		result.RegistryFileHashes = resolutionFileHashes(result.RegistryFileHashes, keys)
+		for i := range result.Modules {
+			result.Modules[i].RegistryFileHashes = nil
+		}
+		attachModuleFileHashes(result)
+	}
+	for key, info := range discovered {
+		if !overridden[info.Name] {
+			r.modules[key] = info
+		}
+	}
+	return result, nil
+}
+
+// resolutionFileHashes returns the entries of hashes that a resolution
+// discovering the "name@version" keys would have fetched: the files of those
+// module versions, the metadata.json of their modules, and files that belong
+// to no module, such as bazel_registry.json.
+func resolutionFileHashes(hashes map[string]*string, keys map[string]bool) map[string]*string {
+	names := make(map[string]bool, len(keys))
+	for key := range keys {
+		name, _, _ := strings.Cut(key, "@")
+		names[name] = true
+	}
+
+	filtered := make(map[string]*string, len(hashes))
+	for url, hash := range hashes {
+		if key, _, ok := registryModuleFile(url); ok {
+			if !keys[key] {
+				continue
+			}
+		} else if name, ok := registryMetadataFile(url); ok && !names[name] {
+			continue
+		}
+		filtered[url] = hash
+	}
+	return filtered
+}
+
+// registryMetadataFile returns the module name of a registry URL of the form
+// .../modules/<name>/metadata.json, or false if url has another form.
+func registryMetadataFile(url string) (string, bool) {
+	_, path, ok := strings.Cut(url, "/modules/")
+	if !ok {
+		return "", false
+	}
+	name, ok := strings.CutSuffix(path, "/metadata.json")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+	return name, true
+}
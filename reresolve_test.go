@@ -0,0 +1,125 @@
+package gobzlmod
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestResolver_Reresolve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "a", version = "1.0.0")
+bazel_dep(name = "c", version = "1.0.0")`)
+		case "/modules/b/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "b", version = "1.0.0")
+bazel_dep(name = "d", version = "1.0.0")`)
+		case "/modules/b/2.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "b", version = "2.0.0")
+bazel_dep(name = "d", version = "1.0.0")`)
+		case "/modules/c/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "c", version = "1.0.0")`)
+		case "/modules/d/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "d", version = "1.0.0")`)
+		case "/modules/a/1.0.0/source.json", "/modules/b/1.0.0/source.json", "/modules/b/2.0.0/source.json",
+			"/modules/c/1.0.0/source.json", "/modules/d/1.0.0/source.json":
+			fmt.Fprint(w, `{"url":"https://example.com/archive.tar.gz","integrity":"sha256-aaa"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var (
+		mu      sync.Mutex
+		fetched []string
+	)
+	progress := func(e ProgressEvent) {
+		if e.Type == ProgressModuleFetchStart {
+			mu.Lock()
+			fetched = append(fetched, e.Module+"@"+e.Version)
+			mu.Unlock()
+		}
+	}
+	takeFetched := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		got := fetched
+		fetched = nil
+		return got
+	}
+
+	parse := func(content string) *ModuleInfo {
+		t.Helper()
+		info, err := ParseModuleContent(content)
+		if err != nil {
+			t.Fatalf("ParseModuleContent() error = %v", err)
+		}
+		return info
+	}
+	before := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "a", version = "1.0.0")
+bazel_dep(name = "b", version = "1.0.0")`
+	after := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "a", version = "1.0.0")
+bazel_dep(name = "b", version = "2.0.0")`
+
+	resolver, err := NewResolver(WithRegistries(server.URL), WithDeterministic(), WithRegistryTrace(), WithProgress(progress))
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+
+	if _, err := resolver.Reresolve(context.Background(), parse(before)); err != nil {
+		t.Fatalf("Reresolve() cold error = %v", err)
+	}
+	if got := takeFetched(); len(got) != 4 {
+		t.Fatalf("cold resolution fetched %v, want 4 modules", got)
+	}
+
+	incremental, err := resolver.Reresolve(context.Background(), parse(after))
+	if err != nil {
+		t.Fatalf("Reresolve() error = %v", err)
+	}
+	got := takeFetched()
+	if !reflect.DeepEqual(got, []string{"b@2.0.0"}) {
+		t.Errorf("re-resolution fetched %v, want only [b@2.0.0]", got)
+	}
+	if hits := len(incremental.Modules) - len(got); hits <= len(got) {
+		t.Errorf("re-resolution reused %d module files and fetched %d, want reuse to dominate", hits, len(got))
+	}
+
+	cold, err := Resolve(context.Background(), ContentSource(after), WithRegistries(server.URL), WithDeterministic(), WithRegistryTrace())
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if !reflect.DeepEqual(incremental.Modules, cold.Modules) {
+		t.Errorf("re-resolution modules differ from a cold resolve:\n got %+v\nwant %+v", incremental.Modules, cold.Modules)
+	}
+	if len(cold.RegistryFileHashes) == 0 {
+		t.Fatal("cold resolve recorded no registry file hashes")
+	}
+	if !reflect.DeepEqual(incremental.RegistryFileHashes, cold.RegistryFileHashes) {
+		t.Errorf("re-resolution registry file hashes differ from a cold resolve:\n got %v\nwant %v",
+			slices.Sorted(maps.Keys(incremental.RegistryFileHashes)), slices.Sorted(maps.Keys(cold.RegistryFileHashes)))
+	}
+	if !reflect.DeepEqual(incremental.Summary, cold.Summary) {
+		t.Errorf("re-resolution summary = %+v, want %+v", incremental.Summary, cold.Summary)
+	}
+}
+
+func TestResolver_ReresolveNilRoot(t *testing.T) {
+	resolver, err := NewResolver(WithRegistries("http://127.0.0.1:0"))
+	if err != nil {
+		t.Fatalf("NewResolver() error = %v", err)
+	}
+	if _, err := resolver.Reresolve(context.Background(), nil); err == nil {
+		t.Error("Reresolve(nil) succeeded, want error")
+	}
+}
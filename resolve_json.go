@@ -0,0 +1,166 @@
+package gobzlmod
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// Error kinds reported in ErrorInfo.Kind.
+const (
+	ErrorKindRegistry             = "registry"
+	ErrorKindRegistryInconsistent = "registry_inconsistent"
+	ErrorKindYankedVersions       = "yanked_versions"
+	ErrorKindDirectDepsMismatch   = "direct_deps_mismatch"
+	ErrorKindBazelIncompatibility = "bazel_incompatibility"
+	ErrorKindMaxDepthExceeded     = "max_depth_exceeded"
+	ErrorKindDependencyRejected   = "dependency_rejected"
+	ErrorKindResolutionTimeout    = "resolution_timeout"
+	ErrorKindIncludeCycle         = "include_cycle"
+	ErrorKindCanceled             = "canceled"
+	ErrorKindDeadlineExceeded     = "deadline_exceeded"
+	ErrorKindInternal             = "internal"
+	ErrorKindOther                = "error"
+)
+
+// ErrorInfo is a machine-readable description of a resolution error.
+// Kind identifies the error type; the remaining fields are filled in when the
+// underlying error carries them.
+type ErrorInfo struct {
+	// Kind is one of the ErrorKind constants.
+	Kind string `json:"kind"`
+
+	// Message is the full error message.
+	Message string `json:"message"`
+
+	// Module and Version identify the module the error is about, if any.
+	Module  string `json:"module,omitempty"`
+	Version string `json:"version,omitempty"`
+
+	// URL is the registry URL involved in a registry error.
+	URL string `json:"url,omitempty"`
+
+	// StatusCode is the HTTP status of a registry error.
+	StatusCode int `json:"status_code,omitempty"`
+
+	// Modules lists the name@version keys of all modules involved, for errors
+	// that concern several modules (e.g. yanked or incompatible versions).
+	Modules []string `json:"modules,omitempty"`
+
+	// Path is the dependency path for depth errors, or the chain of include
+	// labels for include cycles.
+	Path []string `json:"path,omitempty"`
+
+	// ModulesResolved is how many module files had been resolved when
+	// TotalTimeout ran out.
+	ModulesResolved int `json:"modules_resolved,omitempty"`
+}
+
+// NewErrorInfo converts err into an ErrorInfo, extracting structured details
+// from the error types returned by resolution. Returns nil if err is nil.
+func NewErrorInfo(err error) *ErrorInfo {
+	if err == nil {
+		return nil
+	}
+	info := &ErrorInfo{Kind: ErrorKindOther, Message: err.Error()}
+
+	var (
+		inconsistentErr *RegistryInconsistencyError
+		registryErr     *RegistryError
+		yankedErr       *YankedVersionsError
+		directDepsErr   *DirectDepsMismatchError
+		incompatibleErr *BazelIncompatibilityError
+		depthErr        *MaxDepthExceededError
+		rejectedErr     *DependencyRejectedError
+		timeoutErr      *ResolutionTimeoutError
+		cycleErr        *IncludeCycleError
+	)
+	switch {
+	// Checked first: it wraps the error of the interrupted step, which may
+	// itself be a registry or deadline error.
+	case errors.As(err, &timeoutErr):
+		info.Kind = ErrorKindResolutionTimeout
+		info.ModulesResolved = timeoutErr.ModulesResolved
+	case errors.As(err, &inconsistentErr):
+		info.Kind = ErrorKindRegistryInconsistent
+		info.Module = inconsistentErr.Module
+		info.Version = inconsistentErr.Version
+		if errors.As(err, &registryErr) {
+			info.URL = registryErr.URL
+			info.StatusCode = registryErr.StatusCode
+		}
+	case errors.As(err, &registryErr):
+		info.Kind = ErrorKindRegistry
+		info.Module = registryErr.ModuleName
+		info.Version = registryErr.Version
+		info.URL = registryErr.URL
+		info.StatusCode = registryErr.StatusCode
+	case errors.As(err, &yankedErr):
+		info.Kind = ErrorKindYankedVersions
+		info.Modules = moduleKeys(yankedErr.Modules)
+	case errors.As(err, &directDepsErr):
+		info.Kind = ErrorKindDirectDepsMismatch
+		for _, m := range directDepsErr.Mismatches {
+			info.Modules = append(info.Modules, m.Name+"@"+m.ResolvedVersion)
+		}
+	case errors.As(err, &incompatibleErr):
+		info.Kind = ErrorKindBazelIncompatibility
+		info.Modules = moduleKeys(incompatibleErr.Modules)
+	case errors.As(err, &cycleErr):
+		info.Kind = ErrorKindIncludeCycle
+		info.Path = cycleErr.Chain
+	case errors.As(err, &depthErr):
+		info.Kind = ErrorKindMaxDepthExceeded
+		info.Path = depthErr.Path
+	case errors.As(err, &rejectedErr):
+		info.Kind = ErrorKindDependencyRejected
+		info.Module = rejectedErr.Module
+		info.Version = rejectedErr.Version
+	case errors.Is(err, context.Canceled):
+		info.Kind = ErrorKindCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		info.Kind = ErrorKindDeadlineExceeded
+	}
+	return info
+}
+
+func moduleKeys(modules []ModuleToResolve) []string {
+	keys := make([]string, len(modules))
+	for i, m := range modules {
+		keys[i] = m.Key()
+	}
+	return keys
+}
+
+// ResolveEnvelope is the JSON document produced by ResolveJSON.
+// Exactly one of Result and Error is set, according to OK.
+type ResolveEnvelope struct {
+	OK     bool            `json:"ok"`
+	Result *ResolutionList `json:"result,omitempty"`
+	Error  *ErrorInfo      `json:"error,omitempty"`
+}
+
+// ResolveJSON resolves MODULE.bazel content and encodes the outcome as a JSON
+// envelope, either {"ok":true,"result":{...}} or {"ok":false,"error":{...}}.
+//
+// It never fails: every error, including invalid options and parse errors, is
+// reported inside the envelope. This makes it suitable for tools that run
+// go-bzlmod as a subprocess and consume its output.
+func ResolveJSON(ctx context.Context, content string, opts ...Option) []byte {
+	result, err := Resolve(ctx, ContentSource(content), opts...)
+	if err != nil {
+		return marshalErrorEnvelope(NewErrorInfo(err))
+	}
+
+	data, err := json.Marshal(ResolveEnvelope{OK: true, Result: result})
+	if err != nil {
+		return marshalErrorEnvelope(&ErrorInfo{Kind: ErrorKindInternal, Message: "encode result: " + err.Error()})
+	}
+	return data
+}
+
+func marshalErrorEnvelope(info *ErrorInfo) []byte {
+	// ErrorInfo holds only strings, ints and string slices, so this cannot fail.
+	data, _ := json.Marshal(ResolveEnvelope{Error: info})
+	return data
+}
@@ -0,0 +1,192 @@
+package gobzlmod
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/dep_a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "dep_a", version = "1.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		content  string
+		opts     []Option
+		wantOK   bool
+		wantKind string
+	}{
+		{
+			name: "success",
+			content: `module(name = "root", version = "1.0.0")
+bazel_dep(name = "dep_a", version = "1.0.0")`,
+			opts:   []Option{WithRegistries(server.URL)},
+			wantOK: true,
+		},
+		{
+			name: "missing dependency",
+			content: `module(name = "root", version = "1.0.0")
+bazel_dep(name = "missing", version = "1.0.0")`,
+			opts:     []Option{WithRegistries(server.URL)},
+			wantKind: ErrorKindRegistry,
+		},
+		{
+			name:     "parse error",
+			content:  `bazel_dep(`,
+			opts:     []Option{WithRegistries(server.URL)},
+			wantKind: ErrorKindOther,
+		},
+		{
+			name:     "invalid options",
+			content:  `module(name = "root", version = "1.0.0")`,
+			opts:     []Option{WithTimeout(-1)},
+			wantKind: ErrorKindOther,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := ResolveJSON(context.Background(), tt.content, tt.opts...)
+
+			var raw map[string]json.RawMessage
+			if err := json.Unmarshal(data, &raw); err != nil {
+				t.Fatalf("envelope is not JSON: %v\n%s", err, data)
+			}
+			var env ResolveEnvelope
+			if err := json.Unmarshal(data, &env); err != nil {
+				t.Fatalf("unmarshal envelope: %v", err)
+			}
+
+			if env.OK != tt.wantOK {
+				t.Fatalf("ok = %v, want %v: %s", env.OK, tt.wantOK, data)
+			}
+			if tt.wantOK {
+				if _, ok := raw["error"]; ok {
+					t.Errorf("successful envelope has error: %s", data)
+				}
+				if env.Result == nil || !env.Result.HasModule("dep_a") {
+					t.Errorf("result = %s, want dep_a resolved", raw["result"])
+				}
+				return
+			}
+
+			if _, ok := raw["result"]; ok {
+				t.Errorf("failed envelope has result: %s", data)
+			}
+			if env.Error == nil {
+				t.Fatalf("failed envelope has no error: %s", data)
+			}
+			if env.Error.Kind != tt.wantKind {
+				t.Errorf("error.kind = %q, want %q", env.Error.Kind, tt.wantKind)
+			}
+			if env.Error.Message == "" {
+				t.Error("error.message is empty")
+			}
+		})
+	}
+}
+
+func TestResolveJSON_RegistryErrorDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	data := ResolveJSON(context.Background(), `module(name = "root", version = "1.0.0")
+bazel_dep(name = "missing", version = "1.0.0")`, WithRegistries(server.URL))
+
+	var env ResolveEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		t.Fatalf("unmarshal envelope: %v", err)
+	}
+	if env.Error == nil || env.Error.StatusCode != http.StatusNotFound || env.Error.Module != "missing" || env.Error.Version != "1.0.0" {
+		t.Errorf("error = %+v, want 404 for missing@1.0.0", env.Error)
+	}
+	if !strings.Contains(string(data), `"ok":false`) {
+		t.Errorf("envelope = %s, want ok:false", data)
+	}
+}
+
+func TestNewErrorInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorInfo
+	}{
+		{
+			name: "yanked",
+			err:  fmt.Errorf("resolve: %w", &YankedVersionsError{Modules: []ModuleToResolve{{Name: "a", Version: "1.0.0", YankReason: "bad"}}}),
+			want: ErrorInfo{Kind: ErrorKindYankedVersions, Modules: []string{"a@1.0.0"}},
+		},
+		{
+			name: "max depth",
+			err:  &MaxDepthExceededError{Depth: 3, MaxDepth: 2, Path: []string{"<root>", "a@1", "b@1"}},
+			want: ErrorInfo{Kind: ErrorKindMaxDepthExceeded, Path: []string{"<root>", "a@1", "b@1"}},
+		},
+		{
+			name: "include cycle",
+			err:  fmt.Errorf("process includes: %w", &IncludeCycleError{Chain: []string{"//a:a.MODULE.bazel", "//b:b.MODULE.bazel", "//a:a.MODULE.bazel"}}),
+			want: ErrorInfo{Kind: ErrorKindIncludeCycle, Path: []string{"//a:a.MODULE.bazel", "//b:b.MODULE.bazel", "//a:a.MODULE.bazel"}},
+		},
+		{
+			name: "dependency rejected",
+			err:  &DependencyRejectedError{Module: "b", Version: "1.0.0", Requester: "a@1.0.0"},
+			want: ErrorInfo{Kind: ErrorKindDependencyRejected, Module: "b", Version: "1.0.0"},
+		},
+		{
+			name: "registry inconsistency",
+			err: &RegistryInconsistencyError{Module: "foo", Version: "2.0.0",
+				Err: &RegistryError{StatusCode: 404, ModuleName: "foo", Version: "2.0.0", URL: "https://example.com/modules/foo/2.0.0/MODULE.bazel"}},
+			want: ErrorInfo{Kind: ErrorKindRegistryInconsistent, Module: "foo", Version: "2.0.0",
+				URL: "https://example.com/modules/foo/2.0.0/MODULE.bazel", StatusCode: 404},
+		},
+		{
+			name: "resolution timeout",
+			err: fmt.Errorf("resolve: %w", &ResolutionTimeoutError{Timeout: time.Second, ModulesResolved: 3,
+				Err: fmt.Errorf("fetch module foo@1.0.0: %w", context.DeadlineExceeded)}),
+			want: ErrorInfo{Kind: ErrorKindResolutionTimeout, ModulesResolved: 3},
+		},
+		{
+			name: "canceled",
+			err:  fmt.Errorf("fetch: %w", context.Canceled),
+			want: ErrorInfo{Kind: ErrorKindCanceled},
+		},
+		{
+			name: "plain",
+			err:  errors.New("boom"),
+			want: ErrorInfo{Kind: ErrorKindOther},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewErrorInfo(tt.err)
+			if got.Kind != tt.want.Kind || got.Module != tt.want.Module || got.Version != tt.want.Version ||
+				got.URL != tt.want.URL || got.ModulesResolved != tt.want.ModulesResolved || !slices.Equal(got.Modules, tt.want.Modules) || !slices.Equal(got.Path, tt.want.Path) {
+				t.Errorf("NewErrorInfo() = %+v, want %+v", got, tt.want)
+			}
+			if got.Message != tt.err.Error() {
+				t.Errorf("Message = %q, want %q", got.Message, tt.err.Error())
+			}
+		})
+	}
+
+	if NewErrorInfo(nil) != nil {
+		t.Error("NewErrorInfo(nil) != nil")
+	}
+}
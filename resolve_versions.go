@@ -0,0 +1,39 @@
+package gobzlmod
+
+import (
+	"context"
+	"fmt"
+)
+
+// ResolveForBazelVersions resolves the same MODULE.bazel content once per
+// Bazel version and returns the results keyed by version, to show how
+// resolution differs between Bazel releases.
+//
+// Each resolution applies opts followed by WithBazelVersion, so the version
+// gated behavior of WithBazelVersion differs between results: the
+// MODULE.tools dependencies Bazel injects, bazel_compatibility checks (when
+// WithBazelCompatibilityMode is set) and Summary.FieldWarnings for fields the
+// version does not support. Unless opts set a cache, the resolutions share an
+// in-memory module cache so each MODULE.bazel is fetched once.
+//
+// Resolution stops at the first version that fails.
+func ResolveForBazelVersions(ctx context.Context, content string, versions []string, opts ...Option) (map[string]*ResolutionList, error) {
+	// A caller's WithCache in opts comes later and replaces the shared cache.
+	shared := append([]Option{WithCache(NewMemoryCache())}, opts...)
+
+	results := make(map[string]*ResolutionList, len(versions))
+	for _, version := range versions {
+		if version == "" {
+			return nil, fmt.Errorf("bazel version is empty")
+		}
+		if _, done := results[version]; done {
+			continue
+		}
+		list, err := Resolve(ctx, ContentSource(content), append(shared[:len(shared):len(shared)], WithBazelVersion(version))...)
+		if err != nil {
+			return nil, fmt.Errorf("resolve for Bazel %s: %w", version, err)
+		}
+		results[version] = list
+	}
+	return results, nil
+}
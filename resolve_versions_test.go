@@ -0,0 +1,61 @@
+package gobzlmod
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestResolveForBazelVersions(t *testing.T) {
+	var libFetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/lib/2.0.0/MODULE.bazel":
+			libFetches.Add(1)
+			fmt.Fprint(w, `module(name = "lib", version = "2.0.0", compatibility_level = 2)`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "lib", version = "2.0.0", max_compatibility_level = 3)`
+
+	results, err := ResolveForBazelVersions(context.Background(), content, []string{"6.4.0", "7.4.0"},
+		WithRegistries(server.URL))
+	if err != nil {
+		t.Fatalf("ResolveForBazelVersions() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+
+	// max_compatibility_level is only understood by Bazel 7+.
+	bazel6 := results["6.4.0"].Summary.FieldWarnings
+	if len(bazel6) != 1 || !strings.Contains(bazel6[0], "max_compatibility_level") {
+		t.Errorf("Bazel 6.4.0 FieldWarnings = %v, want a max_compatibility_level warning", bazel6)
+	}
+	if bazel7 := results["7.4.0"].Summary.FieldWarnings; len(bazel7) != 0 {
+		t.Errorf("Bazel 7.4.0 FieldWarnings = %v, want none", bazel7)
+	}
+
+	for version, list := range results {
+		if m := list.Module("lib"); m == nil || m.Version != "2.0.0" {
+			t.Errorf("Bazel %s: lib = %+v, want 2.0.0", version, m)
+		}
+	}
+	if n := libFetches.Load(); n != 1 {
+		t.Errorf("lib MODULE.bazel fetched %d times, want 1 (shared cache)", n)
+	}
+}
+
+func TestResolveForBazelVersions_EmptyVersion(t *testing.T) {
+	if _, err := ResolveForBazelVersions(context.Background(), `module(name = "root")`, []string{""}); err == nil {
+		t.Error("ResolveForBazelVersions() with an empty version: expected error")
+	}
+}
@@ -0,0 +1,65 @@
+package gobzlmod
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// OutputSpec names the files ResolveAndWrite writes. Empty paths are skipped.
+type OutputSpec struct {
+	// Lockfile is the path of a MODULE.bazel.lock to write.
+	Lockfile string
+
+	// GraphJSON is the path of a `bazel mod graph --output=json` style file.
+	GraphJSON string
+
+	// GraphDOT is the path of a Graphviz DOT file.
+	GraphDOT string
+}
+
+// graphFilePermissions is the mode of graph files written by ResolveAndWrite.
+const graphFilePermissions = 0o644
+
+// ResolveAndWrite resolves MODULE.bazel content once and writes every artifact
+// named in outputs from that single result, so the lockfile and graph files
+// always describe the same resolution.
+//
+// When a lockfile is requested, registry file tracing is enabled so that
+// registryFileHashes is populated (see WithRegistryTrace). Files are written
+// in the order lockfile, graph JSON, graph DOT; the first write error stops
+// the remaining writes.
+func ResolveAndWrite(ctx context.Context, content string, outputs OutputSpec, opts ...Option) error {
+	if outputs.Lockfile != "" {
+		opts = append(opts[:len(opts):len(opts)], WithRegistryTrace())
+	}
+
+	result, err := Resolve(ctx, ContentSource(content), opts...)
+	if err != nil {
+		return err
+	}
+
+	if outputs.Lockfile != "" {
+		if err := result.ToLockfile().WriteFile(outputs.Lockfile); err != nil {
+			return fmt.Errorf("write lockfile: %w", err)
+		}
+	}
+
+	if outputs.GraphJSON != "" {
+		data, err := result.Graph.ToJSON()
+		if err != nil {
+			return fmt.Errorf("encode graph JSON: %w", err)
+		}
+		if err := os.WriteFile(outputs.GraphJSON, data, graphFilePermissions); err != nil {
+			return fmt.Errorf("write graph JSON: %w", err)
+		}
+	}
+
+	if outputs.GraphDOT != "" {
+		if err := os.WriteFile(outputs.GraphDOT, []byte(result.Graph.ToDOT()), graphFilePermissions); err != nil {
+			return fmt.Errorf("write graph DOT: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package gobzlmod
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/albertocavalcante/go-bzlmod/lockfile"
+)
+
+func TestResolveAndWrite(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/dep_a/1.0.0/MODULE.bazel":
+			w.Write([]byte(`module(name = "dep_a", version = "1.0.0")`))
+		case "/modules/dep_a/1.0.0/source.json":
+			w.Write([]byte(`{"url":"https://example.com/dep_a-1.0.0.tar.gz","integrity":"sha256-aaa"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "dep_a", version = "1.0.0")`
+
+	dir := t.TempDir()
+	outputs := OutputSpec{
+		Lockfile:  filepath.Join(dir, "MODULE.bazel.lock"),
+		GraphJSON: filepath.Join(dir, "graph.json"),
+		GraphDOT:  filepath.Join(dir, "graph.dot"),
+	}
+	if err := ResolveAndWrite(context.Background(), content, outputs, WithRegistries(server.URL)); err != nil {
+		t.Fatalf("ResolveAndWrite() error = %v", err)
+	}
+
+	lf, err := lockfile.ReadFile(outputs.Lockfile)
+	if err != nil {
+		t.Fatalf("ReadFile(lockfile) error = %v", err)
+	}
+	moduleURL := server.URL + "/modules/dep_a/1.0.0/MODULE.bazel"
+	if lf.GetRegistryHash(moduleURL) == "" {
+		t.Errorf("lockfile has no hash for %s", moduleURL)
+	}
+	written, err := os.ReadFile(outputs.Lockfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remarshaled, err := lf.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !bytes.Equal(written, remarshaled) {
+		t.Errorf("lockfile does not round-trip:\nwritten:\n%s\nremarshaled:\n%s", written, remarshaled)
+	}
+
+	graphJSON, err := os.ReadFile(outputs.GraphJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(graphJSON) || !strings.Contains(string(graphJSON), `"dep_a@1.0.0"`) {
+		t.Errorf("graph.json = %s, want JSON listing dep_a@1.0.0", graphJSON)
+	}
+
+	graphDOT, err := os.ReadFile(outputs.GraphDOT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(graphDOT), "digraph") || !strings.Contains(string(graphDOT), "dep_a@1.0.0") {
+		t.Errorf("graph.dot = %s, want a digraph with dep_a@1.0.0", graphDOT)
+	}
+}
+
+func TestResolveAndWrite_SkipsUnrequestedOutputs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	outputs := OutputSpec{GraphDOT: filepath.Join(dir, "graph.dot")}
+	if err := ResolveAndWrite(context.Background(), `module(name = "root", version = "1.0.0")`, outputs, WithRegistries(server.URL)); err != nil {
+		t.Fatalf("ResolveAndWrite() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "graph.dot" {
+		t.Errorf("written files = %v, want only graph.dot", entries)
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"maps"
 	"net/http"
 	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/albertocavalcante/go-bzlmod/bazeltools"
 	"github.com/albertocavalcante/go-bzlmod/graph"
@@ -18,7 +20,8 @@ import (
 )
 
 const (
-	// defaultMaxConcurrency limits concurrent module fetches from the registry.
+	// defaultMaxConcurrency limits concurrent module fetches from the registry
+	// unless ResolutionOptions.MaxConcurrentFetches is set.
 	// Set to 5 to balance parallelism with resource usage and avoid overwhelming
 	// the registry with too many simultaneous requests. This matches common HTTP
 	// client concurrency limits and provides good performance without excessive
@@ -41,13 +44,18 @@ const (
 	// cause blocking if taskBufferMultiplier * small_dep_count < tools_dep_count.
 	minTaskBufferSize = 100
 
-	// maxDependencyDepth is the maximum allowed depth for dependency traversal.
-	// This prevents stack overflow and resource exhaustion from extremely deep or
-	// circular dependency chains. Set to 1000 to accommodate very deep but valid
+	// maxDependencyDepth is the default maximum depth for dependency traversal,
+	// used unless ResolutionOptions.MaxDependencyDepth is set. This prevents
+	// stack overflow and resource exhaustion from extremely deep or circular
+	// dependency chains. Set to 1000 to accommodate very deep but valid
 	// dependency graphs while protecting against pathological cases.
 	maxDependencyDepth = 1000
 )
 
+// timeNow returns the current time when measuring the soft time budget.
+// Tests replace it to run out the budget at a chosen point.
+var timeNow = time.Now
+
 // dependencyResolver resolves Bazel module dependencies using Minimal Version Selection (MVS).
 //
 // This implementation follows Bazel's bzlmod resolution algorithm as defined in:
@@ -75,6 +83,12 @@ type dependencyResolver struct {
 	options         ResolutionOptions
 	overrideMu      sync.RWMutex
 	overrideModules map[string]*ModuleInfo
+	seededModules   map[string]*ModuleInfo
+
+	// moduleInfoSink, when set, receives the "name@version" -> ModuleInfo
+	// map of every module discovered by a successful resolution, and the
+	// "name@version" keys of the versions the registry did not have.
+	moduleInfoSink func(modules map[string]*ModuleInfo, missing []string)
 }
 
 // graphBuildContext holds state during dependency graph construction.
@@ -88,7 +102,8 @@ type graphBuildContext struct {
 	// Keyed by name@version to ensure the selected version's deps are used after MVS.
 	moduleDeps map[string][]string
 
-	// moduleInfoCache maps "name@version" -> ModuleInfo for Bazel compatibility checking.
+	// moduleInfoCache maps "name@version" -> ModuleInfo for Bazel compatibility
+	// checking and the compatibility levels recorded in the graph.
 	// This caches the parsed MODULE.bazel content to avoid refetching.
 	moduleInfoCache map[string]*ModuleInfo
 
@@ -101,6 +116,10 @@ type graphBuildContext struct {
 	// overrideModules contains pre-parsed MODULE.bazel for overridden modules
 	overrideModules map[string]*ModuleInfo
 
+	// seededModules maps "name@version" -> pre-parsed MODULE.bazel consulted
+	// before any registry fetch. Read-only after initialization.
+	seededModules map[string]*ModuleInfo
+
 	// unfulfilledNodepEdgeModuleNames tracks module names from nodep edges that could
 	// not be satisfied in the current discovery round. These are nodep dependencies
 	// that reference modules not yet in the dependency graph.
@@ -117,10 +136,69 @@ type graphBuildContext struct {
 	// in the root MODULE.bazel (before MODULE.tools injection).
 	explicitRootProdDepNames map[string]bool
 
-	// mu protects concurrent writes to depGraph, moduleDeps, moduleInfoCache, and unfulfilledNodepEdgeModuleNames
+	// extensionRepos maps "name@version" -> repos imported via use_repo by
+	// that module's non-dev extension proxies.
+	extensionRepos map[string][]string
+
+	// skippedDevDeps maps "name@version" -> "dep@version" entries for the
+	// dev_dependency bazel_deps that module declared and that were ignored
+	// because it is not the root.
+	skippedDevDeps map[string][]string
+
+	// ignoredOverrides maps "name@version" -> overrides that non-root module
+	// declared, formatted as "<type>_override(<module>)". Bazel only honors
+	// overrides from the root module.
+	ignoredOverrides map[string][]string
+
+	// contradictoryOverrides maps "name@version" of a module fetched because
+	// of a root override -> the override it declares on itself that would
+	// select a different version or source.
+	contradictoryOverrides map[string]string
+
+	// aliasedDeps maps "name@version" -> "old -> new" entries for the
+	// bazel_deps of that module that ModuleAliases rewrote.
+	aliasedDeps map[string][]string
+
+	// excluded holds the module names in ExcludeModules. Edges to them are
+	// dropped during discovery. Read-only after initialization.
+	excluded map[string]bool
+
+	// excludedEdges maps "name@version", or "<root>", to the ExcludeModules
+	// names whose edges were dropped from that module.
+	excludedEdges map[string][]string
+
+	// missing maps "name@version" -> the not-found fetch of a module version
+	// that was dropped from the graph during discovery.
+	missing map[string]missingModule
+
+	// softDeadline is when SoftTimeBudget runs out. Zero means no budget.
+	softDeadline time.Time
+
+	// unexplored holds "name@version" keys of modules that were not fetched
+	// because the soft time budget ran out.
+	unexplored map[string]bool
+
+	// heldPrefetches maps "name@version" of a version fetched only because a
+	// multiple_version_override allows it -> its module file. Its deps are
+	// processed once some module requests that version.
+	heldPrefetches map[string]*ModuleInfo
+
+	// mu protects concurrent writes to depGraph, moduleDeps, moduleInfoCache, extensionRepos, skippedDevDeps, ignoredOverrides, contradictoryOverrides, aliasedDeps, excludedEdges, missing, unexplored, heldPrefetches, and unfulfilledNodepEdgeModuleNames
 	mu sync.Mutex
 }
 
+// overBudget reports whether the soft time budget has run out, recording
+// depKey as unexplored if so.
+func (bc *graphBuildContext) overBudget(depKey string) bool {
+	if bc.softDeadline.IsZero() || timeNow().Before(bc.softDeadline) {
+		return false
+	}
+	bc.mu.Lock()
+	bc.unexplored[depKey] = true
+	bc.mu.Unlock()
+	return true
+}
+
 // newDependencyResolver creates a new resolver with the given registry.
 // If includeDevDeps is false, dev_dependency=True modules are excluded from resolution.
 func newDependencyResolver(registry Registry, includeDevDeps bool) *dependencyResolver {
@@ -159,6 +237,9 @@ func newDependencyResolverWithOptions(registry Registry, opts ResolutionOptions)
 		)
 	}
 
+	reg = withRetry(reg, opts.RetryAttempts, opts.RetryBaseDelay)
+	reg = withModuleRegistries(reg, opts.ModuleRegistries)
+
 	// Prepend vendor registry if VendorDir is set
 	if opts.VendorDir != "" {
 		vendorReg, err := newVendorRegistry(opts.VendorDir)
@@ -214,6 +295,71 @@ func (r *dependencyResolver) AddOverrideModuleInfo(moduleName string, moduleInfo
 	return nil
 }
 
+// SeedModules registers already-parsed module infos keyed by "name@version".
+// Discovery uses a seeded module instead of fetching its MODULE.bazel from
+// the registry, whatever overrides apply to it. Unlike AddOverrideModuleInfo,
+// which stands in for every version of a git/local/archive override, a seed
+// is only used for the exact version it is keyed by.
+func (r *dependencyResolver) SeedModules(modules map[string]*ModuleInfo) error {
+	seeds, err := normalizeSeedModules(modules)
+	if err != nil {
+		return err
+	}
+
+	r.overrideMu.Lock()
+	defer r.overrideMu.Unlock()
+	if r.seededModules == nil {
+		r.seededModules = make(map[string]*ModuleInfo, len(seeds))
+	}
+	maps.Copy(r.seededModules, seeds)
+	return nil
+}
+
+// normalizeSeedModules validates seed keys against the module infos they map
+// to and returns copies with empty names and versions filled in from the key.
+func normalizeSeedModules(modules map[string]*ModuleInfo) (map[string]*ModuleInfo, error) {
+	seeds := make(map[string]*ModuleInfo, len(modules))
+	for key, info := range modules {
+		name, version, ok := strings.Cut(key, "@")
+		if !ok || name == "" || version == "" {
+			return nil, fmt.Errorf("seed module key %q is not name@version", key)
+		}
+		if info == nil {
+			return nil, fmt.Errorf("seed module info for %s is nil", key)
+		}
+
+		clone := *info
+		if clone.Name == "" {
+			clone.Name = name
+		} else if clone.Name != name {
+			return nil, fmt.Errorf("seed module name mismatch for %s: %s", key, clone.Name)
+		}
+		if clone.Version == "" {
+			clone.Version = version
+		} else if clone.Version != version {
+			return nil, fmt.Errorf("seed module version mismatch for %s: %s", key, clone.Version)
+		}
+		seeds[key] = &clone
+	}
+	return seeds, nil
+}
+
+// seededModuleSnapshot returns the modules seeded via ResolutionOptions and
+// SeedModules, the latter taking precedence.
+func (r *dependencyResolver) seededModuleSnapshot() map[string]*ModuleInfo {
+	r.overrideMu.RLock()
+	defer r.overrideMu.RUnlock()
+	if len(r.options.SeedModules) == 0 && len(r.seededModules) == 0 {
+		return nil
+	}
+	seeds := maps.Clone(r.options.SeedModules)
+	if seeds == nil {
+		seeds = make(map[string]*ModuleInfo, len(r.seededModules))
+	}
+	maps.Copy(seeds, r.seededModules)
+	return seeds
+}
+
 func (r *dependencyResolver) overrideModuleSnapshot() map[string]*ModuleInfo {
 	r.overrideMu.RLock()
 	defer r.overrideMu.RUnlock()
@@ -223,6 +369,15 @@ func (r *dependencyResolver) overrideModuleSnapshot() map[string]*ModuleInfo {
 	return maps.Clone(r.overrideModules)
 }
 
+// maxConcurrentFetches returns the number of concurrent module fetches
+// allowed by opts.
+func maxConcurrentFetches(opts ResolutionOptions) int {
+	if opts.MaxConcurrentFetches > 0 {
+		return opts.MaxConcurrentFetches
+	}
+	return defaultMaxConcurrency
+}
+
 // emitProgress safely calls the OnProgress callback if configured.
 func (r *dependencyResolver) emitProgress(event ProgressEvent) {
 	if r.options.OnProgress != nil {
@@ -248,6 +403,52 @@ func (r *dependencyResolver) ResolveDependencies(ctx context.Context, rootModule
 	if rootModule == nil {
 		return nil, fmt.Errorf("root module is nil")
 	}
+	if r.options.Tracer == nil {
+		return r.resolveDependencies(ctx, rootModule)
+	}
+
+	ctx, span := r.options.Tracer.Start(ctx, spanResolve)
+	defer span.End()
+	span.SetAttributes(
+		SpanAttribute{Key: attrRootName, Value: rootModule.Name},
+		SpanAttribute{Key: attrRootVersion, Value: rootModule.Version},
+	)
+	result, err := r.resolveDependencies(ctx, rootModule)
+	if err != nil {
+		span.RecordError(err)
+		span.SetAttributes(SpanAttribute{Key: attrStatus, Value: spanStatusError})
+		return nil, err
+	}
+	span.SetAttributes(
+		SpanAttribute{Key: attrModuleCount, Value: len(result.Modules)},
+		SpanAttribute{Key: attrStatus, Value: spanStatusOK},
+	)
+	return result, nil
+}
+
+// resolveDependencies implements ResolveDependencies for a non-nil root.
+func (r *dependencyResolver) resolveDependencies(ctx context.Context, rootModule *ModuleInfo) (list *ResolutionList, err error) {
+	// bc is set once discovery starts; the TotalTimeout handler reads it to
+	// report how far resolution got.
+	var bc *graphBuildContext
+	if r.options.TotalTimeout > 0 {
+		parent := ctx
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.options.TotalTimeout)
+		defer cancel()
+		defer func() {
+			if ctx.Err() != context.DeadlineExceeded || parent.Err() != nil {
+				return
+			}
+			// Stages after discovery, such as the metadata checks, fail open
+			// on fetch errors, so the deadline may have cut them short
+			// without an error.
+			if err == nil {
+				err = ctx.Err()
+			}
+			list, err = nil, r.timeoutError(bc, err)
+		}()
+	}
 
 	logger := r.log()
 	logger.Info("starting dependency resolution",
@@ -261,7 +462,14 @@ func (r *dependencyResolver) ResolveDependencies(ctx context.Context, rootModule
 		Message: "starting dependency resolution",
 	})
 
+	// Apply version pins as if they were declared in the root MODULE.bazel,
+	// then rename aliased root deps so everything below sees the new names.
+	rootModule = pinDependencies(rootModule, r.options.VersionPins)
+	rootModule, rootAliased := aliasDependencies(rootModule, r.options.ModuleAliases)
+
 	// Track explicit root production deps before MODULE.tools injection.
+	declaredRoot := *rootModule
+	declaredRoot.Dependencies = slices.Clone(rootModule.Dependencies)
 	explicitRootProdDepNames := make(map[string]bool)
 	for _, dep := range rootModule.Dependencies {
 		if !dep.DevDependency {
@@ -269,6 +477,16 @@ func (r *dependencyResolver) ResolveDependencies(ctx context.Context, rootModule
 		}
 	}
 
+	excluded := make(map[string]bool, len(r.options.ExcludeModules))
+	for _, name := range r.options.ExcludeModules {
+		excluded[name] = true
+	}
+	for _, dep := range rootModule.Dependencies {
+		if excluded[dep.Name] && (!dep.DevDependency || r.options.IncludeDevDeps) {
+			return nil, fmt.Errorf("cannot exclude module %s: the root module depends on it directly via bazel_dep", dep.Name)
+		}
+	}
+
 	// Inject Bazel's MODULE.tools dependencies if a Bazel version is specified
 	if r.options.BazelVersion != "" {
 		logger.Debug("injecting MODULE.tools dependencies", "bazelVersion", r.options.BazelVersion)
@@ -276,16 +494,30 @@ func (r *dependencyResolver) ResolveDependencies(ctx context.Context, rootModule
 	}
 
 	// Initialize graph build context with all state needed for traversal
-	bc := &graphBuildContext{
+	bc = &graphBuildContext{
 		depGraph:                        make(map[string]map[string]*depRequest),
 		moduleDeps:                      make(map[string][]string),
 		moduleInfoCache:                 make(map[string]*ModuleInfo),
+		extensionRepos:                  make(map[string][]string),
+		skippedDevDeps:                  make(map[string][]string),
+		ignoredOverrides:                make(map[string][]string),
+		contradictoryOverrides:          make(map[string]string),
+		aliasedDeps:                     make(map[string][]string),
+		excludedEdges:                   make(map[string][]string),
+		missing:                         make(map[string]missingModule),
+		unexplored:                      make(map[string]bool),
+		heldPrefetches:                  make(map[string]*ModuleInfo),
 		visiting:                        &sync.Map{},
 		overrides:                       indexOverrides(rootModule.Overrides),
 		overrideModules:                 r.overrideModuleSnapshot(),
+		seededModules:                   r.seededModuleSnapshot(),
 		unfulfilledNodepEdgeModuleNames: make(map[string]bool),
 		prevRoundModuleNames:            map[string]bool{rootModule.Name: true},
 		explicitRootProdDepNames:        explicitRootProdDepNames,
+		excluded:                        excluded,
+	}
+	if r.options.SoftTimeBudget > 0 {
+		bc.softDeadline = timeNow().Add(r.options.SoftTimeBudget)
 	}
 
 	// Multi-round discovery loop for handling nodep edges.
@@ -336,6 +568,14 @@ func (r *dependencyResolver) ResolveDependencies(ctx context.Context, rootModule
 			break
 		}
 
+		bc.mu.Lock()
+		partial := len(bc.unexplored) > 0
+		bc.mu.Unlock()
+		if partial {
+			logger.Debug("soft time budget exceeded, skipping further discovery rounds", "round", round)
+			break
+		}
+
 		// Update prev round module names for the next iteration
 		bc.mu.Lock()
 		bc.prevRoundModuleNames = currentModuleNames
@@ -350,12 +590,17 @@ func (r *dependencyResolver) ResolveDependencies(ctx context.Context, rootModule
 	}
 
 	// Substitute yanked versions if enabled
+	var substitutions []YankedVersionInfo
 	if r.options.SubstituteYanked {
-		r.substituteYankedVersionsInGraph(ctx, bc.depGraph)
+		substitutions = r.substituteYankedVersionsInGraph(ctx, bc.depGraph)
 	}
 
 	r.applyOverrides(bc.depGraph, rootModule.Overrides)
 	selectedVersions := r.applyMVS(bc.depGraph)
+	inconsistencies := listedButMissingSelections(ctx, bc.missing, selectedVersions)
+	if r.options.Deterministic {
+		sortRequesters(bc.depGraph)
+	}
 
 	// Validate direct dependencies match resolved versions
 	if r.options.DirectDepsMode != DirectDepsOff {
@@ -372,6 +617,67 @@ func (r *dependencyResolver) ResolveDependencies(ctx context.Context, rootModule
 	if err != nil {
 		return nil, err // Preserve error types (e.g., YankedVersionsError) without wrapping
 	}
+	result.root = &declaredRoot
+	result.Summary.YankedVersions = append(result.Summary.YankedVersions, substitutions...)
+	slices.SortFunc(result.Summary.YankedVersions, func(a, b YankedVersionInfo) int {
+		return cmp.Or(cmp.Compare(a.Module, b.Module), version.Compare(a.Version, b.Version))
+	})
+	result.UnprunedModules = unprunedModules(result.Modules, bc.depGraph, bc.moduleDeps)
+	recordRequestedVersions(result.Graph, bc.depGraph)
+	result.ExtensionRepos = r.collectExtensionRepos(&declaredRoot, result.Modules, bc.extensionRepos)
+	result.Summary.UnprovidedUseRepos = declaredRoot.UnprovidedUseRepos
+	for _, inconsistent := range inconsistencies {
+		result.Summary.RegistryInconsistencies = append(result.Summary.RegistryInconsistencies, inconsistent.Module+"@"+inconsistent.Version)
+		result.Warnings = append(result.Warnings, inconsistent.Error()+"; the version was dropped from resolution")
+	}
+	for _, entry := range declaredRoot.UnprovidedUseRepos {
+		result.Warnings = append(result.Warnings, unprovidedUseRepoWarning(entry))
+	}
+	result.Summary.CompatLevelAdvanced = compatLevelAdvances(result.Modules, bc.depGraph, bc.moduleInfoCache)
+	for _, adv := range result.Summary.CompatLevelAdvanced {
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"module %s: selected %s (compatibility_level %d) advances past %s (compatibility_level %d) requested by the root module",
+			adv.Module, adv.SelectedVersion, adv.SelectedCompatibilityLevel, adv.RequestedVersion, adv.RequestedCompatibilityLevel))
+	}
+	if r.options.WarnPreOneMinorBumps {
+		bumps := preOneMinorBumps(result.Modules, bc.depGraph)
+		result.Summary.PreOneMinorBumps = bumps
+		result.Warnings = append(result.Warnings, bumps...)
+	}
+	for _, rewrite := range rootAliased {
+		result.Summary.AliasedDependencies = append(result.Summary.AliasedDependencies, "<root>: "+rewrite)
+	}
+	result.Summary.ExcludedModules, result.Summary.UnusedExclusions = exclusionEffects(r.options.ExcludeModules, bc.excludedEdges, result.Modules)
+	for _, module := range result.Modules {
+		for _, rewrite := range bc.aliasedDeps[module.Key()] {
+			result.Summary.AliasedDependencies = append(result.Summary.AliasedDependencies, module.Key()+": "+rewrite)
+		}
+		for _, dep := range bc.skippedDevDeps[module.Key()] {
+			result.Summary.SkippedTransitiveDevDeps = append(result.Summary.SkippedTransitiveDevDeps, module.Key()+" -> "+dep)
+		}
+		for _, o := range bc.ignoredOverrides[module.Key()] {
+			result.Summary.IgnoredNonRootOverrides = append(result.Summary.IgnoredNonRootOverrides, module.Key()+": "+o)
+		}
+		if conflict := bc.contradictoryOverrides[module.Key()]; conflict != "" {
+			result.Summary.ContradictoryOverrides = append(result.Summary.ContradictoryOverrides, module.Key()+": "+conflict)
+			result.Warnings = append(result.Warnings, fmt.Sprintf(
+				"module %s: selected by a root override but declares %s on itself; Bazel ignores it, but the override may point at the wrong registry or version",
+				module.Key(), conflict))
+		}
+		if bc.unexplored[module.Key()] {
+			result.Summary.UnexploredModules = append(result.Summary.UnexploredModules, module.Key())
+		}
+	}
+	if len(bc.unexplored) > 0 {
+		result.Summary.Partial = true
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"soft time budget of %s exceeded: resolution is partial, %d module versions were not fetched",
+			r.options.SoftTimeBudget, len(bc.unexplored)))
+	}
+
+	if r.options.Deterministic {
+		sortResolution(result)
+	}
 
 	logger.Info("resolution complete",
 		"totalModules", len(result.Modules),
@@ -384,9 +690,97 @@ func (r *dependencyResolver) ResolveDependencies(ctx context.Context, rootModule
 		Message: fmt.Sprintf("resolved %d modules", len(result.Modules)),
 	})
 
+	if r.moduleInfoSink != nil {
+		r.moduleInfoSink(bc.moduleInfoCache, slices.Collect(maps.Keys(bc.missing)))
+	}
 	return result, nil
 }
 
+// timeoutError returns the ResolutionTimeoutError for err, which a fetch
+// returned once TotalTimeout ran out, counting the module files bc fetched.
+func (r *dependencyResolver) timeoutError(bc *graphBuildContext, err error) error {
+	var resolved int
+	if bc != nil {
+		bc.mu.Lock()
+		resolved = len(bc.moduleInfoCache)
+		bc.mu.Unlock()
+	}
+	return &ResolutionTimeoutError{
+		Timeout:         r.options.TotalTimeout,
+		ModulesResolved: resolved,
+		Err:             err,
+	}
+}
+
+// unprunedModules lists every module version in depGraph, sorted by name and
+// version. Selected versions are copied from modules; the others are built
+// from their request and the dependencies their MODULE.bazel declared.
+func unprunedModules(modules []ModuleToResolve, depGraph map[string]map[string]*depRequest, moduleDeps map[string][]string) []ModuleToResolve {
+	selected := make(map[string]ModuleToResolve, len(modules))
+	for _, m := range modules {
+		selected[m.Key()] = m
+	}
+
+	var unpruned []ModuleToResolve
+	for _, name := range slices.Sorted(maps.Keys(depGraph)) {
+		versions := slices.Collect(maps.Keys(depGraph[name]))
+		version.Sort(versions)
+		for _, v := range versions {
+			key := name + "@" + v
+			if m, ok := selected[key]; ok {
+				unpruned = append(unpruned, m)
+				continue
+			}
+			req := depGraph[name][v]
+			unpruned = append(unpruned, ModuleToResolve{
+				Name:          name,
+				Version:       v,
+				DevDependency: req.DevDependency,
+				Dependencies:  slices.Clone(moduleDeps[key]),
+				RequiredBy:    slices.Clone(req.RequiredBy),
+			})
+		}
+	}
+	return unpruned
+}
+
+// sortRequesters sorts the RequiredBy list of every request in depGraph,
+// which otherwise follows the order the requesters were fetched in.
+func sortRequesters(depGraph map[string]map[string]*depRequest) {
+	for _, versions := range depGraph {
+		for _, req := range versions {
+			slices.Sort(req.RequiredBy)
+		}
+	}
+}
+
+// sortResolution puts the order-sensitive parts of list that sortRequesters
+// does not cover into a canonical order: module dependencies by name and
+// graph edges by module key.
+func sortResolution(list *ResolutionList) {
+	slices.SortFunc(list.Modules, func(a, b ModuleToResolve) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), version.Compare(a.Version, b.Version))
+	})
+	for i := range list.Modules {
+		slices.Sort(list.Modules[i].Dependencies)
+	}
+	for i := range list.UnprunedModules {
+		slices.Sort(list.UnprunedModules[i].Dependencies)
+	}
+	if list.Graph == nil {
+		return
+	}
+	for _, node := range list.Graph.Modules {
+		slices.SortFunc(node.Dependencies, compareModuleKeys)
+		slices.SortFunc(node.Dependents, compareModuleKeys)
+	}
+}
+
+// compareModuleKeys orders graph module keys by name, then version.
+func compareModuleKeys(a, b graph.ModuleKey) int {
+	return cmp.Or(cmp.Compare(a.Name, b.Name), version.Compare(a.Version, b.Version))
+}
+
 // buildDependencyGraph constructs the dependency graph by recursively fetching
 // and processing MODULE.bazel files. Uses bc (graphBuildContext) to accumulate state.
 //
@@ -434,17 +828,46 @@ func (r *dependencyResolver) buildDependencyGraph(ctx context.Context, module *M
 
 	// checkDepth ensures we don't exceed maximum dependency depth.
 	// This protects against pathologically deep dependency chains.
+	maxDepth := r.options.MaxDependencyDepth
+	if maxDepth <= 0 {
+		maxDepth = maxDependencyDepth
+	}
 	checkDepth := func(depPath []string) error {
-		if len(depPath) > maxDependencyDepth {
+		if len(depPath) > maxDepth {
+			chain := slices.Clone(depPath)
+			if chain[0] == "<root>" && module.Name != "" {
+				chain[0] = module.Name
+				if module.Version != "" {
+					chain[0] += "@" + module.Version
+				}
+			}
 			return &MaxDepthExceededError{
 				Depth:    len(depPath),
-				MaxDepth: maxDependencyDepth,
+				MaxDepth: maxDepth,
 				Path:     depPath,
+				Chain:    chain,
 			}
 		}
 		return nil
 	}
 
+	// approve asks OnDependencyDiscovered about a newly visited module version.
+	// depPath ends with the dependency, preceded by its requester.
+	approve := func(depName, depVersion string, depPath []string) error {
+		if r.options.OnDependencyDiscovered == nil {
+			return nil
+		}
+		requester := depPath[len(depPath)-2]
+		ok, err := r.options.OnDependencyDiscovered(depName, depVersion, requester)
+		if err != nil {
+			return fmt.Errorf("approve %s@%s: %w", depName, depVersion, err)
+		}
+		if !ok {
+			return &DependencyRejectedError{Module: depName, Version: depVersion, Requester: requester}
+		}
+		return nil
+	}
+
 	enqueue := func(depName, depVersion string, depPath []string) {
 		if ctx.Err() != nil {
 			return
@@ -467,6 +890,16 @@ func (r *dependencyResolver) buildDependencyGraph(ctx context.Context, module *M
 			return
 		}
 
+		if err := approve(depName, depVersion, depPath); err != nil {
+			setErr(err)
+			return
+		}
+
+		// Once the soft time budget runs out, stop starting new fetches.
+		if bc.overBudget(depKey) {
+			return
+		}
+
 		tasksWG.Add(1)
 		queueMu.Lock()
 		if queueClose || ctx.Err() != nil {
@@ -483,27 +916,87 @@ func (r *dependencyResolver) buildDependencyGraph(ctx context.Context, module *M
 	processDeps = func(module *ModuleInfo, path []string) error {
 		isRootModule := len(path) == 1 && path[0] == "<root>"
 
+		// The root's deps were renamed before discovery started.
+		if !isRootModule && module.Name != "" {
+			var aliased []string
+			module, aliased = aliasDependencies(module, r.options.ModuleAliases)
+			if len(aliased) > 0 {
+				bc.mu.Lock()
+				bc.aliasedDeps[module.Name+"@"+module.Version] = aliased
+				bc.mu.Unlock()
+			}
+		}
+
 		// Capture this module's dependencies for graph building (O(n) - just collect names)
-		var deps []string
+		var deps, excludedDeps []string
 		for _, dep := range module.Dependencies {
 			// Match Bazel: non-root modules always ignore dev dependencies.
 			if dep.DevDependency && (!isRootModule || !r.options.IncludeDevDeps) {
 				continue
 			}
+			if bc.excluded[dep.Name] {
+				excludedDeps = append(excludedDeps, dep.Name)
+				continue
+			}
 			deps = append(deps, dep.Name)
 		}
+		for _, nodepDep := range module.NodepDependencies {
+			if bc.excluded[nodepDep.Name] && (!nodepDep.DevDependency || isRootModule && r.options.IncludeDevDeps) {
+				excludedDeps = append(excludedDeps, nodepDep.Name)
+			}
+		}
+		if len(excludedDeps) > 0 {
+			edgesKey := "<root>"
+			if !isRootModule {
+				edgesKey = module.Name + "@" + module.Version
+			}
+			bc.mu.Lock()
+			bc.excludedEdges[edgesKey] = excludedDeps
+			bc.mu.Unlock()
+		}
 		if len(deps) > 0 && module.Name != "" {
 			depsKey := module.Name + "@" + module.Version
 			bc.mu.Lock()
 			bc.moduleDeps[depsKey] = deps
 			bc.mu.Unlock()
 		}
+		if !isRootModule && module.Name != "" {
+			var skipped []string
+			for _, dep := range module.Dependencies {
+				if dep.DevDependency {
+					skipped = append(skipped, dep.Name+"@"+dep.Version)
+				}
+			}
+			if len(skipped) > 0 {
+				bc.mu.Lock()
+				bc.skippedDevDeps[module.Name+"@"+module.Version] = skipped
+				bc.mu.Unlock()
+			}
+		}
+		if !isRootModule && len(module.Overrides) > 0 && module.Name != "" {
+			// Match Bazel: overrides only take effect in the root module.
+			ignored := make([]string, len(module.Overrides))
+			for i, o := range module.Overrides {
+				ignored[i] = o.Type + "_override(" + o.ModuleName + ")"
+			}
+			bc.mu.Lock()
+			bc.ignoredOverrides[module.Name+"@"+module.Version] = ignored
+			bc.mu.Unlock()
+		}
+		if !isRootModule && len(module.ExtensionRepos) > 0 && module.Name != "" {
+			bc.mu.Lock()
+			bc.extensionRepos[module.Name+"@"+module.Version] = module.ExtensionRepos
+			bc.mu.Unlock()
+		}
 
 		for _, dep := range module.Dependencies {
 			// Match Bazel: non-root modules always ignore dev dependencies.
 			if dep.DevDependency && (!isRootModule || !r.options.IncludeDevDeps) {
 				continue
 			}
+			if bc.excluded[dep.Name] {
+				continue
+			}
 
 			effectiveVersion := dep.Version
 			skipFetch := false
@@ -528,11 +1021,17 @@ func (r *dependencyResolver) buildDependencyGraph(ctx context.Context, module *M
 				bc.depGraph[dep.Name] = make(map[string]*depRequest)
 			}
 
+			var released *ModuleInfo
 			if existing, exists := bc.depGraph[dep.Name][effectiveVersion]; exists {
 				existing.RequiredBy = append(existing.RequiredBy, path[len(path)-1])
 				if !dep.DevDependency {
 					existing.DevDependency = false
 				}
+				if existing.Prefetched {
+					existing.Prefetched = false
+					released = bc.heldPrefetches[dep.Name+"@"+effectiveVersion]
+					delete(bc.heldPrefetches, dep.Name+"@"+effectiveVersion)
+				}
 			} else {
 				bc.depGraph[dep.Name][effectiveVersion] = &depRequest{
 					Version:       effectiveVersion,
@@ -542,6 +1041,19 @@ func (r *dependencyResolver) buildDependencyGraph(ctx context.Context, module *M
 			}
 			bc.mu.Unlock()
 
+			// A prefetched version that was already fetched is now requested,
+			// so its deps take part in discovery.
+			if released != nil {
+				depPath := append(path[:len(path):len(path)], dep.Name+"@"+effectiveVersion)
+				if err := checkDepth(depPath); err != nil {
+					return err
+				}
+				if err := processDeps(released, depPath); err != nil {
+					return err
+				}
+				continue
+			}
+
 			if skipFetch {
 				if overrideModule, ok := bc.overrideModules[dep.Name]; ok {
 					depKey := dep.Name + "@" + effectiveVersion
@@ -556,6 +1068,9 @@ func (r *dependencyResolver) buildDependencyGraph(ctx context.Context, module *M
 					// This prevents infinite loops in mutual dependencies (like rules_go <-> gazelle).
 					// Following Bazel's approach: if already visited, skip silently - no error.
 					if _, visited := bc.visiting.LoadOrStore(depKey, struct{}{}); !visited {
+						if err := approve(dep.Name, effectiveVersion, depPath); err != nil {
+							return err
+						}
 						if err := processDeps(overrideModule, depPath); err != nil {
 							return err
 						}
@@ -579,6 +1094,9 @@ func (r *dependencyResolver) buildDependencyGraph(ctx context.Context, module *M
 			if nodepDep.DevDependency && (!isRootModule || !r.options.IncludeDevDeps) {
 				continue
 			}
+			if bc.excluded[nodepDep.Name] {
+				continue
+			}
 
 			effectiveVersion := nodepDep.Version
 			if override, ok := bc.overrides[nodepDep.Name]; ok {
@@ -607,6 +1125,7 @@ func (r *dependencyResolver) buildDependencyGraph(ctx context.Context, module *M
 				}
 				if existing, exists := bc.depGraph[nodepDep.Name][effectiveVersion]; exists {
 					existing.RequiredBy = append(existing.RequiredBy, path[len(path)-1]+" (nodep)")
+					existing.Prefetched = false
 				} else {
 					bc.depGraph[nodepDep.Name][effectiveVersion] = &depRequest{
 						Version:       effectiveVersion,
@@ -626,6 +1145,21 @@ func (r *dependencyResolver) buildDependencyGraph(ctx context.Context, module *M
 		return nil
 	}
 
+	// hold caches a fetched module file and reports whether its deps must
+	// wait, because only a multiple_version_override has asked for it so far.
+	hold := func(name, version string, info *ModuleInfo) bool {
+		key := name + "@" + version
+		bc.mu.Lock()
+		defer bc.mu.Unlock()
+		// Cache module info for Bazel compatibility checking and graph metadata
+		bc.moduleInfoCache[key] = info
+		if req := bc.depGraph[name][version]; req != nil && req.Prefetched {
+			bc.heldPrefetches[key] = info
+			return true
+		}
+		return false
+	}
+
 	worker := func() {
 		defer workersWG.Done()
 		logger := r.log()
@@ -642,7 +1176,20 @@ func (r *dependencyResolver) buildDependencyGraph(ctx context.Context, module *M
 			taskQueue = taskQueue[1:]
 			queueMu.Unlock()
 
-			if ctx.Err() != nil {
+			if ctx.Err() != nil || bc.overBudget(task.name+"@"+task.version) {
+				tasksWG.Done()
+				continue
+			}
+
+			if seeded := bc.seededModules[task.name+"@"+task.version]; seeded != nil {
+				logger.Debug("using seeded module", "name", task.name, "version", task.version)
+				if hold(task.name, task.version, seeded) {
+					tasksWG.Done()
+					continue
+				}
+				if err := processDeps(seeded, task.path); err != nil {
+					setErr(err)
+				}
 				tasksWG.Done()
 				continue
 			}
@@ -662,17 +1209,19 @@ func (r *dependencyResolver) buildDependencyGraph(ctx context.Context, module *M
 				logger.Debug("using registry override", "name", task.name, "registry", override.Registry)
 				// Use the overridden registry for this specific module while sharing
 				// the trace collector with the main resolver registry.
-				registryToUse = registryWithAllOptionsAndTrace(
+				registryToUse = withRetry(registryWithAllOptionsAndTrace(
 					r.options.HTTPClient,
 					r.options.Cache,
 					r.options.Timeout,
 					r.options.Logger,
 					sharedRegistryFileTrace(r.registry),
 					override.Registry,
-				)
+				), r.options.RetryAttempts, r.options.RetryBaseDelay)
 			}
 
-			transitiveDep, err := registryToUse.GetModuleFile(ctx, task.name, task.version)
+			fetchCtx, span := r.startFetchSpan(ctx, task.name, task.version)
+			transitiveDep, err := registryToUse.GetModuleFile(fetchCtx, task.name, task.version)
+			endFetchSpan(fetchCtx, span, err)
 
 			// Emit module_fetch_end event
 			r.emitProgress(ProgressEvent{
@@ -700,9 +1249,16 @@ func (r *dependencyResolver) buildDependencyGraph(ctx context.Context, module *M
 						}
 					}
 					removeDependency(bc.depGraph, task.name, task.version)
+					if !missingRequiredByRootProduction {
+						bc.missing[task.name+"@"+task.version] = missingModule{registry: registryToUse, err: err}
+					}
 					bc.mu.Unlock()
 					if missingRequiredByRootProduction {
-						setErr(fmt.Errorf("fetch module %s@%s: %w", task.name, task.version, err))
+						if inconsistent := listedButMissing(ctx, registryToUse, task.name, task.version, err); inconsistent != nil {
+							setErr(inconsistent)
+						} else {
+							setErr(fmt.Errorf("fetch module %s@%s: %w", task.name, task.version, err))
+						}
 					}
 					tasksWG.Done()
 					continue
@@ -716,14 +1272,18 @@ func (r *dependencyResolver) buildDependencyGraph(ctx context.Context, module *M
 			logger.Debug("fetched module", "name", task.name, "version", task.version,
 				"dependencies", len(transitiveDep.Dependencies))
 
-			// Cache module info for Bazel compatibility checking
-			if len(transitiveDep.BazelCompatibility) > 0 {
-				cacheKey := task.name + "@" + task.version
-				bc.mu.Lock()
-				bc.moduleInfoCache[cacheKey] = transitiveDep
-				bc.mu.Unlock()
+			if override, ok := bc.overrides[task.name]; ok {
+				if conflict := contradictoryOverride(override, task.version, transitiveDep); conflict != "" {
+					bc.mu.Lock()
+					bc.contradictoryOverrides[task.name+"@"+task.version] = conflict
+					bc.mu.Unlock()
+				}
 			}
 
+			if hold(task.name, task.version, transitiveDep) {
+				tasksWG.Done()
+				continue
+			}
 			if err := processDeps(transitiveDep, task.path); err != nil {
 				setErr(err)
 			}
@@ -731,7 +1291,7 @@ func (r *dependencyResolver) buildDependencyGraph(ctx context.Context, module *M
 		}
 	}
 
-	for range defaultMaxConcurrency {
+	for range maxConcurrentFetches(r.options) {
 		workersWG.Add(1)
 		go worker()
 	}
@@ -740,6 +1300,35 @@ func (r *dependencyResolver) buildDependencyGraph(ctx context.Context, module *M
 		setErr(err)
 	}
 
+	// Fetch every version allowed by a multiple_version_override up front:
+	// nothing guarantees they are reachable through transitive discovery.
+	// Until some module requests one, it is kept out of version selection
+	// and its deps are not followed. Versions come from the regular registry
+	// chain unless the override names a registry.
+	//
+	// Reference: Selection.java computeAllowedVersionSets
+	for _, name := range slices.Sorted(maps.Keys(bc.overrides)) {
+		override := bc.overrides[name]
+		if override.Type != overrideTypeMultiple || bc.excluded[name] {
+			continue
+		}
+		for _, v := range override.Versions {
+			bc.mu.Lock()
+			if bc.depGraph[name] == nil {
+				bc.depGraph[name] = make(map[string]*depRequest)
+			}
+			if _, exists := bc.depGraph[name][v]; !exists {
+				bc.depGraph[name][v] = &depRequest{
+					Version:    v,
+					RequiredBy: []string{"<override>"},
+					Prefetched: true,
+				}
+			}
+			bc.mu.Unlock()
+			enqueue(name, v, append(path[:len(path):len(path)], name+"@"+v))
+		}
+	}
+
 	go func() {
 		tasksWG.Wait()
 		queueMu.Lock()
@@ -805,7 +1394,12 @@ func (r *dependencyResolver) applyMVS(depGraph map[string]map[string]*depRequest
 	for moduleName, versions := range depGraph {
 		var maxReq *depRequest
 		for _, req := range versions {
-			if maxReq == nil || version.Compare(req.Version, maxReq.Version) > 0 {
+			if req.Prefetched {
+				continue
+			}
+			// CompareDeterministic breaks ties between versions differing only
+			// in build metadata, which Compare treats as equal.
+			if maxReq == nil || version.CompareDeterministic(req.Version, maxReq.Version) > 0 {
 				maxReq = req
 			}
 		}
@@ -814,6 +1408,18 @@ func (r *dependencyResolver) applyMVS(depGraph map[string]map[string]*depRequest
 		}
 	}
 
+	if r.options.OnProgress != nil {
+		for _, moduleName := range slices.Sorted(maps.Keys(selected)) {
+			candidates := slices.SortedFunc(maps.Keys(depGraph[moduleName]), version.CompareDeterministic)
+			r.emitProgress(ProgressEvent{
+				Type:       ProgressVersionSelected,
+				Module:     moduleName,
+				Version:    selected[moduleName].Version,
+				Candidates: candidates,
+			})
+		}
+	}
+
 	return selected
 }
 
@@ -916,8 +1522,8 @@ func (r *dependencyResolver) buildResolutionList(ctx context.Context, selectedVe
 		return cmp.Compare(a.Name, b.Name)
 	})
 
-	// Check for yanked/deprecated versions if enabled
-	if r.options.CheckYanked || r.options.WarnDeprecated {
+	// Check for yanked/deprecated versions and collect licenses if enabled
+	if r.options.CheckYanked || r.options.WarnDeprecated || r.options.FetchLicenses {
 		checkModuleMetadata(ctx, r.registry, r.options, list)
 	}
 
@@ -934,7 +1540,13 @@ func (r *dependencyResolver) buildResolutionList(ctx context.Context, selectedVe
 
 	// Compute summary statistics
 	list.Summary.TotalModules = len(list.Modules)
+	modulesAtDepth := make(map[int]int)
 	for _, module := range list.Modules {
+		if module.Depth > 0 {
+			modulesAtDepth[module.Depth]++
+			list.Summary.MaxDepth = max(list.Summary.MaxDepth, module.Depth)
+			list.Summary.MaxBreadth = max(list.Summary.MaxBreadth, modulesAtDepth[module.Depth])
+		}
 		if module.DevDependency {
 			list.Summary.DevModules++
 		} else {
@@ -951,6 +1563,17 @@ func (r *dependencyResolver) buildResolutionList(ctx context.Context, selectedVe
 		}
 	}
 
+	for _, m := range list.Modules {
+		if m.Yanked || m.YankAllowed {
+			list.Summary.YankedVersions = append(list.Summary.YankedVersions, YankedVersionInfo{
+				Module:  m.Name,
+				Version: m.Version,
+				Reason:  m.YankReason,
+				Allowed: m.YankAllowed,
+			})
+		}
+	}
+
 	// Handle yanked version behavior
 	if list.Summary.YankedModules > 0 {
 		switch r.options.YankedBehavior {
@@ -978,8 +1601,9 @@ func (r *dependencyResolver) buildResolutionList(ctx context.Context, selectedVe
 	if r.options.WarnDeprecated && list.Summary.DeprecatedModules > 0 {
 		for _, m := range list.Modules {
 			if m.IsDeprecated {
-				list.Warnings = append(list.Warnings,
-					fmt.Sprintf("module %s is deprecated: %s", m.Name, m.DeprecationReason))
+				warning := fmt.Sprintf("module %s is deprecated: %s", m.Name, m.DeprecationReason)
+				list.Summary.DeprecationWarnings = append(list.Summary.DeprecationWarnings, warning)
+				list.Warnings = append(list.Warnings, warning)
 			}
 		}
 	}
@@ -1027,6 +1651,13 @@ func (r *dependencyResolver) buildResolutionList(ctx context.Context, selectedVe
 
 	// Build dependency graph - O(n) where n = number of modules
 	list.Graph = buildGraph(rootModule, list.Modules)
+	for key, node := range list.Graph.Modules {
+		if node.IsRoot {
+			node.CompatibilityLevel = rootModule.CompatibilityLevel
+		} else if info := moduleInfoCache[key.String()]; info != nil {
+			node.CompatibilityLevel = info.CompatibilityLevel
+		}
+	}
 
 	return list, nil
 }
@@ -1080,11 +1711,141 @@ func buildGraph(rootModule *ModuleInfo, modules []ModuleToResolve) *graph.Graph
 	return graph.Build(rootKey, simpleModules)
 }
 
+// recordRequestedVersions fills Node.RequestedVersions from the versions each
+// module asked for during discovery, before MVS picked one. Requests made by
+// module versions that were not selected are not in the graph and are skipped.
+func recordRequestedVersions(g *graph.Graph, depGraph map[string]map[string]*depRequest) {
+	for key, node := range g.Modules {
+		for requested, req := range depGraph[key.Name] {
+			for _, requester := range req.RequiredBy {
+				requesterKey := g.Root
+				if requester != "<root>" {
+					name, version, _ := strings.Cut(requester, "@")
+					requesterKey = graph.ModuleKey{Name: name, Version: version}
+				}
+				if g.Modules[requesterKey] != nil {
+					node.RequestedVersions[requesterKey] = requested
+				}
+			}
+		}
+	}
+}
+
 func isNotFound(err error) bool {
+	var pinnedErr *PinnedRegistryError
+	if errors.As(err, &pinnedErr) {
+		return false
+	}
 	var regErr *RegistryError
 	return errors.As(err, &regErr) && regErr.StatusCode == http.StatusNotFound
 }
 
+// listedButMissing returns a *RegistryInconsistencyError if fetchErr is a
+// not-found error for a version that the module's metadata.json lists.
+// Metadata is only fetched after a miss, and a metadata failure is treated as
+// no evidence of inconsistency.
+func listedButMissing(ctx context.Context, reg Registry, name, version string, fetchErr error) error {
+	if !isNotFound(fetchErr) {
+		return nil
+	}
+	metadata, err := reg.GetModuleMetadata(ctx, name)
+	if err != nil || !slices.Contains(metadata.Versions, version) {
+		return nil
+	}
+	return &RegistryInconsistencyError{Module: name, Version: version, Err: fetchErr}
+}
+
+// missingModule is a module version whose MODULE.bazel was not found.
+type missingModule struct {
+	registry Registry
+	err      error
+}
+
+// listedButMissingSelections checks the missing module versions that MVS
+// would have selected had their MODULE.bazel been found, and returns those
+// whose metadata.json lists them, sorted. Metadata is only fetched for these
+// versions, never for ones a higher request superseded anyway.
+func listedButMissingSelections(ctx context.Context, missing map[string]missingModule, selected map[string]*depRequest) []*RegistryInconsistencyError {
+	var inconsistencies []*RegistryInconsistencyError
+	for _, key := range slices.Sorted(maps.Keys(missing)) {
+		name, v, _ := strings.Cut(key, "@")
+		if req := selected[name]; req != nil && version.Compare(v, req.Version) <= 0 {
+			continue
+		}
+		var inconsistent *RegistryInconsistencyError
+		if errors.As(listedButMissing(ctx, missing[key].registry, name, v, missing[key].err), &inconsistent) {
+			inconsistencies = append(inconsistencies, inconsistent)
+		}
+	}
+	return inconsistencies
+}
+
+// aliasDependencies returns module with the names of its bazel_deps
+// (including nodep ones) rewritten by aliases, and the rewrites as
+// "old -> new" in declaration order. module itself is not modified; it is
+// returned unchanged if no dependency is aliased.
+func aliasDependencies(module *ModuleInfo, aliases map[string]string) (*ModuleInfo, []string) {
+	if len(aliases) == 0 {
+		return module, nil
+	}
+
+	var rewrites []string
+	rename := func(deps []Dependency) []Dependency {
+		var renamed []Dependency
+		for i, dep := range deps {
+			newName, ok := aliases[dep.Name]
+			if !ok {
+				continue
+			}
+			if renamed == nil {
+				renamed = slices.Clone(deps)
+			}
+			renamed[i].Name = newName
+			rewrites = append(rewrites, dep.Name+" -> "+newName)
+		}
+		if renamed == nil {
+			return deps
+		}
+		return renamed
+	}
+
+	deps := rename(module.Dependencies)
+	nodepDeps := rename(module.NodepDependencies)
+	if len(rewrites) == 0 {
+		return module, nil
+	}
+	aliased := *module
+	aliased.Dependencies = deps
+	aliased.NodepDependencies = nodepDeps
+	return &aliased, rewrites
+}
+
+// pinDependencies returns module with the versions in pins forced into its
+// direct dependencies: a bazel_dep on a pinned name takes the pinned version,
+// and other pinned names are added as non-dev bazel_deps in name order.
+// module itself is not modified.
+func pinDependencies(module *ModuleInfo, pins map[string]string) *ModuleInfo {
+	if len(pins) == 0 {
+		return module
+	}
+
+	pinned := *module
+	pinned.Dependencies = slices.Clone(module.Dependencies)
+	declared := make(map[string]bool, len(pins))
+	for i, dep := range pinned.Dependencies {
+		if version, ok := pins[dep.Name]; ok {
+			pinned.Dependencies[i].Version = version
+			declared[dep.Name] = true
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(pins)) {
+		if !declared[name] {
+			pinned.Dependencies = append(pinned.Dependencies, Dependency{Name: name, Version: pins[name]})
+		}
+	}
+	return &pinned
+}
+
 func removeDependency(depGraph map[string]map[string]*depRequest, moduleName, moduleVersion string) {
 	if versions, exists := depGraph[moduleName]; exists {
 		delete(versions, moduleVersion)
@@ -1126,11 +1887,22 @@ func injectBazelToolsDeps(rootModule *ModuleInfo, bazelVersion string) {
 
 // substituteYankedVersionsInGraph iterates through the dependency graph and replaces
 // yanked versions with non-yanked alternatives in the same compatibility level.
-func (r *dependencyResolver) substituteYankedVersionsInGraph(ctx context.Context, depGraph map[string]map[string]*depRequest) {
+// Versions permitted by AllowYankedVersions are kept. It returns the
+// substitutions made.
+func (r *dependencyResolver) substituteYankedVersionsInGraph(ctx context.Context, depGraph map[string]map[string]*depRequest) []YankedVersionInfo {
+	allowedYanked := buildAllowedYankedSet(r.options.AllowYankedVersions)
+	if allowedYanked["all"] {
+		return nil
+	}
+
+	var substitutions []YankedVersionInfo
 	for moduleName, versions := range depGraph {
 		// Collect replacements to avoid modifying map during iteration
 		replacements := make(map[string]string)
 		for ver := range versions {
+			if allowedYanked[moduleName+"@"+ver] {
+				continue
+			}
 			replacement := r.findNonYankedVersion(ctx, moduleName, ver)
 			if replacement != ver {
 				replacements[ver] = replacement
@@ -1143,8 +1915,21 @@ func (r *dependencyResolver) substituteYankedVersionsInGraph(ctx context.Context
 			delete(versions, oldVer)
 			req.Version = newVer
 			versions[newVer] = req
+
+			// Metadata was fetched by findNonYankedVersion and is cached.
+			var reason string
+			if metadata, err := r.registry.GetModuleMetadata(ctx, moduleName); err == nil {
+				reason = metadata.YankReason(oldVer)
+			}
+			substitutions = append(substitutions, YankedVersionInfo{
+				Module:          moduleName,
+				Version:         oldVer,
+				Reason:          reason,
+				SubstitutedWith: newVer,
+			})
 		}
 	}
+	return substitutions
 }
 
 // findNonYankedVersion finds a non-yanked replacement for a yanked version.
@@ -1210,12 +1995,16 @@ func indexOverrides(overrides []Override) map[string]Override {
 	return index
 }
 
-// checkFieldCompatibility checks if bzlmod fields used in the root module are
-// compatible with the target Bazel version. Returns warning messages for any
-// unsupported fields.
+// checkFieldCompatibility checks if bzlmod fields and directives used in the
+// root module are compatible with the target Bazel version. Returns a warning
+// message, naming the feature and the Bazel version it needs, for each
+// unsupported one. The minimum versions come from the table in
+// internal/compat, so supporting a new feature only needs a table entry and,
+// for a field rather than a directive, a check here for its use.
 //
 // Currently checks:
-// - max_compatibility_level (requires Bazel 7.0.0+)
+// - max_compatibility_level and repo_name = None (nodep_dep) on bazel_dep
+// - every directive in the compat table, e.g. include or override_repo
 func checkFieldCompatibility(rootModule *ModuleInfo, bazelVersion string) []string {
 	if bazelVersion == "" {
 		return nil
@@ -1223,21 +2012,167 @@ func checkFieldCompatibility(rootModule *ModuleInfo, bazelVersion string) []stri
 
 	var warnings []string
 
-	// Check max_compatibility_level usage in dependencies
-	for _, dep := range rootModule.Dependencies {
-		if dep.MaxCompatibilityLevel > 0 {
-			if w := compat.CheckField(bazelVersion, "max_compatibility_level"); w != nil {
-				warnings = append(warnings,
-					fmt.Sprintf("bazel_dep(%s): %s", dep.Name, w.String()))
-				// Only warn once for this field type
-				break
-			}
+	// Fields of bazel_dep warn once, naming the first dependency using them.
+	checkDepField := func(field string, deps []Dependency, uses func(Dependency) bool) {
+		i := slices.IndexFunc(deps, uses)
+		if i < 0 {
+			return
+		}
+		if w := compat.CheckField(bazelVersion, field); w != nil {
+			warnings = append(warnings, fmt.Sprintf("bazel_dep(%s): %s", deps[i].Name, w.String()))
+		}
+	}
+	checkDepField("max_compatibility_level", rootModule.Dependencies, func(dep Dependency) bool {
+		return dep.MaxCompatibilityLevel > 0
+	})
+	checkDepField("nodep_dep", rootModule.NodepDependencies, func(Dependency) bool { return true })
+
+	directives := rootModule.Directives
+	if len(rootModule.Includes) > 0 {
+		directives = appendDirective(slices.Clone(directives), "include")
+	}
+	for _, directive := range directives {
+		if req := compat.GetRequirement(directive); req == nil || req.Location != compat.LocationModule {
+			continue
+		}
+		if w := compat.CheckField(bazelVersion, directive); w != nil {
+			warnings = append(warnings, fmt.Sprintf("%s(): %s", directive, w.String()))
 		}
 	}
 
 	return warnings
 }
 
+// collectExtensionRepos builds ResolutionList.ExtensionRepos from the root
+// module and the selected versions of all resolved modules. Returns nil if no
+// module imports extension repos.
+func (r *dependencyResolver) collectExtensionRepos(root *ModuleInfo, modules []ModuleToResolve, byKey map[string][]string) map[string][]string {
+	result := make(map[string][]string)
+
+	rootRepos := slices.Clone(root.ExtensionRepos)
+	if r.options.IncludeDevDeps {
+		rootRepos = append(rootRepos, root.DevExtensionRepos...)
+	}
+	if len(rootRepos) > 0 {
+		result[root.Name] = rootRepos
+	}
+
+	for _, module := range modules {
+		if repos, ok := byKey[module.Key()]; ok {
+			result[module.Name] = slices.Clone(repos)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// compatLevelAdvances returns, in module order, the modules the root requested
+// whose selected version has a higher compatibility level than the requested
+// one. Versions whose MODULE.bazel was not fetched (e.g. non-registry
+// overrides) are skipped.
+func compatLevelAdvances(modules []ModuleToResolve, depGraph map[string]map[string]*depRequest, infos map[string]*ModuleInfo) []CompatLevelAdvance {
+	var advances []CompatLevelAdvance
+	for _, module := range modules {
+		selected := infos[module.Key()]
+		if selected == nil {
+			continue
+		}
+		for v, req := range depGraph[module.Name] {
+			if v == module.Version || !slices.Contains(req.RequiredBy, "<root>") {
+				continue
+			}
+			requested := infos[module.Name+"@"+v]
+			if requested == nil || selected.CompatibilityLevel <= requested.CompatibilityLevel {
+				continue
+			}
+			advances = append(advances, CompatLevelAdvance{
+				Module:                      module.Name,
+				RequestedVersion:            v,
+				RequestedCompatibilityLevel: requested.CompatibilityLevel,
+				SelectedVersion:             module.Version,
+				SelectedCompatibilityLevel:  selected.CompatibilityLevel,
+			})
+		}
+	}
+	return advances
+}
+
+// preOneMinorBumps returns a message for every request of a 0.x version whose
+// selected version is also 0.x but has a different minor, in module order.
+//
+// Versions only prefetched for a multiple_version_override, and versions that
+// are themselves selected alongside another under one, were not bumped and
+// are skipped.
+func preOneMinorBumps(modules []ModuleToResolve, depGraph map[string]map[string]*depRequest) []string {
+	selected := make(map[string]bool, len(modules))
+	for _, module := range modules {
+		selected[module.Name+"@"+module.Version] = true
+	}
+
+	var bumps []string
+	for _, module := range modules {
+		selectedMinor, ok := preOneMinor(module.Version)
+		if !ok {
+			continue
+		}
+		requested := slices.Collect(maps.Keys(depGraph[module.Name]))
+		version.Sort(requested)
+		for _, v := range requested {
+			if minor, ok := preOneMinor(v); !ok || minor == selectedMinor {
+				continue
+			}
+			req := depGraph[module.Name][v]
+			if req.Prefetched || selected[module.Name+"@"+v] {
+				continue
+			}
+			bumps = append(bumps, fmt.Sprintf("module %s: selected %s crosses a 0.x minor boundary from %s requested by %s",
+				module.Name, module.Version, v, strings.Join(req.RequiredBy, ", ")))
+		}
+	}
+	return bumps
+}
+
+// preOneMinor returns the minor component of a 0.x version.
+func preOneMinor(v string) (uint64, bool) {
+	parsed, err := version.Parse(v)
+	if err != nil || len(parsed.Release) < 2 {
+		return 0, false
+	}
+	major, minor := parsed.Release[0], parsed.Release[1]
+	if !major.IsDigitsOnly || major.AsNumber != 0 || !minor.IsDigitsOnly {
+		return 0, false
+	}
+	return minor.AsNumber, true
+}
+
+// contradictoryOverride describes the override module, fetched at version
+// because of the root override, declares on itself that would select a
+// different version or source, e.g. single_version_override(A, "2.0")
+// fetching an A@2.0 that pins A to 1.0. It returns "" if there is none.
+//
+// Bazel ignores non-root overrides, so such an override never takes effect,
+// but it almost always means the root override points at the wrong registry
+// or version.
+func contradictoryOverride(override Override, version string, module *ModuleInfo) string {
+	for _, own := range module.Overrides {
+		if own.ModuleName != override.ModuleName {
+			continue
+		}
+		if own.Type == overrideTypeSingleVersion && (own.Version == "" || own.Version == version) {
+			continue
+		}
+		conflict := own.Type + "_override"
+		if own.Version != "" {
+			conflict += " to version " + own.Version
+		}
+		return conflict
+	}
+	return ""
+}
+
 // calculateModuleDepths computes the shortest path length from root to each module using BFS.
 // Returns a map from module name to depth (1 = direct dependency, 2+ = transitive).
 func calculateModuleDepths(rootDeps []string, moduleDeps map[string][]string, selected map[string]bool) map[string]int {
@@ -1271,3 +2206,32 @@ func calculateModuleDepths(rootDeps []string, moduleDeps map[string][]string, se
 	}
 	return depths
 }
+
+// exclusionEffects splits the names in ExcludeModules into those that
+// dropped a dependency edge of the root or of a module in the result, and
+// those that did not, each sorted and without duplicates. An exclusion only
+// seen on versions MVS did not select had no effect on the result.
+func exclusionEffects(excludeModules []string, excludedEdges map[string][]string, modules []ModuleToResolve) (applied, unused []string) {
+	if len(excludeModules) == 0 {
+		return nil, nil
+	}
+	hit := make(map[string]bool)
+	for _, name := range excludedEdges["<root>"] {
+		hit[name] = true
+	}
+	for _, module := range modules {
+		for _, name := range excludedEdges[module.Key()] {
+			hit[name] = true
+		}
+	}
+	for _, name := range excludeModules {
+		if hit[name] {
+			applied = append(applied, name)
+		} else {
+			unused = append(unused, name)
+		}
+	}
+	slices.Sort(applied)
+	slices.Sort(unused)
+	return slices.Compact(applied), slices.Compact(unused)
+}
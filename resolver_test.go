@@ -1,18 +1,22 @@
 package gobzlmod
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/albertocavalcante/go-bzlmod/graph"
 	"github.com/albertocavalcante/go-bzlmod/registry"
 	"github.com/albertocavalcante/go-bzlmod/selection/version"
 )
@@ -224,6 +228,46 @@ func TestApplyMVS(t *testing.T) {
 	}
 }
 
+func TestApplyMVS_BuildMetadataTieBreak(t *testing.T) {
+	resolver := newDependencyResolver(nil, false)
+
+	// 1.0.0+a and 1.0.0+b compare equal; the lexicographically greater
+	// full string must be selected regardless of map iteration order.
+	for range 50 {
+		depGraph := map[string]map[string]*depRequest{
+			"module_a": {
+				"1.0.0+b": {Version: "1.0.0+b", RequiredBy: []string{"x"}},
+				"1.0.0+a": {Version: "1.0.0+a", RequiredBy: []string{"y"}},
+				"1.0.0+c": {Version: "1.0.0+c", RequiredBy: []string{"z"}},
+			},
+		}
+		got := resolver.applyMVS(depGraph)
+		if v := got["module_a"].Version; v != "1.0.0+c" {
+			t.Fatalf("applyMVS() selected %s, want 1.0.0+c", v)
+		}
+	}
+}
+
+func TestPreOneMinorBumps_MultipleVersionOverride(t *testing.T) {
+	// 0.1.0 and 0.3.0 are both selected and 0.4.0 is only prefetched, so
+	// no request was bumped across a minor.
+	modules := []ModuleToResolve{
+		{Name: "lib", Version: "0.1.0"},
+		{Name: "lib", Version: "0.3.0"},
+	}
+	depGraph := map[string]map[string]*depRequest{
+		"lib": {
+			"0.1.0": {Version: "0.1.0", RequiredBy: []string{"a"}},
+			"0.3.0": {Version: "0.3.0", RequiredBy: []string{"b"}},
+			"0.4.0": {Version: "0.4.0", RequiredBy: []string{"<override>"}, Prefetched: true},
+		},
+	}
+
+	if got := preOneMinorBumps(modules, depGraph); len(got) != 0 {
+		t.Errorf("preOneMinorBumps() = %v, want none", got)
+	}
+}
+
 func TestApplyOverrides(t *testing.T) {
 	registry := newRegistryClient("https://bcr.bazel.build")
 	resolver := newDependencyResolver(registry, false)
@@ -810,7 +854,6 @@ bazel_dep(name = "transitive_dev", version = "1.0.0", dev_dependency = True)`)
 	}))
 	defer server.Close()
 
-	resolver := newDependencyResolver(newRegistryClient(server.URL), true)
 	rootModule := &ModuleInfo{
 		Name:    "root",
 		Version: "1.0.0",
@@ -820,24 +863,36 @@ bazel_dep(name = "transitive_dev", version = "1.0.0", dev_dependency = True)`)
 		},
 	}
 
-	result, err := resolver.ResolveDependencies(context.Background(), rootModule)
-	if err != nil {
-		t.Fatalf("ResolveDependencies() error = %v", err)
-	}
+	// Bazel only honors the root module's dev deps, so transitive_dev must be
+	// skipped whether or not the root's dev deps are included.
+	for _, includeDevDeps := range []bool{true, false} {
+		t.Run(fmt.Sprintf("includeDevDeps=%v", includeDevDeps), func(t *testing.T) {
+			resolver := newDependencyResolver(newRegistryClient(server.URL), includeDevDeps)
+			result, err := resolver.ResolveDependencies(context.Background(), rootModule)
+			if err != nil {
+				t.Fatalf("ResolveDependencies() error = %v", err)
+			}
 
-	modules := make(map[string]ModuleToResolve, len(result.Modules))
-	for _, m := range result.Modules {
-		modules[m.Name] = m
-	}
+			modules := make(map[string]ModuleToResolve, len(result.Modules))
+			for _, m := range result.Modules {
+				modules[m.Name] = m
+			}
 
-	if _, ok := modules["prod_parent"]; !ok {
-		t.Fatal("expected prod_parent in resolved modules")
-	}
-	if _, ok := modules["root_dev"]; !ok {
-		t.Fatal("expected root_dev in resolved modules")
-	}
-	if _, ok := modules["transitive_dev"]; ok {
-		t.Fatal("transitive_dev should be ignored because non-root dev_dependency is always ignored")
+			if _, ok := modules["prod_parent"]; !ok {
+				t.Fatal("expected prod_parent in resolved modules")
+			}
+			if _, ok := modules["root_dev"]; ok != includeDevDeps {
+				t.Fatalf("root_dev resolved = %v, want %v", ok, includeDevDeps)
+			}
+			if _, ok := modules["transitive_dev"]; ok {
+				t.Fatal("transitive_dev should be ignored because non-root dev_dependency is always ignored")
+			}
+
+			want := []string{"prod_parent@1.0.0 -> transitive_dev@1.0.0"}
+			if !reflect.DeepEqual(result.Summary.SkippedTransitiveDevDeps, want) {
+				t.Errorf("Summary.SkippedTransitiveDevDeps = %v, want %v", result.Summary.SkippedTransitiveDevDeps, want)
+			}
+		})
 	}
 }
 
@@ -1087,6 +1142,14 @@ func TestBuildDependencyGraph_DiamondDependency(t *testing.T) {
 			t.Errorf("Expected module %s in resolution list", expected)
 		}
 	}
+
+	// module_a and module_b sit at depth 1, module_c at depth 2.
+	if list.Summary.MaxDepth != 2 {
+		t.Errorf("Summary.MaxDepth = %d, want 2", list.Summary.MaxDepth)
+	}
+	if list.Summary.MaxBreadth != 2 {
+		t.Errorf("Summary.MaxBreadth = %d, want 2", list.Summary.MaxBreadth)
+	}
 }
 
 // TestBuildDependencyGraph_DeepChain tests that deep but valid chains work.
@@ -1137,6 +1200,89 @@ func TestBuildDependencyGraph_DeepChain(t *testing.T) {
 	if len(list.Modules) != chainDepth {
 		t.Errorf("Expected %d modules in chain, got %d", chainDepth, len(list.Modules))
 	}
+
+	if list.Summary.MaxDepth != chainDepth {
+		t.Errorf("Summary.MaxDepth = %d, want %d", list.Summary.MaxDepth, chainDepth)
+	}
+	if list.Summary.MaxBreadth != 1 {
+		t.Errorf("Summary.MaxBreadth = %d, want 1", list.Summary.MaxBreadth)
+	}
+}
+
+// TestResolveDependencies_ContradictoryOverrideCycle tests that a root override
+// selecting a module version that overrides itself back is reported clearly,
+// without failing resolution since Bazel ignores non-root overrides.
+func TestResolveDependencies_ContradictoryOverrideCycle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/module_a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "module_a", version = "1.0.0")`)
+		case "/modules/module_a/2.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "module_a", version = "2.0.0")
+			single_version_override(module_name = "module_a", version = "1.0.0")`)
+		case "/modules/module_b/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "module_b", version = "1.0.0")
+			single_version_override(module_name = "module_b", version = "1.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		overrides []Override
+		want      []string
+	}{
+		{
+			name: "override selects version that pins itself elsewhere",
+			overrides: []Override{
+				{Type: overrideTypeSingleVersion, ModuleName: "module_a", Version: "2.0.0", Registry: server.URL},
+			},
+			want: []string{"module_a@2.0.0: single_version_override to version 1.0.0"},
+		},
+		{
+			name: "self override agreeing with selected version",
+			overrides: []Override{
+				{Type: overrideTypeSingleVersion, ModuleName: "module_b", Version: "1.0.0"},
+			},
+		},
+		{
+			name: "no root override ignores non-root overrides",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := newDependencyResolver(newRegistryClient(server.URL), false)
+			rootModule := &ModuleInfo{
+				Name:    "root",
+				Version: "1.0.0",
+				Dependencies: []Dependency{
+					{Name: "module_a", Version: "1.0.0"},
+					{Name: "module_b", Version: "1.0.0"},
+				},
+				Overrides: tt.overrides,
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			list, err := resolver.ResolveDependencies(ctx, rootModule)
+			if err != nil {
+				t.Fatalf("ResolveDependencies() error = %v", err)
+			}
+			if !slices.Equal(list.Summary.ContradictoryOverrides, tt.want) {
+				t.Errorf("Summary.ContradictoryOverrides = %v, want %v", list.Summary.ContradictoryOverrides, tt.want)
+			}
+			warned := slices.ContainsFunc(list.Warnings, func(w string) bool {
+				return strings.Contains(w, "module_a@2.0.0") && strings.Contains(w, "single_version_override to version 1.0.0")
+			})
+			if warned != (len(tt.want) > 0) {
+				t.Errorf("contradictory override warning present = %v, want %v (warnings: %v)", warned, len(tt.want) > 0, list.Warnings)
+			}
+		})
+	}
 }
 
 // TestBuildDependencyGraph_MaxDepthExceeded tests that very deep chains are rejected.
@@ -1191,6 +1337,18 @@ func TestBuildDependencyGraph_MaxDepthExceeded(t *testing.T) {
 	if !strings.Contains(errMsg, "maximum dependency depth") {
 		t.Errorf("Error message should contain 'maximum dependency depth', got: %s", errMsg)
 	}
+
+	if len(depthErr.Chain) != depthErr.Depth {
+		t.Fatalf("len(Chain) = %d, want Depth %d", len(depthErr.Chain), depthErr.Depth)
+	}
+	if depthErr.Chain[0] != "root@1.0.0" || depthErr.Chain[1] != "module_0@1.0.0" {
+		t.Errorf("Chain starts %v, want root@1.0.0 -> module_0@1.0.0", depthErr.Chain[:2])
+	}
+	deepest := depthErr.Chain[len(depthErr.Chain)-1]
+	wantMsg := "root@1.0.0 -> module_0@1.0.0 -> module_1@1.0.0 -> module_2@1.0.0 -> module_3@1.0.0 -> ... 991 more ... -> "
+	if !strings.Contains(errMsg, wantMsg) || !strings.HasSuffix(errMsg, deepest+")") {
+		t.Errorf("Error() = %q, want truncated chain ending at %s", errMsg, deepest)
+	}
 }
 
 // TestBuildDependencyGraph_SelfReference tests module depending on itself.
@@ -1484,6 +1642,73 @@ func TestOnProgress_ModuleInfoInEvents(t *testing.T) {
 	}
 }
 
+// TestOnProgress_VersionSelected tests that every MVS decision is reported
+// with the versions it chose from.
+func TestOnProgress_VersionSelected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/module_a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "module_a", version = "1.0.0")
+			bazel_dep(name = "module_b", version = "1.10.0")`)
+		case "/modules/module_b/1.2.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "module_b", version = "1.2.0")`)
+		case "/modules/module_b/1.10.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "module_b", version = "1.10.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var selections []ProgressEvent
+	var mu sync.Mutex
+
+	registry := newRegistryClient(server.URL)
+	resolver := newDependencyResolverWithOptions(registry, ResolutionOptions{
+		OnProgress: func(event ProgressEvent) {
+			if event.Type != ProgressVersionSelected {
+				return
+			}
+			mu.Lock()
+			selections = append(selections, event)
+			mu.Unlock()
+		},
+	})
+
+	rootModule := &ModuleInfo{
+		Name:    "root",
+		Version: "1.0.0",
+		Dependencies: []Dependency{
+			{Name: "module_a", Version: "1.0.0"},
+			{Name: "module_b", Version: "1.2.0"},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_, err := resolver.ResolveDependencies(ctx, rootModule)
+	if err != nil {
+		t.Fatalf("ResolveDependencies() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	want := []ProgressEvent{
+		{Type: ProgressVersionSelected, Module: "module_a", Version: "1.0.0", Candidates: []string{"1.0.0"}},
+		{Type: ProgressVersionSelected, Module: "module_b", Version: "1.10.0", Candidates: []string{"1.2.0", "1.10.0"}},
+	}
+	if len(selections) != len(want) {
+		t.Fatalf("got %d version_selected events, want %d: %+v", len(selections), len(want), selections)
+	}
+	for i, e := range selections {
+		if e.Module != want[i].Module || e.Version != want[i].Version || !slices.Equal(e.Candidates, want[i].Candidates) {
+			t.Errorf("event %d = %+v, want %+v", i, e, want[i])
+		}
+	}
+}
+
 // TestCalculateModuleDepths tests the BFS-based depth calculation.
 func TestCalculateModuleDepths(t *testing.T) {
 	tests := []struct {
@@ -2268,6 +2493,27 @@ func TestCheckFieldCompatibility(t *testing.T) {
 			bazelVersion: "6.6.0",
 			wantWarnings: 1, // Only one warning for the field, not per dependency
 		},
+		{
+			name: "directives newer than the target version",
+			rootModule: &ModuleInfo{
+				Name:       "root",
+				Version:    "1.0.0",
+				Directives: []string{"module", "bazel_dep", "use_repo_rule", "override_repo", "inject_repo"},
+			},
+			bazelVersion: "7.4.0",
+			wantWarnings: 2, // override_repo and inject_repo need 8.0.0
+		},
+		{
+			name: "include and nodep deps with Bazel 7.1.0",
+			rootModule: &ModuleInfo{
+				Name:              "root",
+				Version:           "1.0.0",
+				NodepDependencies: []Dependency{{Name: "dep_a", Version: "1.0.0", IsNodepDep: true}},
+				Includes:          []string{"//:deps.MODULE.bazel"},
+			},
+			bazelVersion: "7.1.0",
+			wantWarnings: 2,
+		},
 		{
 			name: "empty bazel version returns no warnings",
 			rootModule: &ModuleInfo{
@@ -2293,6 +2539,34 @@ func TestCheckFieldCompatibility(t *testing.T) {
 	}
 }
 
+// TestCheckFieldCompatibility_ParsedModule tests the warnings for a parsed
+// MODULE.bazel, which records the directives it calls.
+func TestCheckFieldCompatibility_ParsedModule(t *testing.T) {
+	info, err := ParseModuleContent(`module(name = "root", version = "1.0.0")
+bazel_dep(name = "dep_a", version = "1.0.0", repo_name = None)
+http_archive = use_repo_rule("@bazel_tools//tools/build_defs/repo:http.bzl", "http_archive")
+http_archive(name = "data", url = "https://example.com/data.tar.gz")
+include("//:deps.MODULE.bazel")
+`)
+	if err != nil {
+		t.Fatalf("ParseModuleContent() error = %v", err)
+	}
+	wantDirectives := []string{"module", "bazel_dep", "use_repo_rule", "http_archive", "include"}
+	if !slices.Equal(info.Directives, wantDirectives) {
+		t.Errorf("Directives = %v, want %v", info.Directives, wantDirectives)
+	}
+
+	got := checkFieldCompatibility(info, "6.6.0")
+	want := []string{
+		"bazel_dep(dep_a): nodep_dep requires Bazel 7.6.0+, but target is 6.6.0",
+		"use_repo_rule(): use_repo_rule requires Bazel 7.0.0+, but target is 6.6.0",
+		"include(): include requires Bazel 7.2.0+, but target is 6.6.0",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("checkFieldCompatibility() = %q, want %q", got, want)
+	}
+}
+
 // TestResolutionSummary_FieldWarnings tests that FieldWarnings are populated in the summary.
 func TestResolutionSummary_FieldWarnings(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -2757,3 +3031,396 @@ func TestResolveDependencies_WideFanoutDoesNotDeadlock(t *testing.T) {
 		t.Fatalf("Summary.TotalModules = %d, want %d", result.Summary.TotalModules, wantTotal)
 	}
 }
+
+func TestDependencyResolver_SeedModules(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/modules/app/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "app", version = "1.0.0")
+bazel_dep(name = "internal_lib", version = "2.0.0")`)
+		case "/modules/leaf/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "leaf", version = "1.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// internal_lib is not in the registry; it comes from the monorepo checkout.
+	internalLib, err := ParseModuleContent(`module(name = "internal_lib", version = "2.0.0", compatibility_level = 2)
+bazel_dep(name = "leaf", version = "1.0.0")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolver := newDependencyResolverWithOptions(nil, ResolutionOptions{Registries: []string{server.URL}})
+	if err := resolver.SeedModules(map[string]*ModuleInfo{"internal_lib@2.0.0": internalLib}); err != nil {
+		t.Fatalf("SeedModules() error = %v", err)
+	}
+
+	root := &ModuleInfo{
+		Name:         "root",
+		Version:      "1.0.0",
+		Dependencies: []Dependency{{Name: "app", Version: "1.0.0"}},
+	}
+	list, err := resolver.ResolveDependencies(context.Background(), root)
+	if err != nil {
+		t.Fatalf("ResolveDependencies() error = %v", err)
+	}
+
+	if m := list.Module("internal_lib"); m == nil || m.Version != "2.0.0" {
+		t.Fatalf("internal_lib = %+v, want 2.0.0", m)
+	}
+	if !list.HasModule("leaf") {
+		t.Error("leaf, a dependency of the seeded module, was not resolved")
+	}
+	if node := list.Graph.Get(graph.ModuleKey{Name: "internal_lib", Version: "2.0.0"}); node == nil || node.CompatibilityLevel != 2 {
+		t.Errorf("internal_lib graph node = %+v, want compatibility level 2", node)
+	}
+	for _, path := range requested {
+		if strings.Contains(path, "internal_lib") {
+			t.Errorf("registry was asked for seeded module: %s", path)
+		}
+	}
+}
+
+func TestSeedModules_InvalidKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		seeds map[string]*ModuleInfo
+	}{
+		{"missing version", map[string]*ModuleInfo{"lib": {Name: "lib"}}},
+		{"nil info", map[string]*ModuleInfo{"lib@1.0.0": nil}},
+		{"name mismatch", map[string]*ModuleInfo{"lib@1.0.0": {Name: "other"}}},
+		{"version mismatch", map[string]*ModuleInfo{"lib@1.0.0": {Name: "lib", Version: "2.0.0"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Resolve(context.Background(), ContentSource(`module(name = "root")`), WithSeedModules(tt.seeds)); err == nil {
+				t.Error("Resolve() with invalid seed: expected error")
+			}
+		})
+	}
+}
+
+func TestResolve_ModuleAliases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/lib/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "lib", version = "1.0.0")
+bazel_dep(name = "com_google_protobuf", version = "28.0")`)
+		case "/modules/protobuf/28.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "protobuf", version = "28.0")`)
+		case "/modules/protobuf/29.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "protobuf", version = "29.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "com_google_protobuf", version = "29.0")
+bazel_dep(name = "lib", version = "1.0.0")`
+
+	list, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL),
+		WithModuleAliases(map[string]string{"com_google_protobuf": "protobuf"}))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	if m := list.Module("protobuf"); m == nil || m.Version != "29.0" {
+		t.Fatalf("Module(protobuf) = %+v, want version 29.0", m)
+	}
+	if m := list.Module("com_google_protobuf"); m != nil {
+		t.Errorf("Module(com_google_protobuf) = %+v, want nil", m)
+	}
+	if !list.Graph.Contains(graph.ModuleKey{Name: "protobuf", Version: "29.0"}) {
+		t.Error("graph does not contain protobuf@29.0")
+	}
+	if lib := list.Module("lib"); lib == nil || !slices.Equal(lib.Dependencies, []string{"protobuf"}) {
+		t.Errorf("Module(lib) = %+v, want dependencies [protobuf]", lib)
+	}
+
+	want := []string{
+		"<root>: com_google_protobuf -> protobuf",
+		"lib@1.0.0: com_google_protobuf -> protobuf",
+	}
+	if !slices.Equal(list.Summary.AliasedDependencies, want) {
+		t.Errorf("Summary.AliasedDependencies = %v, want %v", list.Summary.AliasedDependencies, want)
+	}
+}
+
+func TestWithModuleAliases_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		aliases map[string]string
+	}{
+		{"empty old name", map[string]string{"": "protobuf"}},
+		{"empty new name", map[string]string{"com_google_protobuf": ""}},
+		{"self alias", map[string]string{"protobuf": "protobuf"}},
+		{"chained", map[string]string{"a": "b", "b": "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Resolve(context.Background(), ContentSource(`module(name = "root")`), WithModuleAliases(tt.aliases)); err == nil {
+				t.Error("Resolve() with invalid aliases: expected error")
+			}
+		})
+	}
+}
+
+func TestResolve_CompatLevelAdvanced(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/lib/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "lib", version = "1.0.0")
+bazel_dep(name = "proto", version = "2.0.0")`)
+		case "/modules/proto/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "proto", version = "1.0.0", compatibility_level = 1)`)
+		case "/modules/proto/2.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "proto", version = "2.0.0", compatibility_level = 2)`)
+		case "/modules/other/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "other", version = "1.0.0", compatibility_level = 1)`)
+		case "/modules/other/1.1.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "other", version = "1.1.0", compatibility_level = 1)`)
+		case "/modules/bumper/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "bumper", version = "1.0.0")
+bazel_dep(name = "other", version = "1.1.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "proto", version = "1.0.0", max_compatibility_level = 2)
+bazel_dep(name = "lib", version = "1.0.0")
+bazel_dep(name = "other", version = "1.0.0")
+bazel_dep(name = "bumper", version = "1.0.0")`
+
+	list, err := Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	// other moves up within its compatibility level, which is not reported.
+	want := []CompatLevelAdvance{{
+		Module:                      "proto",
+		RequestedVersion:            "1.0.0",
+		RequestedCompatibilityLevel: 1,
+		SelectedVersion:             "2.0.0",
+		SelectedCompatibilityLevel:  2,
+	}}
+	if !reflect.DeepEqual(list.Summary.CompatLevelAdvanced, want) {
+		t.Errorf("Summary.CompatLevelAdvanced = %+v, want %+v", list.Summary.CompatLevelAdvanced, want)
+	}
+	found := false
+	for _, w := range list.Warnings {
+		if strings.Contains(w, "module proto: selected 2.0.0 (compatibility_level 2)") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Warnings = %v, want a compatibility level warning for proto", list.Warnings)
+	}
+}
+
+func TestResolve_Deterministic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/a/1.0.0/MODULE.bazel", "/modules/b/1.0.0/MODULE.bazel", "/modules/c/1.0.0/MODULE.bazel":
+			name := strings.Split(r.URL.Path, "/")[2]
+			// Finish in reverse name order so fetch order differs from sorted order.
+			time.Sleep(time.Duration('d'-name[0]) * 10 * time.Millisecond)
+			fmt.Fprintf(w, `module(name = %q, version = "1.0.0")
+bazel_dep(name = "zlib", version = "1.0.0")
+bazel_dep(name = "shared", version = "1.0.0")`, name)
+		case "/modules/shared/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "shared", version = "1.0.0")`)
+		case "/modules/zlib/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "zlib", version = "1.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "c", version = "1.0.0")
+bazel_dep(name = "a", version = "1.0.0")
+bazel_dep(name = "b", version = "1.0.0")`
+
+	var first []byte
+	for range 3 {
+		list, err := Resolve(context.Background(), ContentSource(content),
+			WithRegistries(server.URL), WithDeterministic())
+		if err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+
+		if got, want := list.Module("shared").RequiredBy, []string{"a@1.0.0", "b@1.0.0", "c@1.0.0"}; !slices.Equal(got, want) {
+			t.Errorf("shared RequiredBy = %v, want %v", got, want)
+		}
+		if got, want := list.Module("a").Dependencies, []string{"shared", "zlib"}; !slices.Equal(got, want) {
+			t.Errorf("a Dependencies = %v, want %v", got, want)
+		}
+		shared := list.Graph.Modules[graph.ModuleKey{Name: "shared", Version: "1.0.0"}]
+		if !slices.IsSortedFunc(shared.Dependents, compareModuleKeys) {
+			t.Errorf("shared Dependents = %v, want sorted", shared.Dependents)
+		}
+
+		data, err := json.Marshal(list)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		if first == nil {
+			first = data
+		} else if !bytes.Equal(data, first) {
+			t.Errorf("serialized results differ between runs:\n%s\n%s", first, data)
+		}
+	}
+}
+
+func TestResolve_MaxDependencyDepth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var i int
+		if _, err := fmt.Sscanf(r.URL.Path, "/modules/m%d/1.0.0/MODULE.bazel", &i); err != nil || i >= 10 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, "module(name = \"m%d\", version = \"1.0.0\")\n", i)
+		if i < 9 {
+			fmt.Fprintf(w, "bazel_dep(name = \"m%d\", version = \"1.0.0\")\n", i+1)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "m0", version = "1.0.0")`
+
+	// root plus m0..m9 is a chain of 11.
+	_, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithMaxDependencyDepth(5))
+	var depthErr *MaxDepthExceededError
+	if !errors.As(err, &depthErr) {
+		t.Fatalf("Resolve() error = %v, want MaxDepthExceededError", err)
+	}
+	if depthErr.MaxDepth != 5 || depthErr.Depth != 6 {
+		t.Errorf("MaxDepth = %d, Depth = %d; want 5, 6", depthErr.MaxDepth, depthErr.Depth)
+	}
+
+	list, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithMaxDependencyDepth(11))
+	if err != nil {
+		t.Fatalf("Resolve() with limit 11 error = %v", err)
+	}
+	if len(list.Modules) != 10 {
+		t.Errorf("resolved %d modules, want 10", len(list.Modules))
+	}
+}
+
+func TestResolve_DirectDepsMode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/app/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "app", version = "1.0.0")
+bazel_dep(name = "lib", version = "2.0.0")
+bazel_dep(name = "util", version = "1.5.0")`)
+		case "/modules/lib/1.0.0/MODULE.bazel", "/modules/lib/2.0.0/MODULE.bazel",
+			"/modules/util/1.0.0/MODULE.bazel", "/modules/util/1.5.0/MODULE.bazel":
+			parts := strings.Split(r.URL.Path, "/")
+			fmt.Fprintf(w, `module(name = %q, version = %q)`, parts[2], parts[3])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "app", version = "1.0.0")
+bazel_dep(name = "lib", version = "1.0.0")
+bazel_dep(name = "util", version = "1.0.0")`
+
+	_, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithDirectDepsMode(DirectDepsError))
+	var mismatchErr *DirectDepsMismatchError
+	if !errors.As(err, &mismatchErr) {
+		t.Fatalf("Resolve() error = %v, want *DirectDepsMismatchError", err)
+	}
+	want := []DirectDepMismatch{
+		{Name: "lib", DeclaredVersion: "1.0.0", ResolvedVersion: "2.0.0"},
+		{Name: "util", DeclaredVersion: "1.0.0", ResolvedVersion: "1.5.0"},
+	}
+	if !slices.Equal(mismatchErr.Mismatches, want) {
+		t.Errorf("Mismatches = %+v, want %+v", mismatchErr.Mismatches, want)
+	}
+
+	list, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithDirectDepsMode(DirectDepsWarn))
+	if err != nil {
+		t.Fatalf("Resolve() with DirectDepsWarn error = %v", err)
+	}
+	var mismatchWarnings int
+	for _, w := range list.Warnings {
+		if strings.Contains(w, "lib") || strings.Contains(w, "util") {
+			mismatchWarnings++
+		}
+	}
+	if mismatchWarnings != 2 {
+		t.Errorf("Warnings = %v, want one per mismatch", list.Warnings)
+	}
+}
+
+func TestResolve_UnprunedModules(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "a", version = "1.0.0")
+bazel_dep(name = "b", version = "1.0.0")`)
+		case "/modules/a/2.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "a", version = "2.0.0")`)
+		case "/modules/b/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "b", version = "1.0.0")`)
+		case "/modules/c/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "c", version = "1.0.0")
+bazel_dep(name = "a", version = "2.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	list, err := Resolve(context.Background(), ContentSource(`module(name = "root", version = "1.0.0")
+bazel_dep(name = "a", version = "1.0.0")
+bazel_dep(name = "c", version = "1.0.0")`), WithRegistries(server.URL))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	var keys []string
+	for _, m := range list.UnprunedModules {
+		keys = append(keys, m.Key())
+	}
+	want := []string{"a@1.0.0", "a@2.0.0", "b@1.0.0", "c@1.0.0"}
+	if !slices.Equal(keys, want) {
+		t.Fatalf("UnprunedModules = %v, want %v", keys, want)
+	}
+
+	passedOver := list.UnprunedModules[0]
+	if !slices.Equal(passedOver.RequiredBy, []string{"<root>"}) || !slices.Equal(passedOver.Dependencies, []string{"b"}) {
+		t.Errorf("a@1.0.0 = %+v, want required by <root> and depending on b", passedOver)
+	}
+	if list.Module("a").Version != "2.0.0" {
+		t.Errorf("selected a = %s, want 2.0.0", list.Module("a").Version)
+	}
+	if selected := list.UnprunedModules[1]; selected.Registry != list.Module("a").Registry || selected.Depth != list.Module("a").Depth {
+		t.Errorf("a@2.0.0 = %+v, want a copy of the Modules entry", selected)
+	}
+}
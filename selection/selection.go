@@ -68,7 +68,9 @@ func Run(graph *DepGraph, overrides map[string]Override) (*Result, error) {
 	selectedVersions := make(map[SelectionGroup]string)
 	for key, group := range selectionGroups {
 		existing, ok := selectedVersions[group]
-		if !ok || version.Compare(key.Version, existing) > 0 {
+		// Build-metadata-only ties are broken by full string so the choice
+		// does not depend on map iteration order.
+		if !ok || version.CompareDeterministic(key.Version, existing) > 0 {
 			selectedVersions[group] = key.Version
 		}
 	}
@@ -169,6 +171,7 @@ func tryStrategy(
 			Deps:        newDeps,
 			NodepDeps:   newNodepDeps,
 			CompatLevel: module.CompatLevel,
+			Registry:    moduleRegistry(module, overrides),
 		}
 	}
 
@@ -179,6 +182,24 @@ func tryStrategy(
 	}, nil
 }
 
+// moduleRegistry returns the registry module is fetched from: the one named
+// by an override on its name, or else the one it already carries.
+func moduleRegistry(module *Module, overrides map[string]Override) string {
+	var registry string
+	switch o := overrides[module.Key.Name].(type) {
+	case *RegistryOverride:
+		registry = o.Registry
+	case *SingleVersionOverride:
+		registry = o.Registry
+	case *MultipleVersionOverride:
+		registry = o.Registry
+	}
+	if registry == "" {
+		return module.Registry
+	}
+	return registry
+}
+
 // computeAllowedVersionSets computes a mapping from (moduleName, compatLevel)
 // to the set of allowed versions for modules with multiple-version overrides.
 //
@@ -379,6 +400,7 @@ func (w *depGraphWalker) walk(resolutionStrategy func(DepSpec) string) (map[Modu
 			Deps:        newDeps,
 			NodepDeps:   newNodepDeps,
 			CompatLevel: oldModule.CompatLevel,
+			Registry:    moduleRegistry(oldModule, w.overrides),
 		}
 
 		// Check for conflicts
@@ -458,6 +480,13 @@ func (w *depGraphWalker) visit(key ModuleKey, module *Module, from *ModuleKey, m
 						"with compatibility level %d which is different",
 					from, key, module.CompatLevel,
 					existing.dependent, existing.key, existing.compatLevel),
+				ModuleName:   key.Name,
+				VersionA:     key.Version,
+				CompatLevelA: module.CompatLevel,
+				DependentA:   derefKey(from),
+				VersionB:     existing.key.Version,
+				CompatLevelB: existing.compatLevel,
+				DependentB:   derefKey(existing.dependent),
 			}
 		}
 		moduleByName[module.Key.Name] = existingModule{
@@ -470,6 +499,14 @@ func (w *depGraphWalker) visit(key ModuleKey, module *Module, from *ModuleKey, m
 	return nil
 }
 
+// derefKey returns *key, or the zero ModuleKey if key is nil.
+func derefKey(key *ModuleKey) ModuleKey {
+	if key == nil {
+		return ModuleKey{}
+	}
+	return *key
+}
+
 // resolutionResult represents a possible resolution for a DepSpec.
 // It contains the target module's version and compatibility level.
 //
@@ -545,7 +582,7 @@ func computePossibleResolutionResultsForOneDepSpec(
 		// If we already have one for this compat level, keep the lower version
 		// (to try simpler resolutions first)
 		existing, hasExisting := resultsByCompat[group.CompatLevel]
-		if !hasExisting || version.Compare(selectedVersion, existing) < 0 {
+		if !hasExisting || version.CompareDeterministic(selectedVersion, existing) < 0 {
 			resultsByCompat[group.CompatLevel] = selectedVersion
 		}
 	}
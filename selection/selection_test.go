@@ -161,6 +161,56 @@ func TestCompatibilityLevelSelection(t *testing.T) {
 	}
 }
 
+// TestCompatibilityLevelConflict_Structured tests that a compatibility-level
+// conflict reports both sides in SelectionError's structured fields.
+func TestCompatibilityLevelConflict_Structured(t *testing.T) {
+	// Given: root -> A@1.0 (compat=1), root -> B@1.0 -> A@2.0 (compat=2)
+	graph := &DepGraph{
+		Modules: map[ModuleKey]*Module{
+			{Name: "<root>", Version: ""}: {
+				Key: ModuleKey{Name: "<root>", Version: ""},
+				Deps: []DepSpec{
+					{Name: "A", Version: "1.0", MaxCompatibilityLevel: -1},
+					{Name: "B", Version: "1.0", MaxCompatibilityLevel: -1},
+				},
+			},
+			{Name: "A", Version: "1.0"}: {
+				Key:         ModuleKey{Name: "A", Version: "1.0"},
+				CompatLevel: 1,
+			},
+			{Name: "B", Version: "1.0"}: {
+				Key:  ModuleKey{Name: "B", Version: "1.0"},
+				Deps: []DepSpec{{Name: "A", Version: "2.0", MaxCompatibilityLevel: -1}},
+			},
+			{Name: "A", Version: "2.0"}: {
+				Key:         ModuleKey{Name: "A", Version: "2.0"},
+				CompatLevel: 2,
+			},
+		},
+		RootKey: ModuleKey{Name: "<root>", Version: ""},
+	}
+
+	_, err := Run(graph, nil)
+	selErr, ok := err.(*SelectionError)
+	if !ok {
+		t.Fatalf("Expected *SelectionError, got %T (%v)", err, err)
+	}
+	want := SelectionError{
+		Code:         "VERSION_RESOLUTION_ERROR",
+		Message:      selErr.Message,
+		ModuleName:   "A",
+		VersionA:     "2.0",
+		CompatLevelA: 2,
+		DependentA:   ModuleKey{Name: "B", Version: "1.0"},
+		VersionB:     "1.0",
+		CompatLevelB: 1,
+		DependentB:   ModuleKey{Name: "<root>", Version: ""},
+	}
+	if *selErr != want {
+		t.Errorf("SelectionError = %+v, want %+v", *selErr, want)
+	}
+}
+
 // TestSingleVersionOverride tests that single_version_override forces a specific version.
 func TestSingleVersionOverride(t *testing.T) {
 	// Given: B@1.0 and B@2.0 in graph, override forces B@1.5
@@ -202,6 +252,63 @@ func TestSingleVersionOverride(t *testing.T) {
 	}
 }
 
+// TestRegistryOverride tests that modules are tagged with the registry an
+// override names, without the override affecting which version is selected.
+func TestRegistryOverride(t *testing.T) {
+	// Given: root -> A@1.0 -> B@1.0, root -> B@2.0, and C pinned by a
+	// single_version_override that also names a registry.
+	graph := &DepGraph{
+		Modules: map[ModuleKey]*Module{
+			{Name: "<root>", Version: ""}: {
+				Key:  ModuleKey{Name: "<root>", Version: ""},
+				Deps: []DepSpec{{Name: "A", Version: "1.0"}, {Name: "B", Version: "2.0"}, {Name: "C", Version: "1.0"}},
+			},
+			{Name: "A", Version: "1.0"}: {
+				Key:  ModuleKey{Name: "A", Version: "1.0"},
+				Deps: []DepSpec{{Name: "B", Version: "1.0"}},
+			},
+			{Name: "B", Version: "1.0"}: {Key: ModuleKey{Name: "B", Version: "1.0"}},
+			{Name: "B", Version: "2.0"}: {Key: ModuleKey{Name: "B", Version: "2.0"}},
+			{Name: "C", Version: "1.0"}: {Key: ModuleKey{Name: "C", Version: "1.0"}},
+		},
+		RootKey: ModuleKey{Name: "<root>", Version: ""},
+	}
+
+	overrides := map[string]Override{
+		"B": &RegistryOverride{Registry: "https://registry.example.com"},
+		"C": &SingleVersionOverride{Version: "1.0", Registry: "https://other.example.com"},
+	}
+
+	result, err := Run(graph, overrides)
+	if err != nil {
+		t.Fatalf("Selection.Run() error = %v", err)
+	}
+
+	tests := []struct {
+		key  ModuleKey
+		want string
+	}{
+		{ModuleKey{Name: "A", Version: "1.0"}, ""},
+		{ModuleKey{Name: "B", Version: "2.0"}, "https://registry.example.com"},
+		{ModuleKey{Name: "C", Version: "1.0"}, "https://other.example.com"},
+	}
+	for _, tt := range tests {
+		module, ok := result.ResolvedGraph[tt.key]
+		if !ok {
+			t.Errorf("Expected %v to be selected, got: %v", tt.key, keys(result.ResolvedGraph))
+			continue
+		}
+		if module.Registry != tt.want {
+			t.Errorf("%v Registry = %q, want %q", tt.key, module.Registry, tt.want)
+		}
+	}
+
+	// The unpruned graph is tagged too.
+	if got := result.UnprunedGraph[ModuleKey{Name: "B", Version: "1.0"}].Registry; got != "https://registry.example.com" {
+		t.Errorf("unpruned B@1.0 Registry = %q, want override registry", got)
+	}
+}
+
 // TestDiamondDependency tests the classic diamond dependency pattern.
 func TestDiamondDependency(t *testing.T) {
 	// Given: Diamond pattern
@@ -969,3 +1076,38 @@ func TestStrategyEnumeration_NoMaxCompatLevel(t *testing.T) {
 		t.Errorf("Expected 1 strategy when no max_compatibility_level, got %d", len(strategies))
 	}
 }
+
+func TestBuildMetadataTieBreakIsDeterministic(t *testing.T) {
+	// root -> A@1.0 -> B@1.0+a
+	//      -> C@1.0 -> B@1.0+b
+	// Both B versions compare equal; B@1.0+b must win on every run.
+	for range 50 {
+		graph := &DepGraph{
+			Modules: map[ModuleKey]*Module{
+				{Name: "<root>", Version: ""}: {
+					Key:  ModuleKey{Name: "<root>", Version: ""},
+					Deps: []DepSpec{{Name: "A", Version: "1.0"}, {Name: "C", Version: "1.0"}},
+				},
+				{Name: "A", Version: "1.0"}: {
+					Key:  ModuleKey{Name: "A", Version: "1.0"},
+					Deps: []DepSpec{{Name: "B", Version: "1.0+a"}},
+				},
+				{Name: "C", Version: "1.0"}: {
+					Key:  ModuleKey{Name: "C", Version: "1.0"},
+					Deps: []DepSpec{{Name: "B", Version: "1.0+b"}},
+				},
+				{Name: "B", Version: "1.0+a"}: {Key: ModuleKey{Name: "B", Version: "1.0+a"}},
+				{Name: "B", Version: "1.0+b"}: {Key: ModuleKey{Name: "B", Version: "1.0+b"}},
+			},
+			RootKey: ModuleKey{Name: "<root>", Version: ""},
+		}
+
+		result, err := Run(graph, nil)
+		if err != nil {
+			t.Fatalf("Selection.Run() error = %v", err)
+		}
+		if _, ok := result.ResolvedGraph[ModuleKey{Name: "B", Version: "1.0+b"}]; !ok {
+			t.Fatalf("Expected B@1.0+b to be selected, got keys: %v", keys(result.ResolvedGraph))
+		}
+	}
+}
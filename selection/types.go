@@ -56,6 +56,12 @@ type Module struct {
 	// Introduced in Bazel 7.6+.
 	// Reference: https://github.com/bazelbuild/bazel/blob/master/src/main/java/com/google/devtools/build/lib/bazel/bzlmod/Selection.java#L397-L403
 	NodepDeps []DepSpec
+
+	// Registry is the registry the module is fetched from when an override
+	// names one: a RegistryOverride, or the Registry of a single- or
+	// multiple-version override. Empty means the default registry chain.
+	// Set by Run on the modules of the Result.
+	Registry string
 }
 
 // DepGraph represents the complete dependency graph before selection.
@@ -95,6 +101,17 @@ type MultipleVersionOverride struct {
 
 func (o *MultipleVersionOverride) isOverride() {}
 
+// RegistryOverride fetches a module from a specific registry without
+// changing which version is selected, like a single_version_override that
+// sets only registry. Run records the registry on the module in the Result,
+// so callers can attribute registry files, such as the lockfile's
+// registryFileHashes, to the right registry.
+type RegistryOverride struct {
+	Registry string
+}
+
+func (o *RegistryOverride) isOverride() {}
+
 // NonRegistryOverride represents git_override, local_path_override, or archive_override.
 // These override the module source entirely, so version becomes empty.
 //
@@ -139,9 +156,30 @@ type SelectionGroup struct {
 }
 
 // SelectionError represents an error during version selection.
+//
+// For a compatibility-level conflict, where two versions of ModuleName with
+// different compatibility levels are both reachable, the structured fields
+// describe both sides so tools can point at the bazel_dep of each dependent.
+// Side A is the dependency reached last; side B the one it conflicts with.
+// The fields are zero for other errors.
 type SelectionError struct {
 	Code    string
 	Message string
+
+	// ModuleName is the module whose versions conflict.
+	ModuleName string
+
+	// VersionA, CompatLevelA and DependentA describe one side of the
+	// conflict: DependentA depends on ModuleName@VersionA, which has
+	// compatibility level CompatLevelA.
+	VersionA     string
+	CompatLevelA int
+	DependentA   ModuleKey
+
+	// VersionB, CompatLevelB and DependentB describe the other side.
+	VersionB     string
+	CompatLevelB int
+	DependentB   ModuleKey
 }
 
 func (e *SelectionError) Error() string {
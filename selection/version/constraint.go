@@ -0,0 +1,104 @@
+package version
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// constraintOps lists the comparison operators of a constraint term. Longer
+// operators come first so ">=" is not read as ">".
+var constraintOps = []string{">=", "<=", "==", "!=", ">", "<", "="}
+
+// Constraint is a set of version bounds, such as ">=1.2.0, <2.0.0", that a
+// version must all satisfy. Versions are ordered as Compare orders them.
+//
+// Bazel itself selects versions with MVS and has no ranges; constraints are
+// for tooling, e.g. to filter the versions a registry offers before
+// resolution or to audit which selected versions fall outside a policy.
+type Constraint struct {
+	raw   string
+	terms []constraintTerm
+}
+
+type constraintTerm struct {
+	op      string
+	version ParsedVersion
+}
+
+// ParseConstraint parses a comma-separated list of terms, each an operator
+// (>=, <=, >, <, = or ==, !=) followed by a version, such as
+// ">=1.2.0, <2.0.0". A version without an operator must match exactly.
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{raw: s}
+	for term := range strings.SplitSeq(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			return Constraint{}, fmt.Errorf("bad constraint %q: empty term", s)
+		}
+
+		op := "="
+		for _, candidate := range constraintOps {
+			if rest, ok := strings.CutPrefix(term, candidate); ok {
+				op, term = candidate, strings.TrimSpace(rest)
+				break
+			}
+		}
+		if op == "==" {
+			op = "="
+		}
+		if term == "" {
+			return Constraint{}, fmt.Errorf("bad constraint %q: %s without a version", s, op)
+		}
+
+		v, err := Parse(term)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("bad constraint %q: %w", s, err)
+		}
+		c.terms = append(c.terms, constraintTerm{op: op, version: v})
+	}
+	return c, nil
+}
+
+// Matches reports whether v satisfies every term of c. A version that does
+// not parse matches nothing.
+func (c Constraint) Matches(v string) bool {
+	parsed, err := Parse(v)
+	if err != nil {
+		return false
+	}
+	for _, term := range c.terms {
+		cmp := compareParsed(parsed, term.version)
+		var ok bool
+		switch term.op {
+		case ">=":
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			ok = cmp < 0
+		case "!=":
+			ok = cmp != 0
+		default:
+			ok = cmp == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the versions that satisfy c, in their original order.
+func (c Constraint) Filter(versions []string) []string {
+	return slices.DeleteFunc(slices.Clone(versions), func(v string) bool {
+		return !c.Matches(v)
+	})
+}
+
+// String returns the constraint as it was parsed.
+func (c Constraint) String() string {
+	return c.raw
+}
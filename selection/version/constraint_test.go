@@ -0,0 +1,62 @@
+package version
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestConstraintMatches(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{">=1.2.0", "1.2.0", true},
+		{">=1.2.0", "1.10.0", true},
+		{">=1.2.0", "1.1.9", false},
+		{"<2.0.0", "2.0.0", false},
+		{"<2.0.0", "2.0.0-rc1", true}, // prereleases sort before their release
+		{">=1.2.0, <2.0.0", "1.5", true},
+		{">=1.2.0,<2.0.0", "2.1.0", false},
+		{"> 1.0", "1.0.0", true}, // 1.0.0 has more identifiers than 1.0
+		{"<=1.0.0", "1.0.0+build", true},
+		{"1.0.0", "1.0.0", true},
+		{"=1.0.0", "1.0.1", false},
+		{"==1.0.0", "1.0.0", true},
+		{"!=1.0.0", "1.0.0", false},
+		{">=1.0", "1.0.bcr.1", true},
+		{">=1.0", "not-a-version!", false},
+	}
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint)
+		if err != nil {
+			t.Fatalf("ParseConstraint(%q) error: %v", tt.constraint, err)
+		}
+		if got := c.Matches(tt.version); got != tt.want {
+			t.Errorf("ParseConstraint(%q).Matches(%q) = %v, want %v", tt.constraint, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestParseConstraint_Errors(t *testing.T) {
+	for _, s := range []string{"", ">=", ">=1.0,", ">=1.0, <", ">=1.0!"} {
+		if _, err := ParseConstraint(s); err == nil {
+			t.Errorf("ParseConstraint(%q) succeeded, want error", s)
+		}
+	}
+}
+
+func TestConstraintFilter(t *testing.T) {
+	c, err := ParseConstraint(">=1.2.0, <2.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := c.Filter([]string{"2.0.0", "1.2.0", "1.1.0", "1.9.9", "2.0.0-rc1"})
+	want := []string{"1.2.0", "1.9.9", "2.0.0-rc1"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Filter() = %v, want %v", got, want)
+	}
+	if c.String() != ">=1.2.0, <2.0.0" {
+		t.Errorf("String() = %q", c.String())
+	}
+}
@@ -120,14 +120,22 @@ func Parse(s string) (ParsedVersion, error) {
 	releaseStr := match[1]
 	prereleaseStr := match[2]
 
+	// Reference: Version.java Identifier.from rejects empty identifiers,
+	// as in "1..0" or "1.0-rc.".
 	var release []Identifier
 	for _, part := range strings.Split(releaseStr, ".") {
+		if part == "" {
+			return ParsedVersion{}, &ParseError{Version: s, Message: "release has an empty identifier"}
+		}
 		release = append(release, ParseIdentifier(part))
 	}
 
 	var prerelease []Identifier
 	if prereleaseStr != "" {
 		for _, part := range strings.Split(prereleaseStr, ".") {
+			if part == "" {
+				return ParsedVersion{}, &ParseError{Version: s, Message: "prerelease has an empty identifier"}
+			}
 			prerelease = append(prerelease, ParseIdentifier(part))
 		}
 	}
@@ -155,7 +163,9 @@ func (e *ParseError) Error() string {
 }
 
 // Compare compares two version strings.
-// Returns -1 if a < b, 0 if a == b, 1 if a > b.
+// Returns -1 if a < b, 0 if a == b, 1 if a > b. If either string is not a
+// valid version, the strings are compared lexicographically instead; see
+// CompareStrict to detect that.
 //
 // Reference: Version.java lines 182-191, COMPARATOR
 // Order:
@@ -171,7 +181,27 @@ func Compare(a, b string) int {
 	if errA != nil || errB != nil {
 		return strings.Compare(a, b)
 	}
+	return compareParsed(va, vb)
+}
+
+// CompareStrict compares two versions like Compare, but returns the
+// *ParseError of the first operand that is not a valid version instead of
+// falling back to comparing the strings. Use it where a malformed version
+// means bad data, such as a corrupt registry, rather than something to sort.
+func CompareStrict(a, b string) (int, error) {
+	va, err := Parse(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := Parse(b)
+	if err != nil {
+		return 0, err
+	}
+	return compareParsed(va, vb), nil
+}
 
+// compareParsed compares two parsed versions in Compare's order.
+func compareParsed(va, vb ParsedVersion) int {
 	// Empty versions sort LAST (higher than everything)
 	// Reference: Version.java line 183
 	if va.IsEmpty != vb.IsEmpty {
@@ -206,6 +236,21 @@ func Compare(a, b string) int {
 	return compareIdentifierLists(va.Prerelease, vb.Prerelease)
 }
 
+// CompareDeterministic compares two versions like Compare, but never reports
+// two different strings as equal. Versions that Compare considers equal yet
+// differ as strings (for example 1.0.0+a and 1.0.0+b, which differ only in
+// build metadata) are ordered lexicographically by their full string.
+//
+// Use this when picking a single winner among candidate versions, so the
+// result does not depend on iteration order: among build-metadata-only
+// variants, the lexicographically greatest string is the maximum.
+func CompareDeterministic(a, b string) int {
+	if c := Compare(a, b); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
 // compareIdentifierLists compares two lists of identifiers lexicographically.
 //
 // Reference: Version.java line 184 uses lexicographical(Identifier.COMPARATOR)
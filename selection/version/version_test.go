@@ -1,6 +1,7 @@
 package version
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -198,3 +199,64 @@ func TestMax(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareStrict(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		want    int
+		wantErr bool
+	}{
+		{"1.0.0", "1.0.1", -1, false},
+		{"1.0.0-rc1", "1.0.0", -1, false},
+		{"1.0.0+a", "1.0.0+b", 0, false},
+		{"", "1.0", 1, false},
+		{"1..0", "1.0", 0, true},
+		{"1.0", "1.0-rc.", 0, true},
+		{"1.0_0", "1.0", 0, true},
+		{"1.0", "bad version", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := CompareStrict(tt.a, tt.b)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CompareStrict(%q, %q) error = %v, wantErr %v", tt.a, tt.b, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Errorf("CompareStrict(%q, %q) error %T is not a *ParseError", tt.a, tt.b, err)
+			}
+			// Compare still orders malformed versions.
+			if Compare(tt.a, tt.b) == 0 {
+				t.Errorf("Compare(%q, %q) = 0, want lexicographic order", tt.a, tt.b)
+			}
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("CompareStrict(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCompareDeterministic(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "2.0.0", -1},
+		{"2.0.0", "1.0.0", 1},
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.0+a", "1.0.0+b", -1}, // Compare reports 0; full string breaks the tie
+		{"1.0.0+b", "1.0.0+a", 1},
+		{"1.0.0+a", "1.0.0", 1},
+		{"1.0.0+z", "1.0.1+a", -1}, // Release ordering still wins
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			if got := CompareDeterministic(tt.a, tt.b); got != tt.want {
+				t.Errorf("CompareDeterministic(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"sync"
 
 	"github.com/albertocavalcante/go-bzlmod/selection"
+	"github.com/albertocavalcante/go-bzlmod/selection/version"
 )
 
 // Override type constants.
@@ -31,6 +32,9 @@ const (
 type selectionResolver struct {
 	registry Registry
 	options  ResolutionOptions
+
+	hydratedMu      sync.RWMutex
+	hydratedModules map[selection.ModuleKey]*ModuleInfo
 }
 
 // newSelectionResolver creates a resolver using Bazel's full selection algorithm.
@@ -61,12 +65,53 @@ func newSelectionResolver(registry Registry, opts ResolutionOptions) *selectionR
 		)
 	}
 
+	reg = withRetry(reg, opts.RetryAttempts, opts.RetryBaseDelay)
+	reg = withModuleRegistries(reg, opts.ModuleRegistries)
+
 	return &selectionResolver{
 		registry: reg,
 		options:  opts,
 	}
 }
 
+// AddModuleInfo registers already-hydrated MODULE.bazel information for
+// moduleName@version. It is used in place of a registry fetch, so its
+// dependencies and compatibility_level flow into the selection DepGraph
+// unchanged. Register modules under non-registry overrides with an empty
+// version, matching the key Bazel gives them.
+//
+// This lets callers reproduce compatibility-level scenarios without serving
+// a full registry.
+func (r *selectionResolver) AddModuleInfo(moduleName, version string, moduleInfo *ModuleInfo) error {
+	if moduleName == "" {
+		return fmt.Errorf("module name is empty")
+	}
+	if moduleInfo == nil {
+		return fmt.Errorf("module info for %s@%s is nil", moduleName, version)
+	}
+
+	clone := *moduleInfo
+	if clone.Name == "" {
+		clone.Name = moduleName
+	} else if clone.Name != moduleName {
+		return fmt.Errorf("module name mismatch: %s != %s", clone.Name, moduleName)
+	}
+
+	r.hydratedMu.Lock()
+	defer r.hydratedMu.Unlock()
+	if r.hydratedModules == nil {
+		r.hydratedModules = make(map[selection.ModuleKey]*ModuleInfo)
+	}
+	r.hydratedModules[selection.ModuleKey{Name: moduleName, Version: version}] = &clone
+	return nil
+}
+
+func (r *selectionResolver) hydratedModule(key selection.ModuleKey) *ModuleInfo {
+	r.hydratedMu.RLock()
+	defer r.hydratedMu.RUnlock()
+	return r.hydratedModules[key]
+}
+
 // Resolve performs dependency resolution using Bazel's selection algorithm.
 // It returns a ResolutionList with the resolved modules and optionally an
 // unpruned view for debugging.
@@ -181,6 +226,25 @@ func (r *selectionResolver) buildDepGraph(ctx context.Context, rootModule *Modul
 	queue := make([]selection.DepSpec, len(rootDeps))
 	copy(queue, rootDeps)
 
+	// Fetch every version allowed by a multiple_version_override up front.
+	// Selection requires each allowed version to exist in the dep graph, but
+	// nothing guarantees they are reachable through transitive discovery.
+	// Versions are fetched from the regular registry chain.
+	//
+	// Reference: Selection.java computeAllowedVersionSets
+	for _, override := range rootModule.Overrides {
+		if override.Type != overrideTypeMultiple {
+			continue
+		}
+		for _, v := range override.Versions {
+			queue = append(queue, selection.DepSpec{
+				Name:                  override.ModuleName,
+				Version:               v,
+				MaxCompatibilityLevel: -1,
+			})
+		}
+	}
+
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 	errCh := make(chan error, 1)
@@ -189,7 +253,7 @@ func (r *selectionResolver) buildDepGraph(ctx context.Context, rootModule *Modul
 	defer cancel()
 
 	// Worker pool for concurrent fetching
-	sem := make(chan struct{}, defaultMaxConcurrency)
+	sem := make(chan struct{}, maxConcurrentFetches(r.options))
 
 	for {
 		// Process all current queue items
@@ -212,13 +276,17 @@ func (r *selectionResolver) buildDepGraph(ctx context.Context, rootModule *Modul
 					// Match Bazel: non-registry overrides resolve to empty version.
 					key = selection.ModuleKey{Name: dep.Name, Version: ""}
 
-					if override.Type == overrideTypeLocalPath && override.Path != "" {
-						localModule, err := parseLocalPathOverrideModule(override.Path)
+					localModule := r.hydratedModule(key)
+					if localModule == nil && override.Type == overrideTypeLocalPath && override.Path != "" {
+						var err error
+						localModule, err = parseLocalPathOverrideModule(override.Path)
 						if err != nil {
 							cancel()
 							wg.Wait()
 							return nil, fmt.Errorf("parse local_path override for %s: %w", dep.Name, err)
 						}
+					}
+					if localModule != nil {
 						localDeps := buildDepSpecs(localModule.Dependencies, false)
 						localNodepDeps := buildDepSpecs(localModule.NodepDependencies, false)
 
@@ -287,7 +355,11 @@ func (r *selectionResolver) buildDepGraph(ctx context.Context, rootModule *Modul
 					return
 				}
 
-				moduleInfo, err := r.registry.GetModuleFile(ctx, k.Name, k.Version)
+				moduleInfo := r.hydratedModule(k)
+				var err error
+				if moduleInfo == nil {
+					moduleInfo, err = r.registry.GetModuleFile(ctx, k.Name, k.Version)
+				}
 				if err != nil {
 					if !isNotFound(err) {
 						select {
@@ -524,9 +596,10 @@ func (r *selectionResolver) buildResult(ctx context.Context, result *selection.R
 		})
 	}
 	slices.SortFunc(unpruned.Modules, func(a, b ModuleToResolve) int {
-		return cmp.Compare(a.Name, b.Name)
+		return cmp.Or(cmp.Compare(a.Name, b.Name), version.Compare(a.Version, b.Version))
 	})
 	unpruned.Summary.TotalModules = len(unpruned.Modules)
+	resolved.UnprunedModules = unpruned.Modules
 	unpruned.RegistryFileHashes = cloneRegistryFileHashes(resolved.RegistryFileHashes)
 
 	// Build BFS order
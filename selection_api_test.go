@@ -480,6 +480,63 @@ local_path_override(module_name = "local_mod", path = %q)`, localPath)
 	})
 }
 
+func TestResolveWithSelection_MultipleVersionOverridePrefetchesAllowedVersions(t *testing.T) {
+	var fetchedUnreachable atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/dep_a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "dep_a", version = "1.0.0")
+bazel_dep(name = "lib", version = "1.0.0")`)
+		case "/modules/lib/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "lib", version = "1.0.0")`)
+		case "/modules/lib/2.0.0/MODULE.bazel":
+			// No module in the graph depends on lib@2.0.0.
+			fetchedUnreachable.Add(1)
+			fmt.Fprint(w, `module(name = "lib", version = "2.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// The override omits registry, so allowed versions come from the default chain.
+	moduleContent := `module(name = "test", version = "1.0.0")
+bazel_dep(name = "dep_a", version = "1.0.0")
+multiple_version_override(module_name = "lib", versions = ["1.0.0", "2.0.0"])`
+
+	opts := ResolutionOptions{
+		Registries: []string{server.URL},
+	}
+	result, err := resolveWithSelection(context.Background(), moduleContent, opts)
+	if err != nil {
+		t.Fatalf("resolveWithSelection() error = %v", err)
+	}
+
+	if got := fetchedUnreachable.Load(); got != 1 {
+		t.Errorf("lib@2.0.0 fetched %d times, want 1", got)
+	}
+
+	var libVersions []string
+	for _, m := range result.Resolved.Modules {
+		if m.Name == "lib" {
+			libVersions = append(libVersions, m.Version)
+		}
+	}
+	if len(libVersions) != 1 || libVersions[0] != "1.0.0" {
+		t.Errorf("resolved lib versions = %v, want [1.0.0]", libVersions)
+	}
+
+	foundUnpruned := false
+	for _, m := range result.Unpruned.Modules {
+		if m.Name == "lib" && m.Version == "2.0.0" {
+			foundUnpruned = true
+		}
+	}
+	if !foundUnpruned {
+		t.Error("lib@2.0.0 should be present in the unpruned graph")
+	}
+}
+
 func TestResolveWithSelection_YankedVersions(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
@@ -767,3 +824,95 @@ bazel_dep(name = "lib_j", version = "1.0.0")`
 		}
 	}
 }
+
+func TestSelectionResolver_AddModuleInfo_CompatLevel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected registry request for %s", r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	rootContent := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "A", version = "1.0")
+bazel_dep(name = "B", version = "1.0")
+bazel_dep(name = "C", version = "")
+git_override(module_name = "C", remote = "https://example.com/c.git", commit = "abc")`
+
+	newResolver := func(t *testing.T, aTwoCompat int) *selectionResolver {
+		t.Helper()
+		resolver := newSelectionResolver(nil, ResolutionOptions{Registries: []string{server.URL}})
+		modules := []struct {
+			name, version string
+			info          *ModuleInfo
+		}{
+			{"A", "1.0", &ModuleInfo{Version: "1.0", CompatibilityLevel: 1}},
+			{"A", "2.0", &ModuleInfo{Version: "2.0", CompatibilityLevel: aTwoCompat}},
+			{"B", "1.0", &ModuleInfo{Version: "1.0", Dependencies: []Dependency{{Name: "A", Version: "2.0"}}}},
+			{"C", "", &ModuleInfo{Version: "3.0", CompatibilityLevel: 3}},
+		}
+		for _, m := range modules {
+			if err := resolver.AddModuleInfo(m.name, m.version, m.info); err != nil {
+				t.Fatalf("AddModuleInfo(%s, %s) error = %v", m.name, m.version, err)
+			}
+		}
+		return resolver
+	}
+
+	root, err := ParseModuleContent(rootContent)
+	if err != nil {
+		t.Fatalf("ParseModuleContent() error = %v", err)
+	}
+
+	t.Run("compat level reaches DepGraph", func(t *testing.T) {
+		depGraph, err := newResolver(t, 2).buildDepGraph(context.Background(), root)
+		if err != nil {
+			t.Fatalf("buildDepGraph() error = %v", err)
+		}
+		want := map[selection.ModuleKey]int{
+			{Name: "A", Version: "1.0"}: 1,
+			{Name: "A", Version: "2.0"}: 2,
+			{Name: "C", Version: ""}:    3,
+		}
+		for key, level := range want {
+			module, ok := depGraph.Modules[key]
+			if !ok {
+				t.Errorf("DepGraph missing %s", key)
+				continue
+			}
+			if module.CompatLevel != level {
+				t.Errorf("%s CompatLevel = %d, want %d", key, module.CompatLevel, level)
+			}
+		}
+	})
+
+	// Mirrors selection.TestCompatibilityLevelSelection: two compat levels of
+	// one module without multiple_version_override is an error.
+	t.Run("different compat levels conflict", func(t *testing.T) {
+		if _, err := newResolver(t, 2).Resolve(context.Background(), root); err == nil {
+			t.Error("Resolve() expected error for A at compat levels 1 and 2")
+		}
+	})
+
+	t.Run("same compat level selects highest", func(t *testing.T) {
+		result, err := newResolver(t, 1).Resolve(context.Background(), root)
+		if err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		if m := result.Resolved.Module("A"); m == nil || m.Version != "2.0" {
+			t.Errorf("A = %+v, want 2.0", m)
+		}
+	})
+}
+
+func TestSelectionResolver_AddModuleInfo_Errors(t *testing.T) {
+	resolver := newSelectionResolver(nil, ResolutionOptions{Registries: []string{"https://example.com"}})
+	if err := resolver.AddModuleInfo("", "1.0", &ModuleInfo{}); err == nil {
+		t.Error("AddModuleInfo() with empty name: expected error")
+	}
+	if err := resolver.AddModuleInfo("a", "1.0", nil); err == nil {
+		t.Error("AddModuleInfo() with nil info: expected error")
+	}
+	if err := resolver.AddModuleInfo("a", "1.0", &ModuleInfo{Name: "b"}); err == nil {
+		t.Error("AddModuleInfo() with mismatched name: expected error")
+	}
+}
@@ -0,0 +1,138 @@
+package gobzlmod
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Tracer starts spans for a resolution: one "bzlmod.resolve" span covering
+// the whole resolution and a child "bzlmod.fetch_module" span for every
+// MODULE.bazel fetched. See WithTracer.
+//
+// Tracer is the subset of OpenTelemetry's trace.Tracer that go-bzlmod uses,
+// declared here so the module does not depend on OpenTelemetry. An OTel
+// tracer is plugged in with a small adapter:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, gobzlmod.Span) {
+//	    ctx, span := t.Tracer.Start(ctx, name)
+//	    return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) SetAttributes(attrs ...gobzlmod.SpanAttribute) {
+//	    for _, a := range attrs {
+//	        switch v := a.Value.(type) {
+//	        case string:
+//	            s.Span.SetAttributes(attribute.String(a.Key, v))
+//	        case bool:
+//	            s.Span.SetAttributes(attribute.Bool(a.Key, v))
+//	        case int:
+//	            s.Span.SetAttributes(attribute.Int(a.Key, v))
+//	        }
+//	    }
+//	}
+//
+//	func (s otelSpan) RecordError(err error) {
+//	    s.Span.RecordError(err)
+//	    s.Span.SetStatus(codes.Error, err.Error())
+//	}
+//
+//	func (s otelSpan) End() { s.Span.End() }
+//
+// Implementations must be safe for concurrent use: modules are fetched in
+// parallel.
+type Tracer interface {
+	// Start creates a span named name as a child of any span in ctx and
+	// returns a context carrying the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation started by a Tracer.
+type Span interface {
+	// SetAttributes annotates the span. Values are string, bool or int.
+	SetAttributes(attrs ...SpanAttribute)
+
+	// RecordError marks the span as failed with err.
+	RecordError(err error)
+
+	// End completes the span.
+	End()
+}
+
+// SpanAttribute is a key-value annotation on a Span.
+type SpanAttribute struct {
+	Key   string
+	Value any
+}
+
+// Span names and attribute keys emitted during resolution.
+const (
+	spanResolve     = "bzlmod.resolve"
+	spanFetchModule = "bzlmod.fetch_module"
+
+	attrRootName      = "bzlmod.root.name"
+	attrRootVersion   = "bzlmod.root.version"
+	attrModuleCount   = "bzlmod.module_count"
+	attrModuleName    = "bzlmod.module.name"
+	attrModuleVersion = "bzlmod.module.version"
+	attrCacheHit      = "bzlmod.cache_hit"
+	attrStatus        = "bzlmod.status"
+)
+
+// Values of the attrStatus attribute.
+const (
+	spanStatusOK       = "ok"
+	spanStatusNotFound = "not_found"
+	spanStatusError    = "error"
+)
+
+// cacheHitKey is the context key of the *atomic.Bool a traced fetch uses to
+// learn whether the registry served the module from a cache.
+type cacheHitKey struct{}
+
+// noteCacheHit records that the module fetch running under ctx was served
+// from a cache. It is a no-op unless the fetch is traced.
+func noteCacheHit(ctx context.Context) {
+	if hit, ok := ctx.Value(cacheHitKey{}).(*atomic.Bool); ok {
+		hit.Store(true)
+	}
+}
+
+// startFetchSpan starts the span of one MODULE.bazel fetch. It returns ctx
+// unchanged and a nil span if tracing is disabled.
+func (r *dependencyResolver) startFetchSpan(ctx context.Context, name, version string) (context.Context, Span) {
+	if r.options.Tracer == nil {
+		return ctx, nil
+	}
+	ctx, span := r.options.Tracer.Start(ctx, spanFetchModule)
+	span.SetAttributes(
+		SpanAttribute{Key: attrModuleName, Value: name},
+		SpanAttribute{Key: attrModuleVersion, Value: version},
+	)
+	return context.WithValue(ctx, cacheHitKey{}, new(atomic.Bool)), span
+}
+
+// endFetchSpan records the outcome of the fetch started by startFetchSpan and
+// ends its span. It is a no-op if span is nil.
+func endFetchSpan(ctx context.Context, span Span, err error) {
+	if span == nil {
+		return
+	}
+	hit, _ := ctx.Value(cacheHitKey{}).(*atomic.Bool)
+	status := spanStatusOK
+	switch {
+	case err != nil && isNotFound(err):
+		status = spanStatusNotFound
+	case err != nil:
+		status = spanStatusError
+		span.RecordError(err)
+	}
+	span.SetAttributes(
+		SpanAttribute{Key: attrCacheHit, Value: hit != nil && hit.Load()},
+		SpanAttribute{Key: attrStatus, Value: status},
+	)
+	span.End()
+}
@@ -0,0 +1,161 @@
+package gobzlmod
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// recordedSpan is a span captured by recordingTracer.
+type recordedSpan struct {
+	name   string
+	parent *recordedSpan
+	attrs  map[string]any
+	err    error
+	ended  bool
+}
+
+// recordingTracer is a Tracer that keeps every span it starts.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+type spanKey struct{}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parent, _ := ctx.Value(spanKey{}).(*recordedSpan)
+	span := &recordedSpan{name: name, parent: parent, attrs: make(map[string]any)}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), &recordingSpan{tracer: t, span: span}
+}
+
+// byModule returns the fetch spans keyed by "name@version".
+func (t *recordingTracer) byModule() map[string]*recordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	spans := make(map[string]*recordedSpan)
+	for _, s := range t.spans {
+		if s.name == spanFetchModule {
+			spans[fmt.Sprintf("%v@%v", s.attrs[attrModuleName], s.attrs[attrModuleVersion])] = s
+		}
+	}
+	return spans
+}
+
+type recordingSpan struct {
+	tracer *recordingTracer
+	span   *recordedSpan
+}
+
+func (s *recordingSpan) SetAttributes(attrs ...SpanAttribute) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	for _, a := range attrs {
+		s.span.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.span.err = err
+}
+
+func (s *recordingSpan) End() {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.span.ended = true
+}
+
+func TestResolve_WithTracer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/a/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "a", version = "1.0.0")
+bazel_dep(name = "gone", version = "1.0.0")`)
+		case "/modules/b/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "b", version = "1.0.0")`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "a", version = "1.0.0")
+bazel_dep(name = "b", version = "1.0.0")`
+	cache := NewMemoryCache()
+
+	tracer := &recordingTracer{}
+	if _, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithCache(cache), WithTracer(tracer)); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	root := tracer.spans[0]
+	if root.name != spanResolve || root.parent != nil || !root.ended {
+		t.Fatalf("first span = %+v, want an ended root %s span", root, spanResolve)
+	}
+	if root.attrs[attrRootName] != "root" || root.attrs[attrModuleCount] != 2 || root.attrs[attrStatus] != spanStatusOK {
+		t.Errorf("root span attributes = %v", root.attrs)
+	}
+
+	fetches := tracer.byModule()
+	if len(fetches) != 3 || len(tracer.spans) != 4 {
+		t.Fatalf("fetch spans = %v, want a, b and gone", fetches)
+	}
+	for key, span := range fetches {
+		if span.parent != root || !span.ended {
+			t.Errorf("%s span: parent %v, ended %v; want an ended child of the root span", key, span.parent, span.ended)
+		}
+		if span.attrs[attrCacheHit] != false {
+			t.Errorf("%s span cache hit = %v, want false", key, span.attrs[attrCacheHit])
+		}
+	}
+	if got := fetches["a@1.0.0"].attrs[attrStatus]; got != spanStatusOK {
+		t.Errorf("a@1.0.0 status = %v, want %s", got, spanStatusOK)
+	}
+	if got := fetches["gone@1.0.0"].attrs[attrStatus]; got != spanStatusNotFound {
+		t.Errorf("gone@1.0.0 status = %v, want %s", got, spanStatusNotFound)
+	}
+
+	// A second resolution is served by the shared cache.
+	tracer = &recordingTracer{}
+	if _, err := Resolve(context.Background(), ContentSource(content),
+		WithRegistries(server.URL), WithCache(cache), WithTracer(tracer)); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if got := tracer.byModule()["b@1.0.0"].attrs[attrCacheHit]; got != true {
+		t.Errorf("b@1.0.0 cache hit on second resolution = %v, want true", got)
+	}
+}
+
+func TestResolve_WithTracer_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	_, err := Resolve(context.Background(),
+		ContentSource(`module(name = "root")
+bazel_dep(name = "a", version = "1.0.0")`),
+		WithRegistries(server.URL), WithTracer(tracer))
+	if err == nil {
+		t.Fatal("Resolve() expected error")
+	}
+
+	root := tracer.spans[0]
+	if root.err == nil || root.attrs[attrStatus] != spanStatusError || !root.ended {
+		t.Errorf("root span = %+v, want an ended span with the error recorded", root)
+	}
+	if span := tracer.byModule()["a@1.0.0"]; span == nil || span.err == nil || span.attrs[attrStatus] != spanStatusError {
+		t.Errorf("a@1.0.0 span = %+v, want status error", span)
+	}
+}
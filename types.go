@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/albertocavalcante/go-bzlmod/graph"
+	"github.com/albertocavalcante/go-bzlmod/selection/version"
 )
 
 // ModuleInfo represents the information extracted from a MODULE.bazel file.
@@ -51,6 +53,36 @@ type ModuleInfo struct {
 
 	// Overrides lists all override declarations (single_version, git, etc.).
 	Overrides []Override `json:"overrides"`
+
+	// ExtensionRepos lists the repos imported from module extensions via
+	// use_repo on non-dev extension proxies, in declaration order.
+	ExtensionRepos []string `json:"extension_repos,omitempty"`
+
+	// DevExtensionRepos lists the repos imported via use_repo on extension
+	// proxies created with dev_dependency = True.
+	DevExtensionRepos []string `json:"dev_extension_repos,omitempty"`
+
+	// Includes lists the labels of the MODULE.bazel segments loaded with
+	// include(), in declaration order. Resolution merges their directives
+	// into this module only with ResolutionOptions.FollowIncludes.
+	Includes []string `json:"includes,omitempty"`
+
+	// Directives lists the names of the functions the module file calls at
+	// top level, such as "bazel_dep", "use_repo_rule" or "override_repo",
+	// each once in order of first use. Calls of extension proxy methods are
+	// not listed. The Bazel version check uses it to find directives newer
+	// than the target version.
+	Directives []string `json:"directives,omitempty"`
+
+	// UnprovidedUseRepos lists use_repo imports, as "<proxy>: <repo>", that
+	// no tag of their extension appears to create. This is a best-effort
+	// check; see findUnprovidedUseRepos for the heuristic.
+	UnprovidedUseRepos []string `json:"unprovided_use_repos,omitempty"`
+
+	// RegistryIndex is the position, in the list of registries, of the
+	// registry that served this module file. It is 0 when a single registry
+	// is used.
+	RegistryIndex int `json:"registry_index,omitempty"`
 }
 
 // Dependency represents a bazel_dep declaration in a MODULE.bazel file.
@@ -120,6 +152,10 @@ type Override struct {
 
 	// Path is the local filesystem path for local_path overrides.
 	Path string `json:"path,omitempty"`
+
+	// URLs are the download URLs for archive overrides. Like Bazel's
+	// http_archive, they are mirrors tried in order until one succeeds.
+	URLs []string `json:"urls,omitempty"`
 }
 
 // ResolutionList contains the final resolved dependency set after MVS.
@@ -131,6 +167,16 @@ type ResolutionList struct {
 	// Modules is the list of all resolved modules, sorted by name.
 	Modules []ModuleToResolve `json:"modules"`
 
+	// UnprunedModules lists every module version discovered during
+	// resolution, sorted by name and then version, including the versions
+	// version selection passed over. A name@version listed here but not in
+	// Modules was requested but not selected: its RequiredBy names who asked
+	// for it and its Dependencies what its MODULE.bazel declares, which
+	// explains modules that "disappeared" from the result. Selected entries
+	// are copies of their Modules entry; the others carry no Registry, Depth
+	// or registry metadata.
+	UnprunedModules []ModuleToResolve `json:"unpruned_modules,omitempty"`
+
 	// Summary provides aggregate statistics about the resolution.
 	Summary ResolutionSummary `json:"summary"`
 
@@ -148,10 +194,23 @@ type ResolutionList struct {
 	// registries that missed before a lower-priority registry succeeded.
 	RegistryFileHashes map[string]*string `json:"registry_file_hashes,omitempty"`
 
+	// ExtensionRepos maps a module name to the repos it imports from module
+	// extensions via use_repo. Extensions are not evaluated, so this is only
+	// a partial view of extension-provided dependencies: it records which repos
+	// each module asks for, not what they contain. The root module is included
+	// under its own name; its dev extension repos are included only when dev
+	// dependencies are.
+	ExtensionRepos map[string][]string `json:"extension_repos,omitempty"`
+
 	// Graph is the dependency graph for advanced queries.
 	// Use this for bazel mod graph/explain equivalent functionality.
-	// Supports: Explain(), Path(), AllPaths(), ToJSON(), ToDOT(), ToText()
+	// Supports: Explain(), Path(), AllPaths(), ToJSON(), ToDOT(), ToMermaid(), ToText()
 	Graph *graph.Graph `json:"-"`
+
+	// root is a snapshot of the parsed root module, taken before any
+	// MODULE.tools injection. Used to answer questions about what the root
+	// declared and to re-resolve hypothetical variants of it.
+	root *ModuleInfo
 }
 
 // ModuleToResolve represents a module selected by dependency resolution.
@@ -188,6 +247,12 @@ type ModuleToResolve struct {
 	// Empty if not yanked.
 	YankReason string `json:"yank_reason,omitempty"`
 
+	// YankAllowed indicates the version is yanked but permitted by
+	// AllowYankedVersions. Yanked stays false, so no warning or error is
+	// raised, but YankReason is set and ToLockfile records the version in
+	// selectedYankedVersions as Bazel does.
+	YankAllowed bool `json:"yank_allowed,omitempty"`
+
 	// IsDeprecated indicates the module is deprecated.
 	// Check DeprecationReason for details.
 	IsDeprecated bool `json:"deprecated,omitempty"`
@@ -195,6 +260,11 @@ type ModuleToResolve struct {
 	// DeprecationReason explains why the module is deprecated.
 	DeprecationReason string `json:"deprecation_reason,omitempty"`
 
+	// License is the license declared in the module's registry metadata.json,
+	// typically an SPDX identifier. It is populated when FetchLicenses is
+	// enabled and is empty if the metadata declares none.
+	License string `json:"license,omitempty"`
+
 	// BazelCompatibility contains the bazel_compatibility constraints for this module.
 	// Empty if no constraints were declared.
 	BazelCompatibility []string `json:"bazel_compatibility,omitempty"`
@@ -210,6 +280,13 @@ type ModuleToResolve struct {
 	// It is populated when TraceRegistryFiles is enabled.
 	// It can describe archive, git_repository, or local_path sources.
 	Source *SourceInfo `json:"source,omitempty"`
+
+	// RegistryFileHashes holds the entries of ResolutionList.RegistryFileHashes
+	// for this module's own registry files, such as its MODULE.bazel and
+	// source.json, in the same format: SHA-256 hex digests, as Bazel writes
+	// them to the lockfile, and nil for a registry that did not have the
+	// file. It is populated when TraceRegistryFiles is enabled.
+	RegistryFileHashes map[string]*string `json:"registry_file_hashes,omitempty"`
 }
 
 // SourceInfo describes how to fetch a module's source code.
@@ -313,6 +390,150 @@ func (r *ResolutionList) HasModule(name string) bool {
 	return r.Module(name) != nil
 }
 
+// DevOnlyModules returns the names of modules that are reachable from the root
+// only through dev_dependency edges, i.e. the modules that would disappear if
+// resolution excluded dev dependencies. Unlike ModuleToResolve.DevDependency,
+// which only describes how a module itself was requested, this accounts for
+// the transitive dependencies of dev dependencies. Names are sorted.
+//
+// The root's declared dependencies are used when available; for resolutions
+// decoded from JSON, the DevDependency flag of the root's direct dependencies
+// is used instead.
+func (r *ResolutionList) DevOnlyModules() []string {
+	declared := make(map[string]bool)
+	if r.root != nil {
+		for _, dep := range r.root.Dependencies {
+			declared[dep.Name] = dep.DevDependency
+		}
+	}
+
+	index := make(map[string]*ModuleToResolve, len(r.Modules))
+	var queue []string
+	for i := range r.Modules {
+		m := &r.Modules[i]
+		index[m.Name] = m
+		if !slices.Contains(m.RequiredBy, "<root>") {
+			continue
+		}
+		dev, ok := declared[m.Name]
+		if !ok {
+			// Not declared by the root (e.g. MODULE.tools dependencies, or
+			// no root information): fall back to the resolved flag.
+			dev = r.root == nil && m.DevDependency
+		}
+		if !dev {
+			queue = append(queue, m.Name)
+		}
+	}
+
+	production := make(map[string]bool)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if production[name] {
+			continue
+		}
+		production[name] = true
+		if m := index[name]; m != nil {
+			queue = append(queue, m.Dependencies...)
+		}
+	}
+
+	var devOnly []string
+	for _, m := range r.Modules {
+		if !production[m.Name] {
+			devOnly = append(devOnly, m.Name)
+		}
+	}
+	slices.Sort(devOnly)
+	return devOnly
+}
+
+// VendorOrder returns the resolved modules in dependency order: every module
+// comes after all of its dependencies, so sources can be fetched or built
+// incrementally. Modules that are otherwise unordered are sorted by name.
+//
+// Returns a *graph.CycleError if a dependency cycle prevents a total order.
+func (r *ResolutionList) VendorOrder() ([]ModuleToResolve, error) {
+	g := r.Graph
+	if g == nil {
+		// Resolutions decoded from JSON carry no graph; rebuild it from the
+		// module list. The root only contributes outgoing edges.
+		root := r.root
+		if root == nil {
+			root = &ModuleInfo{}
+		}
+		g = buildGraph(root, r.Modules)
+	}
+
+	keys, err := g.TopologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[graph.ModuleKey]ModuleToResolve, len(r.Modules))
+	for _, m := range r.Modules {
+		byKey[graph.ModuleKey{Name: m.Name, Version: m.Version}] = m
+	}
+	order := make([]ModuleToResolve, 0, len(r.Modules))
+	for _, key := range keys {
+		if m, ok := byKey[key]; ok {
+			order = append(order, m)
+		}
+	}
+	return order, nil
+}
+
+// RootRequestedVersion returns the version the root module's bazel_dep
+// declared for the named module. This is the requested version, which may
+// differ from the resolved version when another module requires a higher one.
+//
+// Returns ("", false) if the root module does not directly depend on name.
+func (r *ResolutionList) RootRequestedVersion(name string) (string, bool) {
+	if r.root == nil {
+		return "", false
+	}
+	for _, dep := range r.root.Dependencies {
+		if dep.Name == name {
+			return dep.Version, true
+		}
+	}
+	return "", false
+}
+
+// ValidateRootDeclarations re-parses the root MODULE.bazel content and checks
+// that every declared bazel_dep made it into the resolution at a compatible
+// version, i.e. at least the declared one. It returns one message per
+// declaration that did not, such as a dev_dependency dropped because dev deps
+// were not included.
+//
+// Deps declared with repo_name = None are skipped, as Bazel does not require
+// them to be resolved. Returns an error only if content cannot be parsed.
+func (r *ResolutionList) ValidateRootDeclarations(content string) ([]string, error) {
+	root, err := ParseModuleContent(content)
+	if err != nil {
+		return nil, fmt.Errorf("parse root module: %w", err)
+	}
+
+	var missing []string
+	for _, dep := range root.Dependencies {
+		declared := dep.Name + "@" + dep.Version
+		module := r.Module(dep.Name)
+		if module == nil {
+			reason := "not in resolution"
+			if dep.DevDependency {
+				reason += " (dev_dependency)"
+			}
+			missing = append(missing, declared+": "+reason)
+			continue
+		}
+		if dep.Version != "" && module.Version != "" && version.Compare(module.Version, dep.Version) < 0 {
+			missing = append(missing, fmt.Sprintf("%s: resolved to older version %s", declared, module.Version))
+		}
+	}
+	return missing, nil
+}
+
 // ResolutionSummary provides statistics about the dependency resolution result.
 type ResolutionSummary struct {
 	// TotalModules is the total count of resolved modules.
@@ -327,16 +548,102 @@ type ResolutionSummary struct {
 	// YankedModules is the count of modules with yanked versions.
 	YankedModules int `json:"yanked_modules,omitempty"`
 
+	// YankedVersions is an audit trail of the yanked versions resolution
+	// met, sorted by module and version: every selected module whose version
+	// is yanked (populated when CheckYanked is enabled) and, when
+	// SubstituteYanked is enabled, every yanked request it replaced.
+	YankedVersions []YankedVersionInfo `json:"yanked_versions,omitempty"`
+
 	// DeprecatedModules is the count of deprecated modules.
 	DeprecatedModules int `json:"deprecated_modules,omitempty"`
 
 	// IncompatibleModules is the count of modules incompatible with the target Bazel version.
 	IncompatibleModules int `json:"incompatible_modules,omitempty"`
 
-	// FieldWarnings lists warnings about bzlmod fields that aren't supported
-	// in the target Bazel version. These warnings are informational and don't
-	// block resolution. Examples include mirror_urls (requires 7.7.0+) or
-	// max_compatibility_level (requires 7.0.0+).
+	// MaxDepth is the largest depth of any resolved module, i.e. the length of
+	// the longest shortest path from the root.
+	MaxDepth int `json:"max_depth"`
+
+	// MaxBreadth is the largest number of modules at any single depth level.
+	// Together with MaxDepth it distinguishes deep chains from wide fan-out.
+	MaxBreadth int `json:"max_breadth"`
+
+	// SkippedTransitiveDevDeps lists dev_dependency bazel_deps declared by
+	// selected non-root modules, as "module@version -> dep@version". Bazel
+	// ignores these regardless of whether the root's dev deps are included,
+	// so they never take part in resolution.
+	SkippedTransitiveDevDeps []string `json:"skipped_transitive_dev_deps,omitempty"`
+
+	// IgnoredNonRootOverrides lists overrides declared by selected non-root
+	// modules, as "module@version: <type>_override(<target>)". Bazel only
+	// applies overrides from the root module, so these had no effect.
+	IgnoredNonRootOverrides []string `json:"ignored_non_root_overrides,omitempty"`
+
+	// RegistryInconsistencies lists module versions that MVS would have
+	// selected but whose MODULE.bazel is missing although the registry's
+	// metadata.json lists them, as "module@version". They were dropped from
+	// resolution like other missing transitive dependencies; a missing direct
+	// dependency of the root fails with a RegistryInconsistencyError instead.
+	RegistryInconsistencies []string `json:"registry_inconsistencies,omitempty"`
+
+	// ContradictoryOverrides lists modules selected by a root override whose
+	// own MODULE.bazel overrides them back to a different version or source,
+	// as "module@version: <type>_override[ to version <v>]". Like other
+	// non-root overrides these are ignored, but usually signal a root
+	// override pointing at the wrong registry or version.
+	ContradictoryOverrides []string `json:"contradictory_overrides,omitempty"`
+
+	// PreOneMinorBumps lists modules whose selected 0.x version crosses a
+	// minor boundary relative to a requester's version. Only populated when
+	// WarnPreOneMinorBumps is enabled; see that option for details.
+	PreOneMinorBumps []string `json:"pre_one_minor_bumps,omitempty"`
+
+	// DeprecationWarnings lists a warning for each resolved module that its
+	// registry's metadata.json marks deprecated, naming the module and the
+	// registry's reason. Only populated when WarnDeprecated is enabled.
+	DeprecationWarnings []string `json:"deprecation_warnings,omitempty"`
+
+	// Partial is true if SoftTimeBudget ran out before every module was
+	// fetched. Versions are selected from the requests discovered so far, so
+	// the result is consistent but may miss dependencies of unfetched modules.
+	Partial bool `json:"partial,omitempty"`
+
+	// UnexploredModules lists the selected modules whose MODULE.bazel was not
+	// fetched because SoftTimeBudget ran out; their dependencies are unknown.
+	UnexploredModules []string `json:"unexplored_modules,omitempty"`
+
+	// CompatLevelAdvanced lists root dependencies whose selected version has
+	// a higher compatibility_level than the version the root requested, i.e.
+	// a transitive requester moved a direct dependency to a new compatibility
+	// line. These are potentially breaking but do not fail resolution; each
+	// one also appears in Warnings.
+	CompatLevelAdvanced []CompatLevelAdvance `json:"compat_level_advanced,omitempty"`
+
+	// AliasedDependencies lists the bazel_deps rewritten by ModuleAliases, as
+	// "module@version: old -> new", or "<root>: old -> new" for the root module.
+	AliasedDependencies []string `json:"aliased_dependencies,omitempty"`
+
+	// ExcludedModules lists the ExcludeModules names that took effect: some
+	// selected module, or the root, depended on them and the edge was
+	// dropped.
+	ExcludedModules []string `json:"excluded_modules,omitempty"`
+
+	// UnusedExclusions lists the ExcludeModules names that were no-ops,
+	// because nothing in the resolved graph depended on them.
+	UnusedExclusions []string `json:"unused_exclusions,omitempty"`
+
+	// UnprovidedUseRepos lists the root module's use_repo imports, as
+	// "<proxy>: <repo>", that no tag of their extension declares. Extensions
+	// are not evaluated, so entries are only likely unused: the check assumes
+	// tags create repos named after their name attribute, and skips extensions
+	// with unnamed tags.
+	UnprovidedUseRepos []string `json:"unprovided_use_repos,omitempty"`
+
+	// FieldWarnings lists warnings about bzlmod fields and directives the root
+	// module uses that aren't supported in the target Bazel version, each
+	// naming the Bazel version it requires. These warnings are informational
+	// and don't block resolution. Examples include include() (requires
+	// 7.2.0+) or max_compatibility_level (requires 7.0.0+).
 	FieldWarnings []string `json:"field_warnings,omitempty"`
 }
 
@@ -369,7 +676,9 @@ const (
 	// DirectDepsWarn includes warnings when direct deps don't match resolved versions.
 	DirectDepsWarn
 
-	// DirectDepsError fails resolution if direct deps don't match resolved versions.
+	// DirectDepsError fails resolution with a *DirectDepsMismatchError listing
+	// every direct dep that doesn't match its resolved version, like Bazel's
+	// --check_direct_dependencies=error.
 	DirectDepsError
 )
 
@@ -432,6 +741,10 @@ const (
 
 	// ProgressModuleFetchEnd is emitted when fetching a module completes.
 	ProgressModuleFetchEnd ProgressEventType = "module_fetch_end"
+
+	// ProgressVersionSelected is emitted once per module when MVS selects
+	// its version, with the requested versions it chose from in Candidates.
+	ProgressVersionSelected ProgressEventType = "version_selected"
 )
 
 // ProgressEvent contains information about resolution progress.
@@ -439,12 +752,19 @@ type ProgressEvent struct {
 	// Type identifies the event type.
 	Type ProgressEventType `json:"type"`
 
-	// Module is the module name (for module_fetch_* events).
+	// Module is the module name (for module_fetch_* and version_selected
+	// events).
 	Module string `json:"module,omitempty"`
 
-	// Version is the module version (for module_fetch_* events).
+	// Version is the module version (for module_fetch_* events), or the
+	// selected version (for version_selected events).
 	Version string `json:"version,omitempty"`
 
+	// Candidates lists the versions of Module requested in the dependency
+	// graph after overrides are applied, lowest first (for version_selected
+	// events). The selected Version is the highest.
+	Candidates []string `json:"candidates,omitempty"`
+
 	// Message provides additional context about the event.
 	Message string `json:"message,omitempty"`
 }
@@ -465,6 +785,8 @@ type ResolutionOptions struct {
 
 	// AllowYankedVersions lists specific module@version pairs that are allowed
 	// even if yanked. Use "all" as special keyword to allow all yanked versions.
+	// Allowed versions are never substituted by SubstituteYanked, raise no
+	// warning or error, and are marked YankAllowed instead of Yanked.
 	// Format: []string{"module@version", "other@1.0.0"} or []string{"all"}
 	// Mirrors Bazel's --allow_yanked_versions flag.
 	AllowYankedVersions []string
@@ -473,6 +795,19 @@ type ResolutionOptions struct {
 	// Default is false.
 	WarnDeprecated bool
 
+	// WarnPreOneMinorBumps enables advisory warnings when MVS selects a 0.x
+	// version with a different minor than a requester asked for (e.g. 0.1.0
+	// bumped to 0.2.0). Bazel treats such bumps as compatible, but ecosystems
+	// following SemVer's 0.x convention often treat them as breaking.
+	// Default is false.
+	WarnPreOneMinorBumps bool
+
+	// FetchLicenses populates ModuleToResolve.License from each module's
+	// registry metadata. Metadata is fetched once per module and shared with
+	// the yanked and deprecated checks.
+	// Default is false.
+	FetchLicenses bool
+
 	// TraceRegistryFiles enables Bazel-style registry tracing.
 	// When enabled, ResolutionList.RegistryFileHashes is populated with the
 	// MODULE.bazel and source.json files touched during resolution, and
@@ -518,6 +853,15 @@ type ResolutionOptions struct {
 	// Airgap:  []string{"file:///opt/bazel-registry"}
 	Registries []string
 
+	// ModuleRegistries pins modules to a single registry of Registries. Keys
+	// are module names or path.Match globs, such as "corp_*"; values are
+	// registry URLs. A pinned module is fetched from its registry only, and
+	// a miss there fails resolution instead of falling back to the next
+	// registry, so an internal module name can never be pulled from a public
+	// registry (dependency confusion). If several patterns match, the
+	// longest wins.
+	ModuleRegistries map[string]string
+
 	// VendorDir specifies a directory containing vendored module files.
 	// When set, modules are first looked up in this directory before
 	// checking registries. This enables offline/airgap workflows.
@@ -547,6 +891,120 @@ type ResolutionOptions struct {
 	// Example: 30 * time.Second for slower networks
 	Timeout time.Duration
 
+	// SoftTimeBudget bounds the wall-clock time spent discovering modules.
+	// Once it elapses, no new module fetches are started, in-flight fetches
+	// are allowed to finish, and MVS runs over the requests discovered so far.
+	// The result is flagged with Summary.Partial. Unlike a context deadline,
+	// this degrades gracefully instead of failing resolution.
+	// Zero or negative values disable the budget.
+	SoftTimeBudget time.Duration
+
+	// TotalTimeout bounds the wall-clock time of the whole resolution, every
+	// fetch included, by deriving a context with that deadline. When it runs
+	// out, resolution fails with a ResolutionTimeoutError reporting how many
+	// modules were resolved by then, rather than whichever fetch noticed the
+	// deadline first. Use SoftTimeBudget instead to get a partial result.
+	// Zero or negative values disable the limit.
+	TotalTimeout time.Duration
+
+	// RetryAttempts is how many attempts, in total, a registry request that
+	// fails with a 5xx response or a network error is given. Retries wait
+	// RetryBaseDelay, doubling after every attempt with jitter, and stop
+	// early when the context would expire before the next attempt. 4xx
+	// responses are never retried. The retries performed are reported in
+	// RegistryError.Retries.
+	// Values below 2 disable retries (the default).
+	RetryAttempts int
+
+	// RetryBaseDelay is the wait before the first retry; see RetryAttempts.
+	RetryBaseDelay time.Duration
+
+	// MaxConcurrentFetches caps how many MODULE.bazel files are fetched from
+	// the registry at once. Lower it to go easier on a registry or a CI
+	// network; raise it for large graphs on fast connections.
+	// Zero or negative values use the default of 5.
+	MaxConcurrentFetches int
+
+	// MaxDependencyDepth is the longest dependency chain, counting the root,
+	// that discovery follows before failing with MaxDepthExceededError. Lower
+	// it to fail fast on runaway chains; raise it for very deep graphs. A very
+	// high limit lets a pathological registry keep resolution busy for a long
+	// time before the error is reported.
+	// Zero or negative values use the default of 1000.
+	MaxDependencyDepth int
+
+	// Deterministic makes the result independent of fetch scheduling, so two
+	// resolutions of the same inputs serialize byte for byte identically, as
+	// golden tests need. Requesters (RequiredBy and the warnings naming them)
+	// are sorted before versions are selected, each module's Dependencies are
+	// sorted by name, and graph edges are sorted by module key.
+	//
+	// Without it, module order is still stable but requesters appear in the
+	// order their MODULE.bazel files happened to be fetched, and Dependencies
+	// keep the order of the bazel_dep declarations.
+	Deterministic bool
+
+	// FollowIncludes merges the MODULE.bazel segments the root module loads
+	// with include() into it, as Bazel does, following nested includes. Labels
+	// are resolved against the directory of the root MODULE.bazel, or the
+	// working directory when resolving content. Only files in the root
+	// module's repository whose names end in ".MODULE.bazel" can be included;
+	// a cycle fails with IncludeCycleError. Without it, include() is ignored
+	// and the segments' dependencies are missing from the result.
+	FollowIncludes bool
+
+	// ExcludeModules lists module names to resolve as if they did not exist,
+	// e.g. to test the effect of removing one. Dependency edges to them are
+	// dropped during discovery, so modules only reachable through them are
+	// pruned too. The root module's own bazel_deps cannot be excluded:
+	// resolution fails if one is named here. ResolutionSummary reports which
+	// exclusions took effect and which were no-ops.
+	ExcludeModules []string
+
+	// SeedModules maps "name@version" to module infos that are already
+	// parsed, e.g. from a monorepo checkout. Discovery uses a seeded module
+	// instead of fetching its MODULE.bazel from any registry, whatever
+	// overrides apply to it, so a fully seeded graph resolves without network
+	// access. Seeds only replace the module file: yanked and deprecation
+	// checks still consult registry metadata when enabled.
+	SeedModules map[string]*ModuleInfo
+
+	// Tracer, if set, receives a span for the resolution and a child span for
+	// every MODULE.bazel fetch, annotated with the module, whether a cache
+	// served it and the outcome. See Tracer.
+	Tracer Tracer
+
+	// ModuleAliases maps old module names to new ones, for registries that
+	// renamed a module (e.g. com_google_protobuf to protobuf) before every
+	// MODULE.bazel caught up. A bazel_dep on an old name, from the root or any
+	// dependency, is rewritten to the new name before fetching, so the module
+	// is fetched, selected and keyed in the graph under the new name. Each
+	// rewrite is listed in ResolutionSummary.AliasedDependencies. Overrides
+	// are not rewritten and must use the new name.
+	ModuleAliases map[string]string
+
+	// VersionPins maps module names to versions that are forced into the
+	// root module's direct dependencies, as if declared in its MODULE.bazel,
+	// without editing the file. A bazel_dep on a pinned name takes the
+	// pinned version; any other name is added as a new non-dev bazel_dep.
+	// Pins are requests like any bazel_dep, so selection can still pick a
+	// higher version another module asks for. Combined with Deterministic,
+	// resolving with and without pins previews a dependency bump in memory.
+	VersionPins map[string]string
+
+	// OnDependencyDiscovered is called for every module version discovery
+	// visits, before it is fetched, including the root's direct dependencies.
+	// requester is the "name@version" of the module that first asked for it,
+	// or "<root>". It is called exactly once per module version.
+	//
+	// Returning false rejects the dependency: resolution fails with a
+	// *DependencyRejectedError naming it and the requester. Returning an error
+	// aborts resolution with that error. This allows policy-driven or
+	// interactive vetting of dependencies as they are discovered.
+	//
+	// Like OnProgress, the callback may be called concurrently.
+	OnDependencyDiscovered func(name, version, requester string) (bool, error)
+
 	// OnProgress is called with progress updates during resolution.
 	// This can be used for logging, progress bars, or debugging.
 	//
@@ -608,8 +1066,8 @@ type ResolutionOptions struct {
 //
 // This interface enables persistent caching across resolutions. Common
 // implementations include file-based caches, Redis, memcached, or any
-// key-value store. The library does not provide a built-in implementation;
-// users should implement this interface based on their infrastructure.
+// key-value store. The cache package provides a file-based implementation,
+// cache.NewDiskCache; other backends implement this interface directly.
 //
 // # Thread Safety
 //
@@ -705,6 +1163,45 @@ func (e *YankedVersionsError) Error() string {
 	return sb.String()
 }
 
+// CompatLevelAdvance describes a root dependency whose selected version is on a
+// higher compatibility level than the version the root module requested.
+type CompatLevelAdvance struct {
+	// Module is the module name.
+	Module string `json:"module"`
+
+	// RequestedVersion is the version the root module requested.
+	RequestedVersion string `json:"requested_version"`
+
+	// RequestedCompatibilityLevel is the compatibility_level of RequestedVersion.
+	RequestedCompatibilityLevel int `json:"requested_compatibility_level"`
+
+	// SelectedVersion is the version selected by resolution.
+	SelectedVersion string `json:"selected_version"`
+
+	// SelectedCompatibilityLevel is the compatibility_level of SelectedVersion.
+	SelectedCompatibilityLevel int `json:"selected_compatibility_level"`
+}
+
+// YankedVersionInfo describes a yanked module version met during resolution.
+type YankedVersionInfo struct {
+	// Module is the module name.
+	Module string `json:"module"`
+
+	// Version is the yanked version.
+	Version string `json:"version"`
+
+	// Reason is the registry's explanation for yanking Version.
+	Reason string `json:"reason,omitempty"`
+
+	// Allowed is true if AllowYankedVersions permits Version, which was
+	// then selected without a warning.
+	Allowed bool `json:"allowed,omitempty"`
+
+	// SubstitutedWith is the version SubstituteYanked used instead of
+	// Version. Empty if Version was selected.
+	SubstitutedWith string `json:"substituted_with,omitempty"`
+}
+
 // DirectDepMismatch represents a mismatch between declared and resolved versions.
 type DirectDepMismatch struct {
 	// Name is the module name.
@@ -752,6 +1249,11 @@ type depRequest struct {
 
 	// RequiredBy lists the modules that made this request.
 	RequiredBy []string
+
+	// Prefetched marks a version fetched only because a
+	// multiple_version_override allows it. MVS ignores it until a module
+	// requests it.
+	Prefetched bool
 }
 
 // formatDepPath formats a dependency path for display.
@@ -775,13 +1277,130 @@ type MaxDepthExceededError struct {
 	Depth int
 	// MaxDepth is the maximum allowed depth.
 	MaxDepth int
-	// Path is the dependency path that exceeded the depth.
+	// Path is the dependency path that exceeded the depth, starting at "<root>".
 	Path []string
+	// Chain is Path with the root module named: the modules, as name@version,
+	// from the root to the one that exceeded the depth.
+	Chain []string
 }
 
+// maxDepthChainEnds is how many modules from each end of the chain
+// MaxDepthExceededError.Error shows before eliding the middle.
+const maxDepthChainEnds = 5
+
 func (e *MaxDepthExceededError) Error() string {
-	return fmt.Sprintf("maximum dependency depth exceeded: depth %d > max %d (path: %s)",
-		e.Depth, e.MaxDepth, formatDepPath(e.Path))
+	chain := e.Chain
+	if chain == nil {
+		chain = e.Path
+	}
+	if len(chain) > 2*maxDepthChainEnds {
+		elided := fmt.Sprintf("... %d more ...", len(chain)-2*maxDepthChainEnds)
+		chain = slices.Concat(chain[:maxDepthChainEnds], []string{elided}, chain[len(chain)-maxDepthChainEnds:])
+	}
+	return fmt.Sprintf("maximum dependency depth exceeded: depth %d > max %d (chain: %s)",
+		e.Depth, e.MaxDepth, formatDepPath(chain))
+}
+
+// IncludeCycleError is returned with ResolutionOptions.FollowIncludes when a
+// MODULE.bazel segment includes itself, directly or through other segments.
+type IncludeCycleError struct {
+	// Chain lists the labels from the first include of the repeated segment
+	// to its repeated include.
+	Chain []string
+}
+
+func (e *IncludeCycleError) Error() string {
+	return "include cycle: " + strings.Join(e.Chain, " -> ")
+}
+
+// ResolutionTimeoutError is returned when ResolutionOptions.TotalTimeout runs
+// out before resolution completes. It matches ErrResolutionTimeout with
+// errors.Is, and unwraps to the error the interrupted step returned.
+type ResolutionTimeoutError struct {
+	// Timeout is the TotalTimeout that ran out.
+	Timeout time.Duration
+	// ModulesResolved is how many module files had been fetched or read from
+	// a cache when the deadline hit.
+	ModulesResolved int
+	// Err is the error the interrupted step returned.
+	Err error
+}
+
+func (e *ResolutionTimeoutError) Error() string {
+	return fmt.Sprintf("resolution timed out after %s with %d modules resolved: %v", e.Timeout, e.ModulesResolved, e.Err)
+}
+
+func (e *ResolutionTimeoutError) Is(target error) bool {
+	return target == ErrResolutionTimeout
+}
+
+func (e *ResolutionTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// DependencyRejectedError is returned when OnDependencyDiscovered rejects a
+// module version.
+type DependencyRejectedError struct {
+	// Module and Version identify the rejected dependency.
+	Module  string
+	Version string
+	// Requester is the "name@version" of the module that asked for it, or "<root>".
+	Requester string
+}
+
+func (e *DependencyRejectedError) Error() string {
+	return fmt.Sprintf("dependency %s@%s requested by %s was rejected", e.Module, e.Version, e.Requester)
+}
+
+// RegistryInconsistencyError is returned when a registry's metadata.json lists
+// a version of a direct dependency of the root whose MODULE.bazel is missing.
+// This points at a broken registry rather than a dependency on a version that
+// does not exist. The same inconsistency in a transitive dependency is
+// reported in ResolutionSummary.RegistryInconsistencies instead.
+type RegistryInconsistencyError struct {
+	// Module and Version identify the version listed in metadata.json.
+	Module  string
+	Version string
+	// Err is the not-found error returned when fetching the MODULE.bazel.
+	Err error
+}
+
+func (e *RegistryInconsistencyError) Error() string {
+	return fmt.Sprintf("registry inconsistency: metadata lists %s@%s but its MODULE.bazel is missing",
+		e.Module, e.Version)
+}
+
+func (e *RegistryInconsistencyError) Unwrap() error {
+	return e.Err
+}
+
+// PinnedRegistryError is returned when a module pinned to a registry with
+// ResolutionOptions.ModuleRegistries cannot be fetched from that registry.
+// Unlike a plain not-found RegistryError, resolution never tolerates it, even
+// for transitive dependencies: falling back or dropping the module would
+// defeat the pin.
+type PinnedRegistryError struct {
+	// Module identifies the pinned module. Version is empty for metadata.json.
+	Module  string
+	Version string
+	// File is the registry file that was requested, e.g. "MODULE.bazel".
+	File string
+	// Registry is the URL of the registry the module is pinned to.
+	Registry string
+	// Err is the error returned by the pinned registry.
+	Err error
+}
+
+func (e *PinnedRegistryError) Error() string {
+	target := e.Module
+	if e.Version != "" {
+		target += "@" + e.Version
+	}
+	return fmt.Sprintf("%s for module %s not found in pinned registry %s: %v", e.File, target, e.Registry, e.Err)
+}
+
+func (e *PinnedRegistryError) Unwrap() error {
+	return e.Err
 }
 
 // BazelIncompatibilityError is returned when resolution selects modules that are
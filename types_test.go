@@ -2,8 +2,11 @@ package gobzlmod
 
 import (
 	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
+
+	"github.com/albertocavalcante/go-bzlmod/graph"
 )
 
 // TestModuleToResolve_Key tests the Key() method returns "name@version" format.
@@ -487,3 +490,58 @@ func BenchmarkDirectDepsMismatchError_Large(b *testing.B) {
 		_ = err.Error()
 	}
 }
+
+func TestResolutionList_VendorOrder(t *testing.T) {
+	list := &ResolutionList{
+		Modules: []ModuleToResolve{
+			{Name: "gazelle", Version: "0.38.0", Dependencies: []string{"rules_go", "skylib", "protobuf"}},
+			{Name: "platforms", Version: "0.0.10"},
+			{Name: "protobuf", Version: "29.0", Dependencies: []string{"skylib", "zlib"}},
+			{Name: "rules_go", Version: "0.50.1", Dependencies: []string{"platforms", "skylib"}},
+			{Name: "skylib", Version: "1.7.1", Dependencies: []string{"platforms"}},
+			{Name: "zlib", Version: "1.3.1"},
+		},
+	}
+
+	order, err := list.VendorOrder()
+	if err != nil {
+		t.Fatalf("VendorOrder() error = %v", err)
+	}
+	if len(order) != len(list.Modules) {
+		t.Fatalf("VendorOrder() returned %d modules, want %d", len(order), len(list.Modules))
+	}
+
+	position := make(map[string]int, len(order))
+	for i, m := range order {
+		position[m.Name] = i
+	}
+	for _, m := range order {
+		for _, dep := range m.Dependencies {
+			if position[dep] > position[m.Name] {
+				t.Errorf("%s placed before its dependency %s: %v", m.Name, dep, position)
+			}
+		}
+	}
+	if order[0].Name != "platforms" {
+		t.Errorf("order[0] = %s, want platforms", order[0].Name)
+	}
+}
+
+func TestResolutionList_VendorOrder_Cycle(t *testing.T) {
+	list := &ResolutionList{
+		Modules: []ModuleToResolve{
+			{Name: "a", Version: "1.0.0", Dependencies: []string{"b"}},
+			{Name: "b", Version: "1.0.0", Dependencies: []string{"a"}},
+			{Name: "c", Version: "1.0.0"},
+		},
+	}
+
+	_, err := list.VendorOrder()
+	var cycleErr *graph.CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("VendorOrder() error = %v, want *graph.CycleError", err)
+	}
+	if got := err.Error(); got != "dependency cycle: a@1.0.0 -> b@1.0.0 -> a@1.0.0" {
+		t.Errorf("error = %q", got)
+	}
+}
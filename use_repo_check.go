@@ -0,0 +1,126 @@
+package gobzlmod
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/albertocavalcante/go-bzlmod/internal/buildutil"
+	"github.com/albertocavalcante/go-bzlmod/third_party/buildtools/build"
+)
+
+// extensionProxyUsage collects what a MODULE.bazel file does with one
+// extension proxy (the result of a use_extension assignment).
+type extensionProxyUsage struct {
+	// tagNames are the name attributes of the proxy's tag calls.
+	tagNames []string
+
+	// unnamedTags is true if some tag call has no string name attribute, in
+	// which case the repos the extension creates cannot be inferred.
+	unnamedTags bool
+
+	// imports are the exported repo names passed to use_repo on the proxy.
+	imports []string
+}
+
+// findUnprovidedUseRepos returns the use_repo imports that no tag of their
+// extension appears to create, as "<proxy>: <repo>" in declaration order.
+//
+// Extensions are not evaluated, so this is a heuristic. Many tags create a
+// repo named by their name attribute (oci.pull, go_sdk.download,
+// http_archive-style tags), sometimes with platform-suffixed siblings
+// ("<name>_linux_amd64"). An extension is only checked when it has at least
+// one tag and every tag has a name; an import is flagged when it neither
+// equals a tag name nor starts with one followed by "_". Extensions whose
+// repos come from elsewhere (a go.mod file, a lockfile, defaults) are skipped,
+// and a flagged import may still be created by the extension implementation.
+func findUnprovidedUseRepos(f *build.File) []string {
+	proxies := make(map[string]*extensionProxyUsage)
+	var order []string
+
+	for _, stmt := range f.Stmt {
+		if assign, ok := stmt.(*build.AssignExpr); ok {
+			lhs, isIdent := assign.LHS.(*build.Ident)
+			rhs, isCall := assign.RHS.(*build.CallExpr)
+			if isIdent && isCall && buildutil.FuncName(rhs) == "use_extension" {
+				if _, seen := proxies[lhs.Name]; !seen {
+					order = append(order, lhs.Name)
+				}
+				proxies[lhs.Name] = &extensionProxyUsage{}
+			}
+			continue
+		}
+
+		call, ok := stmt.(*build.CallExpr)
+		if !ok {
+			continue
+		}
+
+		// Tag call: proxy.tag(...)
+		if dot, ok := call.X.(*build.DotExpr); ok {
+			proxy, isIdent := dot.X.(*build.Ident)
+			if !isIdent || proxies[proxy.Name] == nil {
+				continue
+			}
+			usage := proxies[proxy.Name]
+			if name := buildutil.String(call, "name"); name != "" {
+				usage.tagNames = append(usage.tagNames, name)
+			} else {
+				usage.unnamedTags = true
+			}
+			continue
+		}
+
+		if buildutil.FuncName(call) != "use_repo" || len(call.List) == 0 {
+			continue
+		}
+		proxy, ok := call.List[0].(*build.Ident)
+		if !ok || proxies[proxy.Name] == nil {
+			continue
+		}
+		usage := proxies[proxy.Name]
+		for _, arg := range call.List[1:] {
+			switch arg := arg.(type) {
+			case *build.StringExpr:
+				usage.imports = append(usage.imports, arg.Value)
+			case *build.AssignExpr:
+				// use_repo(ext, local = "exported") imports the extension's
+				// "exported" repo, so that is the name the tags must provide.
+				if exported, ok := arg.RHS.(*build.StringExpr); ok {
+					usage.imports = append(usage.imports, exported.Value)
+				}
+			}
+		}
+	}
+
+	var unprovided []string
+	for _, proxy := range order {
+		usage := proxies[proxy]
+		if len(usage.tagNames) == 0 || usage.unnamedTags {
+			continue
+		}
+		for _, repo := range usage.imports {
+			if !providedByTags(repo, usage.tagNames) {
+				unprovided = append(unprovided, proxy+": "+repo)
+			}
+		}
+	}
+	return unprovided
+}
+
+// providedByTags reports whether repo equals a tag name or is a "_"-suffixed
+// variant of one.
+func providedByTags(repo string, tagNames []string) bool {
+	for _, name := range tagNames {
+		if repo == name || strings.HasPrefix(repo, name+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// unprovidedUseRepoWarning formats an UnprovidedUseRepos entry as a warning.
+func unprovidedUseRepoWarning(entry string) string {
+	proxy, repo, _ := strings.Cut(entry, ": ")
+	return fmt.Sprintf("use_repo(%s, %q): no %s tag declares a repo with this name; the import may be unused (extensions are not evaluated, so this is a best-effort check)",
+		proxy, repo, proxy)
+}
@@ -0,0 +1,86 @@
+package gobzlmod
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseModuleContent_UnprovidedUseRepos(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name: "import matches no tag",
+			content: `module(name = "root")
+oci = use_extension("@rules_oci//oci:extensions.bzl", "oci")
+oci.pull(name = "distroless_base", image = "gcr.io/distroless/base")
+use_repo(oci, "distroless_base", "distroless_base_linux_amd64", "distroless_static")`,
+			want: []string{"oci: distroless_static"},
+		},
+		{
+			name: "keyword import checks the exported name",
+			content: `module(name = "root")
+oci = use_extension("@rules_oci//oci:extensions.bzl", "oci")
+oci.pull(name = "base", image = "gcr.io/distroless/base")
+use_repo(oci, base = "base", static = "static")`,
+			want: []string{"oci: static"},
+		},
+		{
+			name: "extension with unnamed tag is skipped",
+			content: `module(name = "root")
+go_deps = use_extension("@gazelle//:extensions.bzl", "go_deps")
+go_deps.from_file(go_mod = "//:go.mod")
+go_deps.module(name = "org_golang_x_tools", path = "golang.org/x/tools", sum = "h1:", version = "v0.1.0")
+use_repo(go_deps, "com_github_google_uuid")`,
+		},
+		{
+			name: "extension without tags is skipped",
+			content: `module(name = "root")
+go_sdk = use_extension("@rules_go//go:extensions.bzl", "go_sdk")
+use_repo(go_sdk, "go_toolchains")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := ParseModuleContent(tt.content)
+			if err != nil {
+				t.Fatalf("ParseModuleContent() error = %v", err)
+			}
+			if !slices.Equal(info.UnprovidedUseRepos, tt.want) {
+				t.Errorf("UnprovidedUseRepos = %v, want %v", info.UnprovidedUseRepos, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve_UnprovidedUseRepoWarnings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+oci = use_extension("@rules_oci//oci:extensions.bzl", "oci")
+oci.pull(name = "distroless_base", image = "gcr.io/distroless/base")
+use_repo(oci, "distroless_base", "distroless_java")`
+
+	list, err := Resolve(context.Background(), ContentSource(content), WithRegistries(server.URL))
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	want := []string{"oci: distroless_java"}
+	if !slices.Equal(list.Summary.UnprovidedUseRepos, want) {
+		t.Errorf("Summary.UnprovidedUseRepos = %v, want %v", list.Summary.UnprovidedUseRepos, want)
+	}
+	if len(list.Warnings) != 1 || !strings.Contains(list.Warnings[0], `use_repo(oci, "distroless_java")`) {
+		t.Errorf("Warnings = %v, want one warning about distroless_java", list.Warnings)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -267,3 +268,85 @@ func isYankedError(err error, target **YankedVersionsError) bool {
 	}
 	return false
 }
+
+func TestResolve_SummaryYankedVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/modules/app/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "app", version = "1.0.0")
+bazel_dep(name = "lib", version = "1.0.0")`)
+		case "/modules/lib/1.0.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "lib", version = "1.0.0")`)
+		case "/modules/lib/1.1.0/MODULE.bazel":
+			fmt.Fprint(w, `module(name = "lib", version = "1.1.0")`)
+		case "/modules/app/metadata.json":
+			fmt.Fprint(w, `{"versions": ["1.0.0"], "yanked_versions": {"1.0.0": "leaks credentials"}}`)
+		case "/modules/lib/metadata.json":
+			fmt.Fprint(w, `{"versions": ["1.0.0", "1.1.0"], "yanked_versions": {"1.0.0": "data race"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	content := `module(name = "root", version = "1.0.0")
+bazel_dep(name = "app", version = "1.0.0")`
+
+	t.Run("selected yanked versions", func(t *testing.T) {
+		list, err := Resolve(context.Background(), ContentSource(content),
+			WithRegistries(server.URL), WithYankedCheck(true))
+		if err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		want := []YankedVersionInfo{
+			{Module: "app", Version: "1.0.0", Reason: "leaks credentials"},
+			{Module: "lib", Version: "1.0.0", Reason: "data race"},
+		}
+		if !slices.Equal(list.Summary.YankedVersions, want) {
+			t.Errorf("YankedVersions = %+v, want %+v", list.Summary.YankedVersions, want)
+		}
+	})
+
+	t.Run("allowed yanked versions are kept and locked", func(t *testing.T) {
+		list, err := Resolve(context.Background(), ContentSource(content),
+			WithRegistries(server.URL), WithYankedCheck(true), WithYankedBehavior(YankedVersionError),
+			WithSubstituteYanked(true), WithAllowedYankedVersions("app@1.0.0", "lib@1.0.0"))
+		if err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		lib := list.Module("lib")
+		if lib.Version != "1.0.0" || lib.Yanked || !lib.YankAllowed || lib.YankReason != "data race" {
+			t.Errorf("lib = %+v, want allowed yanked 1.0.0 kept", lib)
+		}
+		if len(list.Warnings) != 0 {
+			t.Errorf("Warnings = %v, want none", list.Warnings)
+		}
+		want := []YankedVersionInfo{
+			{Module: "app", Version: "1.0.0", Reason: "leaks credentials", Allowed: true},
+			{Module: "lib", Version: "1.0.0", Reason: "data race", Allowed: true},
+		}
+		if !slices.Equal(list.Summary.YankedVersions, want) {
+			t.Errorf("YankedVersions = %+v, want %+v", list.Summary.YankedVersions, want)
+		}
+		locked := list.ToLockfile().SelectedYankedVersions
+		if locked["lib@1.0.0"] != "data race" || locked["app@1.0.0"] != "leaks credentials" {
+			t.Errorf("selectedYankedVersions = %v, want app@1.0.0 and lib@1.0.0", locked)
+		}
+	})
+
+	t.Run("substitutions are recorded", func(t *testing.T) {
+		list, err := Resolve(context.Background(), ContentSource(content),
+			WithRegistries(server.URL), WithYankedCheck(true), WithSubstituteYanked(true))
+		if err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		// app has no replacement, so it is selected yanked; lib is substituted.
+		want := []YankedVersionInfo{
+			{Module: "app", Version: "1.0.0", Reason: "leaks credentials"},
+			{Module: "lib", Version: "1.0.0", Reason: "data race", SubstitutedWith: "1.1.0"},
+		}
+		if !slices.Equal(list.Summary.YankedVersions, want) {
+			t.Errorf("YankedVersions = %+v, want %+v", list.Summary.YankedVersions, want)
+		}
+	})
+}